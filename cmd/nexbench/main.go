@@ -0,0 +1,111 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+// Command nexbench replays cache traces or synthetic workloads against
+// LRUCache setups and reports hit ratio, throughput, latency and
+// allocations, to choose capacity and eviction policy from data.
+//
+//	nexbench -trace OLTP.lis -capacity 1000,5000,10000 -policy lru,sieve
+//	nexbench -zipf 1.1 -keys 100000 -ops 5000000 -capacity 10000
+//
+// Every combination of -policy and -capacity is replayed on a fresh
+// cache. Traces are read in the ARC trace format by default; -format keys
+// reads one key per line.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func main() {
+
+	log.SetFlags(0)
+	log.SetPrefix("nexbench: ")
+
+	trace := flag.String("trace", "", "trace file to replay")
+	format := flag.String("format", "arc", "trace format: arc or keys")
+	limit := flag.Int("limit", 0, "replay at most this many accesses of the trace (0 = all)")
+	zipf := flag.Float64("zipf", 0, "generate a Zipfian workload with this exponent (> 1) instead of a trace")
+	keys := flag.Int("keys", 100000, "distinct keys of the Zipfian workload")
+	ops := flag.Int("ops", 1000000, "accesses of the Zipfian workload")
+	seed := flag.Int64("seed", 1, "random seed of the Zipfian workload")
+	capacities := flag.String("capacity", "1000,10000", "comma-separated cache capacities")
+	policies := flag.String("policy", "lru,sieve", "comma-separated eviction policies: lru, sieve")
+	ttl := flag.Duration("ttl", 24*time.Hour, "TTL of the cached entries")
+	goroutines := flag.Int("goroutines", 1, "goroutines replaying the workload concurrently")
+	flag.Parse()
+
+	w, err := load(*trace, *format, *limit, *zipf, *keys, *ops, *seed)
+	if err != nil {
+		log.Fatal(err)
+	}
+	configs, err := parseConfigs(*policies, *capacities, *ttl)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *goroutines < 1 {
+		log.Fatal("-goroutines must be at least 1")
+	}
+
+	fmt.Printf("workload %s: %d accesses, %d distinct keys, %d goroutine(s)\n\n",
+		w.name, len(w.keys), w.distinct(), *goroutines)
+
+	// Fixed columns instead of a tabwriter, so each row is printed as
+	// soon as its replay is done.
+	const row = "%-6s %10s %10s %12s %10s %10s %10s %10s\n"
+	fmt.Printf(row, "policy", "capacity", "hit ratio", "ops/s", "p50", "p99", "allocs/op", "B/op")
+	for _, cfg := range configs {
+		r := replay(w, cfg, *goroutines)
+		fmt.Printf(row, r.policy,
+			strconv.Itoa(r.capacity),
+			fmt.Sprintf("%.2f%%", 100*r.hitRatio),
+			fmt.Sprintf("%.0f", r.opsPerSec()),
+			r.p50, r.p99,
+			fmt.Sprintf("%.2f", r.allocsPerOp),
+			fmt.Sprintf("%.1f", r.bytesPerOp))
+	}
+
+}
+
+// load reads the trace or generates the Zipfian workload.
+func load(trace, format string, limit int, zipf float64, keys, ops int, seed int64) (*workload, error) {
+
+	switch {
+	case trace != "" && zipf != 0:
+		return nil, errors.New("use either -trace or -zipf")
+	case trace != "":
+		return readTrace(trace, format, limit)
+	case zipf != 0:
+		return zipfWorkload(zipf, keys, ops, seed)
+	}
+	return nil, errors.New("no workload: use -trace FILE or -zipf S")
+
+}
+
+// parseConfigs returns every combination of the given policies and
+// capacities.
+func parseConfigs(policies, capacities string, ttl time.Duration) ([]config, error) {
+
+	var configs []config
+	for _, policy := range strings.Split(policies, ",") {
+		policy = strings.TrimSpace(policy)
+		if policy != "lru" && policy != "sieve" {
+			return nil, fmt.Errorf("unknown policy %q (lru or sieve)", policy)
+		}
+		for _, field := range strings.Split(capacities, ",") {
+			capacity, err := strconv.Atoi(strings.TrimSpace(field))
+			if err != nil || capacity < 1 {
+				return nil, fmt.Errorf("invalid capacity %q", field)
+			}
+			configs = append(configs, config{policy: policy, capacity: capacity, ttl: ttl})
+		}
+	}
+	return configs, nil
+
+}
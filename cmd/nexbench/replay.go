@@ -0,0 +1,124 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/georghagn/nexcache/lrucache"
+)
+
+// ---------------------- Replay ----------------------
+
+// maxSamples bounds the latency samples per run; longer workloads sample
+// every n-th access.
+const maxSamples = 1 << 20
+
+// config is one cache setup to replay a workload against.
+type config struct {
+	policy   string
+	capacity int
+	ttl      time.Duration
+}
+
+// result is the outcome of one replay.
+type result struct {
+	config
+	ops      int
+	hitRatio float64
+	elapsed  time.Duration
+	p50, p99 time.Duration
+
+	allocsPerOp float64
+	bytesPerOp  float64
+}
+
+func (r result) opsPerSec() float64 {
+	return float64(r.ops) / r.elapsed.Seconds()
+}
+
+// replay runs w against a new cache: every access is a Get, and a miss is
+// followed by a Set, as a read-through cache would do. With several
+// goroutines, goroutine i replays the accesses i, i+n, i+2n and so on, so
+// the hit ratio varies slightly between runs. Latencies include the two
+// clock reads per access.
+func replay(w *workload, cfg config, goroutines int) result {
+
+	policy := lrucache.PolicyLRU
+	if cfg.policy == "sieve" {
+		policy = lrucache.PolicySIEVE
+	}
+	cache := lrucache.New(cfg.capacity, cfg.ttl, time.Hour, lrucache.WithEvictionPolicy(policy))
+	defer cache.Close()
+
+	every := (len(w.keys) + maxSamples - 1) / maxSamples
+	samples := make([][]time.Duration, goroutines)
+	hits := make([]int, goroutines)
+	for i := range samples {
+		samples[i] = make([]time.Duration, 0, maxSamples/goroutines+1)
+	}
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			n := 0
+			for i := g; i < len(w.keys); i += goroutines {
+				key := w.keys[i]
+				begin := time.Now()
+				if _, found := cache.Get(key); found {
+					n++
+				} else {
+					cache.Set(key, true)
+				}
+				if i%every == 0 {
+					samples[g] = append(samples[g], time.Since(begin))
+				}
+			}
+			hits[g] = n
+		}(g)
+	}
+	wg.Wait()
+
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+
+	var all []time.Duration
+	total := 0
+	for g := range samples {
+		all = append(all, samples[g]...)
+		total += hits[g]
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i] < all[j] })
+
+	ops := float64(len(w.keys))
+	return result{
+		config:      cfg,
+		ops:         len(w.keys),
+		hitRatio:    float64(total) / ops,
+		elapsed:     elapsed,
+		p50:         percentile(all, 0.50),
+		p99:         percentile(all, 0.99),
+		allocsPerOp: float64(after.Mallocs-before.Mallocs) / ops,
+		bytesPerOp:  float64(after.TotalAlloc-before.TotalAlloc) / ops,
+	}
+
+}
+
+// percentile returns the q-th quantile of sorted.
+func percentile(sorted []time.Duration, q float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	return sorted[int(q*float64(len(sorted)-1))]
+}
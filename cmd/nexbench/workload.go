@@ -0,0 +1,129 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ---------------------- Workloads ----------------------
+
+// A workload is the sequence of keys that is replayed. It is built before
+// the measurement, so reading or generating it does not count. Repeated
+// keys share one string.
+type workload struct {
+	name string
+	keys []string
+}
+
+// interner returns one shared string per distinct key.
+type interner map[string]string
+
+func (in interner) intern(key string) string {
+	if shared, ok := in[key]; ok {
+		return shared
+	}
+	in[key] = key
+	return key
+}
+
+// readTrace reads a trace file. Format "arc" is the format of the ARC
+// traces (Megiddo and Modha): each line is "start count ignored request"
+// and accesses the blocks start to start+count-1. Format "keys" has one
+// key per line (the first field); empty lines and lines starting with #
+// are skipped.
+func readTrace(name, format string, limit int) (*workload, error) {
+
+	if format != "arc" && format != "keys" {
+		return nil, fmt.Errorf("unknown trace format %q (arc or keys)", format)
+	}
+
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	w := &workload{name: name}
+	seen := interner{}
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		if format == "keys" {
+			w.keys = append(w.keys, seen.intern(fields[0]))
+		} else {
+			if len(fields) < 2 {
+				return nil, fmt.Errorf("%s:%d: want \"start count ...\"", name, line)
+			}
+			start, err := strconv.ParseUint(fields[0], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", name, line, err)
+			}
+			count, err := strconv.ParseUint(fields[1], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", name, line, err)
+			}
+			for block := start; block < start+count && (limit <= 0 || len(w.keys) < limit); block++ {
+				w.keys = append(w.keys, seen.intern(strconv.FormatUint(block, 10)))
+			}
+		}
+
+		if limit > 0 && len(w.keys) >= limit {
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(w.keys) == 0 {
+		return nil, errors.New("trace contains no accesses")
+	}
+	return w, nil
+
+}
+
+// zipfWorkload generates ops accesses to keys distinct keys, where key i
+// is accessed with a probability proportional to 1/(i+1)^s. s must be
+// greater than 1; larger values mean a more skewed workload.
+func zipfWorkload(s float64, keys, ops int, seed int64) (*workload, error) {
+
+	if s <= 1 || keys < 1 || ops < 1 {
+		return nil, errors.New("zipf workload needs -zipf > 1, -keys >= 1 and -ops >= 1")
+	}
+
+	names := make([]string, keys)
+	for i := range names {
+		names[i] = "key:" + strconv.Itoa(i)
+	}
+
+	zipf := rand.NewZipf(rand.New(rand.NewSource(seed)), s, 1, uint64(keys-1))
+	w := &workload{
+		name: fmt.Sprintf("zipf(s=%g, keys=%d)", s, keys),
+		keys: make([]string, ops),
+	}
+	for i := range w.keys {
+		w.keys[i] = names[zipf.Uint64()]
+	}
+	return w, nil
+
+}
+
+// distinct returns the number of distinct keys of w.
+func (w *workload) distinct() int {
+	seen := make(map[string]struct{})
+	for _, key := range w.keys {
+		seen[key] = struct{}{}
+	}
+	return len(seen)
+}
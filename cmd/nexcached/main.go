@@ -0,0 +1,45 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+// Command nexcached runs an LRUCache behind the memcached ASCII protocol,
+// as a local drop-in replacement for memcached.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/georghagn/nexcache/lrucache"
+	"github.com/georghagn/nexcache/lrucache/memcached"
+)
+
+func main() {
+
+	addr := flag.String("addr", "127.0.0.1:11211", "listen address")
+	capacity := flag.Int("capacity", 100000, "maximum number of items")
+	cleanup := flag.Duration("cleanup", time.Minute, "interval of the expiry cleanup")
+	flag.Parse()
+
+	// Every store sets the client's expiration time, so the default TTL
+	// only bridges the moment between storing and applying it.
+	cache := lrucache.New(*capacity, time.Hour, *cleanup)
+	server := memcached.NewServer(cache)
+
+	go func() {
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+		<-sig
+		server.Close()
+	}()
+
+	log.Printf("nexcached listening on %s", *addr)
+	if err := server.ListenAndServe(*addr); err != nil {
+		log.Fatal(err)
+	}
+	cache.Close()
+
+}
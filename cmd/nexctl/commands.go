@@ -0,0 +1,388 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/georghagn/nexcache/lrucache"
+)
+
+// ---------------------- Reporting ----------------------
+
+func runInspect(arguments []string) error {
+
+	fs := flag.NewFlagSet("inspect", flag.ExitOnError)
+	in := inputFlags(fs)
+	files, err := args(fs, arguments, 1, -1)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for i, name := range files {
+		entries, savedAt, err := in.read(name)
+		if err != nil {
+			return err
+		}
+
+		var expired, expiredAtSave, eternal, duplicates int
+		var first, last time.Time
+		seen := make(map[string]bool, len(entries))
+		types := make(map[string]int)
+		for _, entry := range entries {
+			if seen[entry.Key] {
+				duplicates++
+			}
+			seen[entry.Key] = true
+			types[fmt.Sprintf("%T", entry.Value)]++
+
+			if entry.ExpiresAt.IsZero() {
+				eternal++
+				continue
+			}
+			if !entry.ExpiresAt.After(now) {
+				expired++
+			}
+			if !savedAt.IsZero() && !entry.ExpiresAt.After(savedAt) {
+				expiredAtSave++
+			}
+			if first.IsZero() || entry.ExpiresAt.Before(first) {
+				first = entry.ExpiresAt
+			}
+			if entry.ExpiresAt.After(last) {
+				last = entry.ExpiresAt
+			}
+		}
+
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "file\t%s\n", name)
+		fmt.Fprintf(w, "saved at\t%s\n", formatTime(savedAt))
+		fmt.Fprintf(w, "entries\t%d\n", len(entries))
+		fmt.Fprintf(w, "duplicate keys\t%d\n", duplicates)
+		fmt.Fprintf(w, "expired now\t%d\n", expired)
+		if !savedAt.IsZero() {
+			fmt.Fprintf(w, "expired at save\t%d\n", expiredAtSave)
+		}
+		fmt.Fprintf(w, "without expiry\t%d\n", eternal)
+		fmt.Fprintf(w, "first expiry\t%s\n", formatTime(first))
+		fmt.Fprintf(w, "last expiry\t%s\n", formatTime(last))
+		for _, typ := range sortedKeys(types) {
+			fmt.Fprintf(w, "values %s\t%d\n", typ, types[typ])
+		}
+	}
+	return w.Flush()
+
+}
+
+// printedEntry is the JSON form of an entry written by print.
+type printedEntry struct {
+	Key       string            `json:"key"`
+	ExpiresAt *time.Time        `json:"expiresAt,omitempty"`
+	Expired   bool              `json:"expired,omitempty"`
+	Meta      map[string]string `json:"meta,omitempty"`
+	Type      string            `json:"type"`
+	Value     interface{}       `json:"value"`
+}
+
+func runPrint(arguments []string) error {
+
+	fs := flag.NewFlagSet("print", flag.ExitOnError)
+	in := inputFlags(fs)
+	match := fs.String("match", "", "only print keys matching this regular expression")
+	live := fs.Bool("live", false, "skip expired entries")
+	files, err := args(fs, arguments, 1, 1)
+	if err != nil {
+		return err
+	}
+	re, err := regexp.Compile(*match)
+	if err != nil {
+		return err
+	}
+
+	entries, _, err := in.read(files[0])
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	for _, entry := range entries {
+		expired := isExpired(entry, now)
+		if !re.MatchString(entry.Key) || (*live && expired) {
+			continue
+		}
+		printed := printedEntry{
+			Key:     entry.Key,
+			Expired: expired,
+			Meta:    entry.Meta,
+			Type:    fmt.Sprintf("%T", entry.Value),
+			Value:   entry.Value,
+		}
+		if !entry.ExpiresAt.IsZero() {
+			printed.ExpiresAt = &entry.ExpiresAt
+		}
+		if _, err := json.Marshal(entry.Value); err != nil {
+			printed.Value = fmt.Sprintf("%#v", entry.Value)
+		}
+		if err := enc.Encode(printed); err != nil {
+			return err
+		}
+	}
+	return nil
+
+}
+
+// runDiff prints the keys only in A (-), only in B (+) and those whose
+// value, expiry or metadata differ (~). Like diff, it exits with status 1
+// if the snapshots differ.
+func runDiff(arguments []string) error {
+
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	in := inputFlags(fs)
+	files, err := args(fs, arguments, 2, 2)
+	if err != nil {
+		return err
+	}
+
+	a, _, err := in.read(files[0])
+	if err != nil {
+		return err
+	}
+	b, _, err := in.read(files[1])
+	if err != nil {
+		return err
+	}
+	left, right := byKey(a), byKey(b)
+
+	keys := make(map[string]int, len(left)+len(right))
+	for key := range left {
+		keys[key]++
+	}
+	for key := range right {
+		keys[key]++
+	}
+
+	differ := false
+	for _, key := range sortedKeys(keys) {
+		x, inA := left[key]
+		y, inB := right[key]
+		switch {
+		case !inB:
+			fmt.Printf("- %s\n", key)
+		case !inA:
+			fmt.Printf("+ %s\n", key)
+		case !reflect.DeepEqual(x.Value, y.Value):
+			fmt.Printf("~ %s value %v -> %v\n", key, x.Value, y.Value)
+		case !x.ExpiresAt.Equal(y.ExpiresAt):
+			fmt.Printf("~ %s expiry %s -> %s\n", key, formatTime(x.ExpiresAt), formatTime(y.ExpiresAt))
+		case !reflect.DeepEqual(x.Meta, y.Meta):
+			fmt.Printf("~ %s meta %v -> %v\n", key, x.Meta, y.Meta)
+		default:
+			continue
+		}
+		differ = true
+	}
+	if differ {
+		os.Exit(1)
+	}
+	return nil
+
+}
+
+// ---------------------- Rewriting ----------------------
+
+func runFilter(arguments []string) error {
+
+	fs := flag.NewFlagSet("filter", flag.ExitOnError)
+	in := inputFlags(fs)
+	out := outputFlags(fs, in)
+	match := fs.String("match", "", "keep keys matching this regular expression (required)")
+	invert := fs.Bool("v", false, "drop the matching keys instead")
+	files, err := args(fs, arguments, 1, 1)
+	if err != nil {
+		return err
+	}
+	if *match == "" {
+		return errors.New("filter: missing -match")
+	}
+	re, err := regexp.Compile(*match)
+	if err != nil {
+		return err
+	}
+
+	return rewrite(in, out, files[0], func(entries []lrucache.CacheEntry) []lrucache.CacheEntry {
+		kept := entries[:0]
+		for _, entry := range entries {
+			if re.MatchString(entry.Key) != *invert {
+				kept = append(kept, entry)
+			}
+		}
+		return kept
+	})
+
+}
+
+func runStripExpired(arguments []string) error {
+
+	fs := flag.NewFlagSet("strip-expired", flag.ExitOnError)
+	in := inputFlags(fs)
+	out := outputFlags(fs, in)
+	files, err := args(fs, arguments, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	return rewrite(in, out, files[0], stripExpired)
+
+}
+
+// runCompact drops expired entries and repeated keys, keeping the first
+// (most recently used) occurrence, and writes the current format. Older
+// snapshots are upgraded on the way.
+func runCompact(arguments []string) error {
+
+	fs := flag.NewFlagSet("compact", flag.ExitOnError)
+	in := inputFlags(fs)
+	out := outputFlags(fs, in)
+	files, err := args(fs, arguments, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	return rewrite(in, out, files[0], func(entries []lrucache.CacheEntry) []lrucache.CacheEntry {
+		return dedup(stripExpired(entries))
+	})
+
+}
+
+func runConvert(arguments []string) error {
+
+	fs := flag.NewFlagSet("convert", flag.ExitOnError)
+	in := inputFlags(fs)
+	out := outputFlags(fs, in)
+	fs.StringVar(&out.codec, "to", "", "codec to convert to: json or gob (required)")
+	files, err := args(fs, arguments, 1, 1)
+	if err != nil {
+		return err
+	}
+	if out.codec == "" {
+		return errors.New("convert: missing -to")
+	}
+
+	return rewrite(in, out, files[0], nil)
+
+}
+
+// runMerge joins several snapshots in the given order. If a key occurs
+// in more than one, the first file wins. The result records the latest
+// save time of the inputs.
+func runMerge(arguments []string) error {
+
+	fs := flag.NewFlagSet("merge", flag.ExitOnError)
+	in := inputFlags(fs)
+	out := outputFlags(fs, in)
+	files, err := args(fs, arguments, 1, -1)
+	if err != nil {
+		return err
+	}
+
+	var merged []lrucache.CacheEntry
+	var latest time.Time
+	for _, name := range files {
+		entries, savedAt, err := in.read(name)
+		if err != nil {
+			return err
+		}
+		merged = append(merged, entries...)
+		if savedAt.After(latest) {
+			latest = savedAt
+		}
+	}
+	return out.write(dedup(merged), latest)
+
+}
+
+// rewrite reads a snapshot, applies fn (if not nil) and writes the result
+// with the save time of the input.
+func rewrite(in *input, out *output, name string, fn func([]lrucache.CacheEntry) []lrucache.CacheEntry) error {
+
+	entries, savedAt, err := in.read(name)
+	if err != nil {
+		return err
+	}
+	if fn != nil {
+		entries = fn(entries)
+	}
+	return out.write(entries, savedAt)
+
+}
+
+// ---------------------- Helpers ----------------------
+
+func isExpired(entry lrucache.CacheEntry, now time.Time) bool {
+	return !entry.ExpiresAt.IsZero() && !entry.ExpiresAt.After(now)
+}
+
+func stripExpired(entries []lrucache.CacheEntry) []lrucache.CacheEntry {
+	now := time.Now()
+	kept := entries[:0]
+	for _, entry := range entries {
+		if !isExpired(entry, now) {
+			kept = append(kept, entry)
+		}
+	}
+	return kept
+}
+
+// dedup keeps the first entry of every key.
+func dedup(entries []lrucache.CacheEntry) []lrucache.CacheEntry {
+	seen := make(map[string]bool, len(entries))
+	kept := entries[:0]
+	for _, entry := range entries {
+		if !seen[entry.Key] {
+			seen[entry.Key] = true
+			kept = append(kept, entry)
+		}
+	}
+	return kept
+}
+
+// byKey indexes entries by key, keeping the first entry of every key.
+func byKey(entries []lrucache.CacheEntry) map[string]lrucache.CacheEntry {
+	index := make(map[string]lrucache.CacheEntry, len(entries))
+	for _, entry := range dedup(entries) {
+		index[entry.Key] = entry
+	}
+	return index
+}
+
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format(time.RFC3339Nano)
+}
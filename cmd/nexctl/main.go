@@ -0,0 +1,86 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+// Command nexctl inspects and rewrites snapshot files written by
+// SaveToFile, e.g. to see what a service had cached when it crashed.
+//
+//	nexctl inspect FILE...
+//	nexctl print [-match RE] [-live] FILE
+//	nexctl diff A B
+//	nexctl filter -match RE [-v] -o OUT FILE
+//	nexctl strip-expired -o OUT FILE
+//	nexctl compact -o OUT FILE
+//	nexctl convert -to json|gob -o OUT FILE
+//	nexctl merge -o OUT FILE...
+//
+// Snapshots are read with -codec (json by default) and decrypted with the
+// keys given as -key ID=HEX. Files are written with the input codec unless
+// -to is set, and encrypted only with -seal ID. Gob snapshots can only be
+// read if their values are basic Go types, since nexctl does not know the
+// types a service registered.
+package main
+
+import (
+	"encoding/gob"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+)
+
+type command struct {
+	usage string
+	run   func(args []string) error
+}
+
+var commands = map[string]command{
+	"inspect":       {"FILE...", runInspect},
+	"print":         {"[-match RE] [-live] FILE", runPrint},
+	"diff":          {"A B", runDiff},
+	"filter":        {"-match RE [-v] -o OUT FILE", runFilter},
+	"strip-expired": {"-o OUT FILE", runStripExpired},
+	"compact":       {"-o OUT FILE", runCompact},
+	"convert":       {"-to json|gob -o OUT FILE", runConvert},
+	"merge":         {"-o OUT FILE...", runMerge},
+}
+
+func init() {
+	// Values decoded from JSON snapshots, so they can be converted to gob.
+	gob.Register(map[string]interface{}{})
+	gob.Register([]interface{}{})
+}
+
+func main() {
+
+	log.SetFlags(0)
+	log.SetPrefix("nexctl: ")
+
+	if len(os.Args) < 2 {
+		usage()
+	}
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		usage()
+	}
+	if err := cmd.run(os.Args[2:]); err != nil {
+		log.Fatal(err)
+	}
+
+}
+
+func usage() {
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(os.Stderr, "usage: nexctl COMMAND [flags] ARGS")
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  nexctl %s %s\n", name, commands[name].usage)
+	}
+	fmt.Fprintln(os.Stderr, "Run nexctl COMMAND -h for the flags of a command.")
+	os.Exit(2)
+
+}
@@ -0,0 +1,187 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/georghagn/nexcache/lrucache"
+)
+
+// ---------------------- Reading ----------------------
+
+// input holds the flags for reading snapshots.
+type input struct {
+	codec string
+	keys  keyFlag
+}
+
+func inputFlags(fs *flag.FlagSet) *input {
+	in := &input{keys: keyFlag{}}
+	fs.StringVar(&in.codec, "codec", "json", "codec of the snapshots: json or gob")
+	fs.Var(in.keys, "key", "decryption key as ID=HEX (repeatable)")
+	return in
+}
+
+func (in *input) options() ([]lrucache.Option, error) {
+
+	codec, err := parseCodec(in.codec)
+	if err != nil {
+		return nil, err
+	}
+	// nexctl is meant for damaged snapshots too, so only the checks that
+	// do not depend on limits apply.
+	opts := []lrucache.Option{
+		lrucache.WithCodec(codec),
+		lrucache.WithSnapshotLimits(lrucache.SnapshotLimits{AllowDuplicates: true}),
+	}
+	if len(in.keys) > 0 {
+		opts = append(opts, lrucache.WithEncryption("", in.keys))
+	}
+	return opts, nil
+
+}
+
+// read returns all entries of a snapshot file and its save time.
+func (in *input) read(name string) ([]lrucache.CacheEntry, time.Time, error) {
+
+	opts, err := in.options()
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	defer file.Close()
+
+	var entries []lrucache.CacheEntry
+	savedAt, err := lrucache.ReadSnapshot(file, func(entry lrucache.CacheEntry) error {
+		entries = append(entries, entry)
+		return nil
+	}, opts...)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("%s: %w", name, err)
+	}
+	return entries, savedAt, nil
+
+}
+
+// ---------------------- Writing ----------------------
+
+// output holds the flags for writing a snapshot.
+type output struct {
+	in    *input
+	name  string
+	codec string
+	seal  string
+}
+
+func outputFlags(fs *flag.FlagSet, in *input) *output {
+	out := &output{in: in}
+	fs.StringVar(&out.name, "o", "", "output file (required)")
+	fs.StringVar(&out.seal, "seal", "", "encrypt the output with the -key of this ID")
+	return out
+}
+
+// write stores entries in the output file. The file is written to a
+// temporary file first, so the output may name an input.
+func (out *output) write(entries []lrucache.CacheEntry, savedAt time.Time) error {
+
+	if out.name == "" {
+		return errors.New("missing output file (-o)")
+	}
+	name := out.codec
+	if name == "" {
+		name = out.in.codec
+	}
+	codec, err := parseCodec(name)
+	if err != nil {
+		return err
+	}
+	opts := []lrucache.Option{lrucache.WithCodec(codec)}
+	if out.seal != "" {
+		if _, ok := out.in.keys[out.seal]; !ok {
+			return fmt.Errorf("no -key with ID %q to seal with", out.seal)
+		}
+		opts = append(opts, lrucache.WithEncryption(out.seal, out.in.keys))
+	}
+	if savedAt.IsZero() {
+		savedAt = time.Now()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(out.name), filepath.Base(out.name)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := lrucache.WriteSnapshot(tmp, entries, savedAt, opts...); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), out.name)
+
+}
+
+// ---------------------- Flags ----------------------
+
+func parseCodec(name string) (lrucache.Codec, error) {
+	switch name {
+	case "json":
+		return lrucache.JSONCodec, nil
+	case "gob":
+		return lrucache.GobCodec, nil
+	}
+	return nil, fmt.Errorf("unknown codec %q (json or gob)", name)
+}
+
+// keyFlag collects encryption keys given as ID=HEX.
+type keyFlag map[string][]byte
+
+func (k keyFlag) String() string {
+	ids := make([]string, 0, len(k))
+	for id := range k {
+		ids = append(ids, id)
+	}
+	return strings.Join(ids, ",")
+}
+
+func (k keyFlag) Set(value string) error {
+	id, key, ok := strings.Cut(value, "=")
+	if !ok {
+		return errors.New("key must be ID=HEX")
+	}
+	raw, err := hex.DecodeString(key)
+	if err != nil {
+		return fmt.Errorf("key %q: %w", id, err)
+	}
+	k[id] = raw
+	return nil
+}
+
+// args parses the flags and checks the number of remaining arguments.
+func args(fs *flag.FlagSet, arguments []string, min, max int) ([]string, error) {
+
+	if err := fs.Parse(arguments); err != nil {
+		return nil, err
+	}
+	rest := fs.Args()
+	if len(rest) < min || (max >= 0 && len(rest) > max) {
+		return nil, fmt.Errorf("%s: wrong number of arguments, see nexctl %s -h", fs.Name(), fs.Name())
+	}
+	return rest, nil
+
+}
@@ -0,0 +1,96 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"sync"
+	"time"
+)
+
+// ---------------------- Admission log ----------------------
+
+// AdmissionReason explains why a write did not end up (or stay) in the cache.
+type AdmissionReason string
+
+const (
+	// AdmissionRejectedByValidator: a loader result failed WithResultValidator.
+	AdmissionRejectedByValidator AdmissionReason = "rejected by validator"
+
+	// AdmissionRejectedBackpressure: TrySet hit the WithMaxInflightWrites limit.
+	AdmissionRejectedBackpressure AdmissionReason = "rejected by write limit"
+
+	// AdmissionRejectedClosed: the write reached a closed cache.
+	AdmissionRejectedClosed AdmissionReason = "cache closed"
+
+	// AdmissionEvicted: the entry was evicted to make room for another one.
+	AdmissionEvicted AdmissionReason = "evicted for capacity"
+
+	// AdmissionEvictedMemory: the entry was evicted under memory pressure.
+	AdmissionEvictedMemory AdmissionReason = "evicted under memory pressure"
+)
+
+// AdmissionRecord describes one declined or evicted write.
+type AdmissionRecord struct {
+	Time   time.Time
+	Key    string
+	Reason AdmissionReason
+	Err    error // cause, if any
+}
+
+// admissionLog is a fixed-size ring buffer of AdmissionRecords.
+type admissionLog struct {
+	mu      sync.Mutex
+	records []AdmissionRecord
+	next    int
+	full    bool
+}
+
+// WithAdmissionLog records the last size declined or evicted writes, to
+// answer "I set it but Get misses". Intended for debugging; see AdmissionLog.
+func WithAdmissionLog(size int) Option {
+	return func(c *LRUCache) {
+		if size > 0 {
+			c.admission = &admissionLog{records: make([]AdmissionRecord, size)}
+		}
+	}
+}
+
+// AdmissionLog returns the recorded admission decisions, oldest first.
+// It returns nil unless WithAdmissionLog is configured.
+func (c *LRUCache) AdmissionLog() []AdmissionRecord {
+
+	log := c.admission
+	if log == nil {
+		return nil
+	}
+
+	log.mu.Lock()
+	defer log.mu.Unlock()
+
+	if !log.full {
+		return append([]AdmissionRecord(nil), log.records[:log.next]...)
+	}
+	records := make([]AdmissionRecord, 0, len(log.records))
+	records = append(records, log.records[log.next:]...)
+	return append(records, log.records[:log.next]...)
+
+}
+
+func (c *LRUCache) logAdmission(key string, reason AdmissionReason, err error) {
+
+	log := c.admission
+	if log == nil {
+		return
+	}
+
+	log.mu.Lock()
+	defer log.mu.Unlock()
+
+	log.records[log.next] = AdmissionRecord{Time: c.clock.Now(), Key: key, Reason: reason, Err: err}
+	if log.next++; log.next == len(log.records) {
+		log.next = 0
+		log.full = true
+	}
+
+}
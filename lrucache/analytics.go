@@ -0,0 +1,174 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// ---------------------- Access analytics ----------------------
+
+// histogramBounds are the upper bounds of the analytics buckets. A last
+// bucket collects everything above.
+var histogramBounds = [...]time.Duration{
+	10 * time.Millisecond,
+	100 * time.Millisecond,
+	time.Second,
+	10 * time.Second,
+	time.Minute,
+	5 * time.Minute,
+	15 * time.Minute,
+	time.Hour,
+	6 * time.Hour,
+	24 * time.Hour,
+}
+
+// Histogram is a distribution of durations in fixed buckets.
+type Histogram struct {
+	// Bounds are the upper bounds of the buckets; Counts has one more
+	// element for the durations above the last bound.
+	Bounds []time.Duration
+	Counts []uint64
+	Count  uint64
+	Sum    time.Duration
+}
+
+// Mean returns the average duration, or 0 if nothing was recorded.
+func (h Histogram) Mean() time.Duration {
+	if h.Count == 0 {
+		return 0
+	}
+	return h.Sum / time.Duration(h.Count)
+}
+
+// Quantile returns the upper bound of the bucket that contains the
+// quantile q (between 0 and 1), or -1 if it lies above the last bound.
+// It returns 0 if nothing was recorded.
+func (h Histogram) Quantile(q float64) time.Duration {
+
+	if h.Count == 0 {
+		return 0
+	}
+	rank := uint64(q * float64(h.Count))
+	var seen uint64
+	for i, n := range h.Counts {
+		seen += n
+		if seen > rank && i < len(h.Bounds) {
+			return h.Bounds[i]
+		}
+	}
+	return -1
+
+}
+
+// Analytics are the distributions recorded with WithAnalytics.
+type Analytics struct {
+	// HitAge is the time since the entry was last written, at each hit.
+	HitAge Histogram
+
+	// AccessGap is the time since the previous hit or write of the entry,
+	// at each hit.
+	AccessGap Histogram
+
+	// EvictionAge is the time since the last write of entries evicted to
+	// make room, which shows how long entries survive in the cache.
+	EvictionAge Histogram
+}
+
+// WithAnalytics records the distributions returned by Analytics, to choose
+// TTL and capacity from data: a TTL far above most hit ages wastes
+// memory, and short eviction ages mean the capacity is too small. Each hit
+// costs a few atomic operations; the entries carry the time of their last
+// write.
+func WithAnalytics() Option {
+	return func(c *LRUCache) {
+		c.analytics = &analytics{}
+	}
+}
+
+// Analytics returns the distributions recorded since the cache was
+// created. Without WithAnalytics all histograms are empty.
+func (c *LRUCache) Analytics() Analytics {
+
+	if c.analytics == nil {
+		return Analytics{}
+	}
+	return Analytics{
+		HitAge:      c.analytics.hitAge.snapshot(),
+		AccessGap:   c.analytics.accessGap.snapshot(),
+		EvictionAge: c.analytics.evictionAge.snapshot(),
+	}
+
+}
+
+type analytics struct {
+	hitAge      histogram
+	accessGap   histogram
+	evictionAge histogram
+}
+
+// histogram is the lock-free recording side of Histogram.
+type histogram struct {
+	counts [len(histogramBounds) + 1]atomic.Uint64
+	sum    atomic.Int64
+}
+
+func (h *histogram) observe(d time.Duration) {
+
+	if d < 0 {
+		d = 0
+	}
+	i := 0
+	for i < len(histogramBounds) && d > histogramBounds[i] {
+		i++
+	}
+	h.counts[i].Add(1)
+	h.sum.Add(int64(d))
+
+}
+
+func (h *histogram) snapshot() Histogram {
+
+	out := Histogram{
+		Bounds: append([]time.Duration(nil), histogramBounds[:]...),
+		Counts: make([]uint64, len(h.counts)),
+		Sum:    time.Duration(h.sum.Load()),
+	}
+	for i := range h.counts {
+		out.Counts[i] = h.counts[i].Load()
+		out.Count += out.Counts[i]
+	}
+	return out
+
+}
+
+// stampWrite records the write time of entry for the analytics.
+func (c *LRUCache) stampWrite(entry *CacheEntry) {
+	if c.analytics != nil {
+		entry.born = c.clock.Now().UnixNano()
+		atomic.StoreInt64(&entry.lastUse, entry.born)
+	}
+}
+
+// recordHit records a hit on entry. It is safe under the read lock and
+// must be called before touch.
+func (c *LRUCache) recordHit(entry *CacheEntry, now time.Time) {
+
+	if c.analytics == nil {
+		return
+	}
+	ns := now.UnixNano()
+	prev := atomic.SwapInt64(&entry.lastUse, ns)
+	c.analytics.hitAge.observe(time.Duration(ns - entry.born))
+	c.analytics.accessGap.observe(time.Duration(ns - prev))
+
+}
+
+// recordEviction records the age of an entry evicted for capacity.
+func (c *LRUCache) recordEviction(entry *CacheEntry) {
+	if c.analytics != nil {
+		c.analytics.evictionAge.observe(time.Duration(c.clock.Now().UnixNano() - entry.born))
+	}
+}
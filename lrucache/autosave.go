@@ -0,0 +1,80 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"errors"
+	"time"
+)
+
+// ---------------------- Auto-save ----------------------
+
+// errCleanupStopped is returned when auto-save is configured after the
+// background routine has been stopped.
+var errCleanupStopped = errors.New("lrucache: cleanup routine is stopped")
+
+// EnableAutoSave writes a snapshot to path every interval and once more on
+// Close. Snapshots are written atomically with the configured codec.
+// Auto-save runs on the cleanup goroutine, so it ends with StopCleanup.
+// Calling it again replaces the previous configuration.
+func (c *LRUCache) EnableAutoSave(path string, interval time.Duration) error {
+
+	if path == "" || interval <= 0 {
+		return errors.New("lrucache: auto-save needs a path and a positive interval")
+	}
+
+	c.lock()
+	if c.closedLocked() {
+		c.unlock()
+		return ErrClosed
+	}
+	c.autoSavePath = path
+	c.unlock()
+
+	select {
+	case c.autoSaveCh <- interval:
+		return nil
+	case <-c.stopCh:
+		return errCleanupStopped
+	}
+
+}
+
+// DisableAutoSave stops periodic snapshots. Close no longer writes a
+// final snapshot.
+func (c *LRUCache) DisableAutoSave() {
+
+	c.lock()
+	c.autoSavePath = ""
+	c.unlock()
+
+	select {
+	case c.autoSaveCh <- 0:
+	case <-c.stopCh:
+	}
+
+}
+
+// AutoSaveError returns the error of the most recent periodic snapshot,
+// or nil if it succeeded.
+func (c *LRUCache) AutoSaveError() error {
+
+	c.lock()
+	defer c.unlock()
+
+	return c.autoSaveErr
+
+}
+
+func (c *LRUCache) autoSaveNow() {
+
+	c.lock()
+	defer c.unlock()
+
+	if c.closed || c.autoSavePath == "" {
+		return
+	}
+	c.autoSaveErr = c.snapshotFileLocked(c.autoSavePath)
+
+}
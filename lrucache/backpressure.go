@@ -0,0 +1,73 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"errors"
+)
+
+// ErrTooManyWrites is returned by TrySet when the write limit is reached.
+var ErrTooManyWrites = errors.New("lrucache: too many concurrent writes")
+
+// WithMaxInflightWrites bounds the number of Set operations that may be in
+// progress (running or waiting for the lock) at the same time. Once the
+// limit is reached, Set blocks until a slot is free, while TrySet fails
+// immediately with ErrTooManyWrites. This protects the cache and its
+// eviction machinery from pathological write floods.
+func WithMaxInflightWrites(n int) Option {
+	return func(c *LRUCache) {
+		if n > 0 {
+			c.writeSlots = make(chan struct{}, n)
+		}
+	}
+}
+
+// TrySet stores a value like Set, but never waits for a write slot.
+// It returns ErrTooManyWrites if the limit set with WithMaxInflightWrites
+// is reached, and ErrClosed if the cache is closed.
+func (c *LRUCache) TrySet(key string, value interface{}) error {
+
+	if !c.tryAcquireWrite() {
+		c.logAdmission(key, AdmissionRejectedBackpressure, ErrTooManyWrites)
+		return ErrTooManyWrites
+	}
+	defer c.releaseWrite()
+
+	c.lock()
+	defer c.unlock()
+
+	if c.closedLocked() {
+		return ErrClosed
+	}
+
+	c.putLocked(key, value, c.deadline(c.clock.Now(), c.ttl))
+	return nil
+
+}
+
+// ---------------------- Helpers ----------------------
+
+func (c *LRUCache) acquireWrite() {
+	if c.writeSlots != nil {
+		c.writeSlots <- struct{}{}
+	}
+}
+
+func (c *LRUCache) tryAcquireWrite() bool {
+	if c.writeSlots == nil {
+		return true
+	}
+	select {
+	case c.writeSlots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *LRUCache) releaseWrite() {
+	if c.writeSlots != nil {
+		<-c.writeSlots
+	}
+}
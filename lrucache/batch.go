@@ -0,0 +1,171 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"context"
+	"sort"
+)
+
+// ---------------------- Batch operations ----------------------
+
+// GetMulti retrieves several values under a single lock acquisition.
+// Missing and expired keys are absent from the result. Found entries are
+// promoted in the order of keys, so the last key ends up most recent.
+func (c *LRUCache) GetMulti(keys []string) map[string]interface{} {
+
+	c.lock()
+	defer c.unlock()
+
+	result := make(map[string]interface{}, len(keys))
+	if c.closedLocked() {
+		return result
+	}
+
+	now := c.clock.Now()
+	for _, key := range keys {
+		c.recordLookup(key)
+		element, found := c.cache[key]
+		if !found {
+			c.misses.Add(1)
+			continue
+		}
+		entry := element.Value.(*CacheEntry)
+		if entry.expired(now) {
+			c.expireElement(element)
+			c.misses.Add(1)
+			continue
+		}
+		c.access(element)
+		c.hits.Add(1)
+		result[key] = c.copyOut(hydrate(entry))
+	}
+	return result
+
+}
+
+// SetMulti stores several values under a single lock acquisition.
+// Since maps are unordered, entries are written in sorted key order,
+// which keeps the resulting recency order deterministic.
+func (c *LRUCache) SetMulti(items map[string]interface{}) {
+
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	c.acquireWrite()
+	defer c.releaseWrite()
+
+	c.lock()
+	defer c.unlock()
+
+	if c.closedLocked() {
+		return
+	}
+
+	now := c.clock.Now()
+	for _, key := range keys {
+		c.putLocked(key, items[key], c.deadline(now, c.ttl))
+	}
+
+}
+
+// GetOrLoadMulti returns the cached values for keys and loads all missing
+// keys with a single loader call. With a backing store, missing keys are
+// read through first and only the rest is passed to the loader. Loaded
+// values are validated and cached; keys the loader does not return, and
+// keys with a failure remembered by WithNegativeCaching, are absent from
+// the result. On a loader error the cached hits are returned together with
+// the error. Timeouts, retries, load shedding and the circuit breaker
+// apply to the whole batch; in per-prefix mode the breaker of the first
+// missing key is used.
+func (c *LRUCache) GetOrLoadMulti(keys []string, loader func(missing []string) (map[string]interface{}, error)) (map[string]interface{}, error) {
+
+	c.checkLoader(loader == nil)
+
+	return c.GetOrLoadMultiContext(context.Background(), keys, func(_ context.Context, missing []string) (map[string]interface{}, error) {
+		return loader(missing)
+	})
+
+}
+
+// GetOrLoadMultiContext is GetOrLoadMulti with a context that is passed to
+// the loader and bounds the wait for retries and the rate limiter. Loads
+// made with a LowPriority context can be shed (see WithLoadShedding).
+func (c *LRUCache) GetOrLoadMultiContext(ctx context.Context, keys []string, loader func(ctx context.Context, missing []string) (map[string]interface{}, error)) (map[string]interface{}, error) {
+
+	c.checkLoader(loader == nil)
+
+	c.lock()
+	closed := c.closedLocked()
+	c.unlock()
+	if closed {
+		return map[string]interface{}{}, ErrClosed
+	}
+
+	result := c.GetMulti(keys)
+
+	var missing []string
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if _, hit := result[key]; hit || seen[key] {
+			continue
+		}
+		seen[key] = true
+		if c.store != nil {
+			if val, found := c.readThrough(key); found {
+				result[key] = val
+				continue
+			}
+		}
+		if c.negativeErr(key) == nil {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	if c.shouldShed(ctx) {
+		return result, ErrLoadShed
+	}
+	if !c.allowLoad(missing[0]) {
+		return result, ErrCircuitOpen
+	}
+	if err := c.throttle(ctx); err != nil {
+		return result, err
+	}
+
+	done := c.trackLoad()
+	val, err := c.callLoader(ctx, missing[0], func(ctx context.Context) (interface{}, error) {
+		return loader(ctx, missing)
+	})
+	done()
+	c.recordLoad(ctx, missing[0], err)
+	c.countLoad(err)
+	if err != nil {
+		return result, &LoaderError{Key: missing[0], Err: err}
+	}
+
+	loaded, _ := val.(map[string]interface{})
+	for _, key := range missing {
+		v, ok := loaded[key]
+		if !ok {
+			continue
+		}
+		if c.validator != nil {
+			if err := c.validator(key, v); err != nil {
+				c.logAdmission(key, AdmissionRejectedByValidator, err)
+				c.rememberFailure(key, err, true)
+				continue
+			}
+		}
+		c.forgetFailure(key)
+		result[key] = c.fill(key, v, 0, false)
+	}
+	return result, nil
+
+}
@@ -0,0 +1,85 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache_test
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/georghagn/nexcache/lrucache"
+)
+
+func TestGetMultiSetMulti(t *testing.T) {
+
+	c := lrucache.New(10, time.Hour, time.Hour)
+	defer c.Close()
+
+	c.SetMulti(map[string]interface{}{"a": 1, "b": 2})
+	got := c.GetMulti([]string{"a", "b", "c"})
+	if !reflect.DeepEqual(got, map[string]interface{}{"a": 1, "b": 2}) {
+		t.Errorf("GetMulti = %v", got)
+	}
+
+}
+
+// Hits come from the cache, the store is read through, and only the rest
+// reaches the loader in one call.
+func TestGetOrLoadMulti(t *testing.T) {
+
+	store := newMemStore()
+	store.data["stored"] = "s"
+	c := lrucache.New(10, time.Hour, time.Hour, lrucache.WithWriteThrough(store))
+	defer c.Close()
+	c.Set("cached", "c")
+
+	var asked [][]string
+	got, err := c.GetOrLoadMulti([]string{"cached", "stored", "x", "y", "x"}, func(missing []string) (map[string]interface{}, error) {
+		asked = append(asked, append([]string(nil), missing...))
+		return map[string]interface{}{"x": "loaded"}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]interface{}{"cached": "c", "stored": "s", "x": "loaded"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("result = %v, want %v", got, want)
+	}
+	if len(asked) != 1 {
+		t.Fatalf("loader called %d times, want 1", len(asked))
+	}
+	sort.Strings(asked[0])
+	if !reflect.DeepEqual(asked[0], []string{"x", "y"}) {
+		t.Errorf("loader asked for %v, want [x y]", asked[0])
+	}
+	if !c.Contains("x") || c.Contains("y") {
+		t.Error("loaded values were not cached, or a missing one was")
+	}
+	if val, _ := store.get("x"); val != nil {
+		t.Error("loaded value was written to the store")
+	}
+
+}
+
+func TestGetOrLoadMultiError(t *testing.T) {
+
+	c := lrucache.New(10, time.Hour, time.Hour)
+	defer c.Close()
+	c.Set("a", 1)
+
+	failure := errors.New("backend down")
+	got, err := c.GetOrLoadMulti([]string{"a", "b"}, func([]string) (map[string]interface{}, error) {
+		return nil, failure
+	})
+	if !errors.Is(err, failure) {
+		t.Errorf("error = %v, want the loader error", err)
+	}
+	if !reflect.DeepEqual(got, map[string]interface{}{"a": 1}) {
+		t.Errorf("result = %v, want the cached hits", got)
+	}
+
+}
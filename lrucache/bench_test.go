@@ -0,0 +1,77 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+// BenchmarkSetEvict inserts into a full cache, so every Set evicts the
+// oldest entry. With recycled list elements only the new CacheEntry is
+// allocated; the list itself does not allocate.
+func BenchmarkSetEvict(b *testing.B) {
+
+	const capacity = 1024
+	c := New(capacity, time.Hour, time.Hour)
+	defer c.Close()
+
+	keys := make([]string, 4*capacity)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+	}
+	for _, key := range keys[:capacity] {
+		c.Set(key, 1)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Set(keys[i%len(keys)], 1)
+	}
+
+}
+
+// BenchmarkSetUpdate overwrites existing keys (0 allocs/op).
+func BenchmarkSetUpdate(b *testing.B) {
+
+	const capacity = 1024
+	c := New(capacity, time.Hour, time.Hour)
+	defer c.Close()
+
+	keys := make([]string, capacity)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+		c.Set(keys[i], 1)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Set(keys[i%len(keys)], 1)
+	}
+
+}
+
+// BenchmarkGet reads existing keys (0 allocs/op).
+func BenchmarkGet(b *testing.B) {
+
+	const capacity = 1024
+	c := New(capacity, time.Hour, time.Hour)
+	defer c.Close()
+
+	keys := make([]string, capacity)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+		c.Set(keys[i], 1)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Get(keys[i%len(keys)])
+	}
+
+}
@@ -0,0 +1,125 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ---------------------- Circuit breaker ----------------------
+
+// ErrCircuitOpen is returned by the GetOrLoad family when the circuit
+// breaker is open and the loader was not called.
+var ErrCircuitOpen = errors.New("lrucache: circuit breaker is open")
+
+// WithCircuitBreaker stops calling loaders after failures consecutive
+// loader errors within window. While open, loads fail fast with
+// ErrCircuitOpen (GetOrLoadWithFallback returns its fallback). After
+// coolDown a single probe load is let through: success closes the
+// breaker, failure keeps it open for another coolDown.
+func WithCircuitBreaker(failures int, window, coolDown time.Duration) Option {
+	return func(c *LRUCache) {
+		c.breakerFailures = failures
+		c.breakerWindow = window
+		c.breakerCoolDown = coolDown
+		c.breakers = make(map[string]*breaker)
+	}
+}
+
+// WithPrefixCircuitBreaker is like WithCircuitBreaker, but keeps a
+// separate breaker per key prefix (the part before the first ':'), so one
+// failing backend does not cut off the others.
+func WithPrefixCircuitBreaker(failures int, window, coolDown time.Duration) Option {
+	return func(c *LRUCache) {
+		WithCircuitBreaker(failures, window, coolDown)(c)
+		c.breakerPerPrefix = true
+	}
+}
+
+type breaker struct {
+	failures  int
+	since     time.Time // first failure of the current run
+	openUntil time.Time // zero while closed
+	probing   bool      // half-open probe in flight
+}
+
+// breakerFor returns the breaker responsible for key, or nil if circuit
+// breaking is disabled. Must be called with c.breakerMu held.
+func (c *LRUCache) breakerFor(key string) *breaker {
+
+	if c.breakers == nil {
+		return nil
+	}
+
+	name := ""
+	if c.breakerPerPrefix {
+		name = keyPrefix(key)
+	}
+	b, ok := c.breakers[name]
+	if !ok {
+		b = &breaker{}
+		c.breakers[name] = b
+	}
+	return b
+
+}
+
+// allowLoad reports whether a loader may run for key. In the half-open
+// state only one caller is admitted as probe.
+func (c *LRUCache) allowLoad(key string) bool {
+
+	c.breakerMu.Lock()
+	defer c.breakerMu.Unlock()
+
+	b := c.breakerFor(key)
+	if b == nil || b.openUntil.IsZero() {
+		return true
+	}
+	if b.probing || c.clock.Now().Before(b.openUntil) {
+		return false
+	}
+	b.probing = true
+	return true
+
+}
+
+// recordLoad updates the breaker with the outcome of a loader call.
+// Cancellations by the caller do not count as failures.
+func (c *LRUCache) recordLoad(ctx context.Context, key string, err error) {
+
+	c.breakerMu.Lock()
+	defer c.breakerMu.Unlock()
+
+	b := c.breakerFor(key)
+	if b == nil {
+		return
+	}
+
+	wasProbe := b.probing
+	b.probing = false
+
+	if err == nil || ctx.Err() != nil {
+		if err == nil {
+			*b = breaker{}
+		}
+		return
+	}
+
+	now := c.clock.Now()
+	if wasProbe {
+		b.openUntil = now.Add(c.breakerCoolDown)
+		return
+	}
+	if b.failures == 0 || now.Sub(b.since) > c.breakerWindow {
+		b.failures = 0
+		b.since = now
+	}
+	b.failures++
+	if b.failures >= c.breakerFailures {
+		b.openUntil = now.Add(c.breakerCoolDown)
+	}
+
+}
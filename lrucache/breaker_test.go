@@ -0,0 +1,88 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/georghagn/nexcache/lrucache"
+)
+
+var errBackend = errors.New("backend down")
+
+func failing() (interface{}, error) { return nil, errBackend }
+
+func TestCircuitBreakerOpensAndProbes(t *testing.T) {
+
+	clock := newClock()
+	c := lrucache.New(10, time.Hour, time.Hour, lrucache.WithClock(clock),
+		lrucache.WithCircuitBreaker(2, time.Minute, 10*time.Second))
+	defer c.Close()
+
+	c.GetOrLoad("a", failing)
+	c.GetOrLoad("b", failing)
+	calls := 0
+	counting := func() (interface{}, error) { calls++; return nil, errBackend }
+	if _, err := c.GetOrLoad("c", counting); err != lrucache.ErrCircuitOpen || calls != 0 {
+		t.Fatalf("load while open = %v after %d loader calls, want ErrCircuitOpen without a call", err, calls)
+	}
+	val, err := c.GetOrLoadWithFallback("c", counting, "fallback")
+	if val != "fallback" || err != lrucache.ErrCircuitOpen {
+		t.Errorf("GetOrLoadWithFallback = %v, %v", val, err)
+	}
+
+	// A failed probe keeps the breaker open for another cool-down.
+	clock.Advance(10 * time.Second)
+	c.GetOrLoad("c", counting)
+	if calls != 1 {
+		t.Fatalf("loader called %d times after the cool-down, want one probe", calls)
+	}
+	if _, err := c.GetOrLoad("c", counting); err != lrucache.ErrCircuitOpen {
+		t.Fatalf("load after a failed probe = %v, want ErrCircuitOpen", err)
+	}
+
+	// A successful probe closes it.
+	clock.Advance(10 * time.Second)
+	if _, err := c.GetOrLoad("c", func() (interface{}, error) { return "v", nil }); err != nil {
+		t.Fatalf("probe = %v", err)
+	}
+	if _, err := c.GetOrLoad("d", failing); err == lrucache.ErrCircuitOpen {
+		t.Error("breaker is still open after a successful probe")
+	}
+
+}
+
+// Failures spread wider than the window do not open the breaker.
+func TestCircuitBreakerWindow(t *testing.T) {
+
+	clock := newClock()
+	c := lrucache.New(10, time.Hour, time.Hour, lrucache.WithClock(clock),
+		lrucache.WithCircuitBreaker(2, time.Minute, time.Minute))
+	defer c.Close()
+
+	c.GetOrLoad("a", failing)
+	clock.Advance(2 * time.Minute)
+	c.GetOrLoad("a", failing)
+	if _, err := c.GetOrLoad("a", failing); err == lrucache.ErrCircuitOpen {
+		t.Error("failures outside the window opened the breaker")
+	}
+
+}
+
+func TestPrefixCircuitBreaker(t *testing.T) {
+
+	c := lrucache.New(10, time.Hour, time.Hour, lrucache.WithPrefixCircuitBreaker(1, time.Minute, time.Minute))
+	defer c.Close()
+
+	c.GetOrLoad("users:1", failing)
+	if _, err := c.GetOrLoad("users:2", failing); err != lrucache.ErrCircuitOpen {
+		t.Errorf("load of the failing prefix = %v, want ErrCircuitOpen", err)
+	}
+	if _, err := c.GetOrLoad("orders:1", func() (interface{}, error) { return "v", nil }); err != nil {
+		t.Errorf("load of another prefix = %v", err)
+	}
+
+}
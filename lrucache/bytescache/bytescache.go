@@ -0,0 +1,216 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+// Package bytescache is a cache for []byte values that keeps keys and
+// values in a few large byte buffers instead of individual objects. The
+// index maps key hashes to offsets and contains no pointers, so the
+// garbage collector neither scans the entries nor has to trace them, even
+// when the cache holds gigabytes:
+//
+//	cache := bytescache.New(4<<30, 10*time.Minute)
+//	cache.Set("page:/", html)
+//	html, ok := cache.Get("page:/")
+//
+// The cache is split into shards, each with its own lock and ring buffer.
+// New entries are appended to the ring of their shard; when it is full,
+// the oldest entries are overwritten. Eviction therefore follows insertion
+// order, not recency. Updates and deletions leave the old bytes in the
+// ring until they are overwritten.
+//
+// Values are copied in Set and out in Get, so callers may reuse their
+// slices. Two keys with the same 64-bit hash replace each other.
+package bytescache
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/georghagn/nexcache/lrucache"
+)
+
+// ErrTooLarge is returned by Set when an entry does not fit into a shard.
+var ErrTooLarge = errors.New("bytescache: entry larger than a shard")
+
+// Cache is a sharded byte-slice cache. It is safe for concurrent use.
+type Cache struct {
+	shards []*shard
+	mask   uint64
+	ttl    time.Duration
+	clock  lrucache.Clock
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+// Stats is a snapshot of the cache counters.
+type Stats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64 // entries overwritten because a ring was full
+	Entries   int
+	Bytes     int // bytes of the rings in use, including stale entries
+}
+
+// Option configures a Cache.
+type Option func(*config)
+
+type config struct {
+	shards int
+	clock  lrucache.Clock
+}
+
+// WithShards sets the number of shards, rounded up to a power of two
+// (64 by default). More shards reduce lock contention; each shard gets
+// an equal part of the size.
+func WithShards(n int) Option {
+	return func(cfg *config) {
+		cfg.shards = n
+	}
+}
+
+// WithClock replaces the wall clock, e.g. with package clocktest. Only
+// Now is used.
+func WithClock(clock lrucache.Clock) Option {
+	return func(cfg *config) {
+		cfg.clock = clock
+	}
+}
+
+// New returns a cache that holds up to size bytes of keys, values and
+// entry headers. ttl is the default lifetime of entries; 0 means they do
+// not expire.
+func New(size int, ttl time.Duration, opts ...Option) *Cache {
+
+	cfg := config{shards: 64}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	n := 1
+	for n < cfg.shards {
+		n <<= 1
+	}
+	perShard := size / n
+	if perShard > maxShardSize {
+		perShard = maxShardSize
+	}
+
+	c := &Cache{
+		shards: make([]*shard, n),
+		mask:   uint64(n - 1),
+		ttl:    ttl,
+		clock:  cfg.clock,
+	}
+	for i := range c.shards {
+		c.shards[i] = newShard(perShard)
+	}
+	return c
+
+}
+
+// Get returns a copy of the value stored under key.
+func (c *Cache) Get(key string) ([]byte, bool) {
+	return c.GetAppend(nil, key)
+}
+
+// GetAppend appends the value stored under key to dst and returns the
+// extended slice. With a reused dst, hits do not allocate.
+func (c *Cache) GetAppend(dst []byte, key string) ([]byte, bool) {
+
+	hash := hashKey(key)
+	dst, found := c.shard(hash).get(dst, key, hash, c.now().UnixNano())
+	if found {
+		c.hits.Add(1)
+	} else {
+		c.misses.Add(1)
+	}
+	return dst, found
+
+}
+
+// Set stores a copy of value under key with the default TTL.
+func (c *Cache) Set(key string, value []byte) error {
+	return c.SetWithTTL(key, value, c.ttl)
+}
+
+// SetWithTTL stores a copy of value under key; a ttl of 0 means the entry
+// does not expire.
+func (c *Cache) SetWithTTL(key string, value []byte, ttl time.Duration) error {
+
+	if len(key) > maxKeyLen {
+		return ErrTooLarge
+	}
+	var expires int64
+	if ttl > 0 {
+		expires = c.now().Add(ttl).UnixNano()
+	}
+	hash := hashKey(key)
+	return c.shard(hash).set(key, value, hash, expires)
+
+}
+
+// Delete removes key and reports whether it was present.
+func (c *Cache) Delete(key string) bool {
+	hash := hashKey(key)
+	return c.shard(hash).remove(key, hash)
+}
+
+// Len returns the number of entries, including expired entries that were
+// not yet looked up or overwritten.
+func (c *Cache) Len() int {
+
+	n := 0
+	for _, s := range c.shards {
+		s.mu.RLock()
+		n += len(s.index)
+		s.mu.RUnlock()
+	}
+	return n
+
+}
+
+// Clear removes all entries. The buffers are kept.
+func (c *Cache) Clear() {
+	for _, s := range c.shards {
+		s.clear()
+	}
+}
+
+// Stats returns the current counters.
+func (c *Cache) Stats() Stats {
+
+	stats := Stats{Hits: c.hits.Load(), Misses: c.misses.Load()}
+	for _, s := range c.shards {
+		s.mu.RLock()
+		stats.Evictions += s.evictions
+		stats.Entries += len(s.index)
+		stats.Bytes += s.used()
+		s.mu.RUnlock()
+	}
+	return stats
+
+}
+
+func (c *Cache) shard(hash uint64) *shard {
+	return c.shards[hash&c.mask]
+}
+
+// hashKey is FNV-1a, computed without allocating.
+func hashKey(key string) uint64 {
+
+	hash := uint64(14695981039346656037)
+	for i := 0; i < len(key); i++ {
+		hash ^= uint64(key[i])
+		hash *= 1099511628211
+	}
+	return hash
+
+}
+
+func (c *Cache) now() time.Time {
+	if c.clock == nil {
+		return time.Now()
+	}
+	return c.clock.Now()
+}
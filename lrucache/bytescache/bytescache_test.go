@@ -0,0 +1,157 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package bytescache
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/georghagn/nexcache/lrucache/clocktest"
+)
+
+// entrySize is the ring space taken by a two-byte key and an eight-byte
+// value, as used by the tests.
+const entrySize = headerLen + 2 + 8
+
+func TestSetGetDelete(t *testing.T) {
+
+	c := New(1<<20, 0, WithShards(4))
+
+	value := []byte("value")
+	if err := c.Set("k", value); err != nil {
+		t.Fatal(err)
+	}
+	value[0] = 'X'
+	got, found := c.Get("k")
+	if !found || string(got) != "value" {
+		t.Fatalf("Get = %q, %v, want the value as it was set", got, found)
+	}
+	got[0] = 'Y'
+	if again, _ := c.Get("k"); string(again) != "value" {
+		t.Errorf("changing a returned slice changed the cache: %q", again)
+	}
+
+	if !c.Delete("k") || c.Delete("k") {
+		t.Error("Delete did not report presence correctly")
+	}
+	if _, found := c.Get("k"); found {
+		t.Error("deleted key was found")
+	}
+
+}
+
+func TestUpdateWithOtherSize(t *testing.T) {
+
+	c := New(1<<20, 0, WithShards(1))
+
+	c.Set("k", []byte("short"))
+	c.Set("k", []byte("a longer value!"))
+	c.Set("k", []byte("same size  val!"))
+	if got, _ := c.Get("k"); string(got) != "same size  val!" {
+		t.Errorf("Get = %q", got)
+	}
+	if n := c.Len(); n != 1 {
+		t.Errorf("Len = %d, want 1", n)
+	}
+
+}
+
+func TestGetAppend(t *testing.T) {
+
+	c := New(1<<20, 0)
+	c.Set("k", []byte("tail"))
+
+	dst, found := c.GetAppend([]byte("head-"), "k")
+	if !found || string(dst) != "head-tail" {
+		t.Errorf("GetAppend = %q, %v", dst, found)
+	}
+	if dst, found := c.GetAppend([]byte("head-"), "missing"); found || string(dst) != "head-" {
+		t.Errorf("GetAppend of a miss = %q, %v, want dst unchanged", dst, found)
+	}
+
+}
+
+func TestExpiry(t *testing.T) {
+
+	clock := clocktest.New(time.Unix(1700000000, 0))
+	c := New(1<<20, time.Minute, WithClock(clock))
+
+	c.Set("default", []byte("a"))
+	c.SetWithTTL("long", []byte("b"), time.Hour)
+	c.SetWithTTL("forever", []byte("c"), 0)
+
+	clock.Advance(time.Minute)
+	if _, found := c.Get("default"); found {
+		t.Error("entry outlived the default TTL")
+	}
+	clock.Advance(2 * time.Hour)
+	if _, found := c.Get("long"); found {
+		t.Error("entry outlived its own TTL")
+	}
+	if _, found := c.Get("forever"); !found {
+		t.Error("entry without TTL expired")
+	}
+
+}
+
+// A full ring overwrites its oldest entries, in insertion order.
+func TestEvictsOldestWhenFull(t *testing.T) {
+
+	c := New(4*entrySize, 0, WithShards(1))
+
+	for i := 0; i < 5; i++ {
+		if err := c.Set(fmt.Sprint("k", i), bytes.Repeat([]byte{byte(i)}, 8)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c.Get("k1") // reading does not protect an entry
+
+	if _, found := c.Get("k0"); found {
+		t.Error("oldest entry was not overwritten")
+	}
+	for i := 1; i < 5; i++ {
+		if got, found := c.Get(fmt.Sprint("k", i)); !found || got[0] != byte(i) {
+			t.Errorf("k%d = %v, %v", i, got, found)
+		}
+	}
+	if s := c.Stats(); s.Evictions != 1 || s.Entries != 4 {
+		t.Errorf("stats = %+v, want one eviction and four entries", s)
+	}
+
+}
+
+func TestTooLarge(t *testing.T) {
+
+	c := New(4*entrySize, 0, WithShards(1))
+
+	if err := c.Set("k", make([]byte, 4*entrySize)); err != ErrTooLarge {
+		t.Errorf("Set of an oversized value = %v, want ErrTooLarge", err)
+	}
+	if err := c.Set(string(make([]byte, maxKeyLen+1)), nil); err != ErrTooLarge {
+		t.Errorf("Set of an oversized key = %v, want ErrTooLarge", err)
+	}
+
+}
+
+func TestClearAndStats(t *testing.T) {
+
+	c := New(1<<20, 0)
+
+	c.Set("a", []byte("1"))
+	c.Get("a")
+	c.Get("b")
+	if s := c.Stats(); s.Hits != 1 || s.Misses != 1 || s.Entries != 1 || s.Bytes == 0 {
+		t.Errorf("stats = %+v", s)
+	}
+	c.Clear()
+	if s := c.Stats(); s.Entries != 0 || s.Bytes != 0 {
+		t.Errorf("stats after Clear = %+v", s)
+	}
+	if _, found := c.Get("a"); found {
+		t.Error("entry survived Clear")
+	}
+
+}
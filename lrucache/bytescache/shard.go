@@ -0,0 +1,204 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package bytescache
+
+import (
+	"encoding/binary"
+	"math"
+	"sync"
+)
+
+// ---------------------- Ring buffer ----------------------
+
+// Each entry is stored in the ring as
+//
+//	hash (8) | expiry in Unix ns, 0 = none (8) | key length (2) | value length (4) | key | value
+//
+// Entries never wrap around the end of the buffer: if an entry does not
+// fit behind the last one, writing continues at the start.
+const (
+	headerLen    = 22
+	maxKeyLen    = math.MaxUint16
+	maxShardSize = math.MaxInt32 // offsets are stored as uint32
+)
+
+type shard struct {
+	mu    sync.RWMutex
+	index map[uint64]uint32 // key hash → offset of the entry
+	buf   []byte
+
+	// The entries occupy [head, tail), or [head, end) and [0, tail) once
+	// writing has wrapped around.
+	head, tail, end int
+	wrapped         bool
+
+	evictions uint64
+}
+
+func newShard(size int) *shard {
+	return &shard{
+		index: make(map[uint64]uint32),
+		buf:   make([]byte, size),
+	}
+}
+
+func (s *shard) get(dst []byte, key string, hash uint64, now int64) ([]byte, bool) {
+
+	s.mu.RLock()
+	off, found := s.index[hash]
+	if !found {
+		s.mu.RUnlock()
+		return dst, false
+	}
+	_, expires, entryKey, value := s.entry(int(off))
+	if string(entryKey) != key {
+		s.mu.RUnlock()
+		return dst, false
+	}
+	if expires != 0 && now >= expires {
+		s.mu.RUnlock()
+		s.mu.Lock()
+		// Recheck: the entry may have been renewed in place meanwhile.
+		if cur, ok := s.index[hash]; ok && cur == off {
+			if _, expires, _, _ := s.entry(int(off)); expires != 0 && now >= expires {
+				delete(s.index, hash)
+			}
+		}
+		s.mu.Unlock()
+		return dst, false
+	}
+	dst = append(dst, value...)
+	s.mu.RUnlock()
+	return dst, true
+
+}
+
+func (s *shard) set(key string, value []byte, hash uint64, expires int64) error {
+
+	size := headerLen + len(key) + len(value)
+	if size > len(s.buf) {
+		return ErrTooLarge
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if off, found := s.index[hash]; found {
+		_, _, entryKey, old := s.entry(int(off))
+		if string(entryKey) == key && len(old) == len(value) {
+			// Same size: overwrite in place.
+			binary.LittleEndian.PutUint64(s.buf[int(off)+8:], uint64(expires))
+			copy(old, value)
+			return nil
+		}
+		// The old bytes stay in the ring until they are overwritten.
+		delete(s.index, hash)
+	}
+
+	off := s.reserve(size)
+	b := s.buf[off : off+size]
+	binary.LittleEndian.PutUint64(b, hash)
+	binary.LittleEndian.PutUint64(b[8:], uint64(expires))
+	binary.LittleEndian.PutUint16(b[16:], uint16(len(key)))
+	binary.LittleEndian.PutUint32(b[18:], uint32(len(value)))
+	copy(b[headerLen:], key)
+	copy(b[headerLen+len(key):], value)
+	s.index[hash] = uint32(off)
+	return nil
+
+}
+
+func (s *shard) remove(key string, hash uint64) bool {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	off, found := s.index[hash]
+	if !found {
+		return false
+	}
+	if _, _, entryKey, _ := s.entry(int(off)); string(entryKey) != key {
+		return false
+	}
+	delete(s.index, hash)
+	return true
+
+}
+
+func (s *shard) clear() {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.index = make(map[uint64]uint32)
+	s.head, s.tail, s.end = 0, 0, 0
+	s.wrapped = false
+
+}
+
+// reserve returns the offset of size free bytes, overwriting the oldest
+// entries as needed. size must not exceed the buffer.
+func (s *shard) reserve(size int) int {
+
+	for {
+		if !s.wrapped {
+			if s.tail+size <= len(s.buf) {
+				off := s.tail
+				s.tail += size
+				return off
+			}
+			if s.head == s.tail {
+				s.head, s.tail = 0, 0
+				continue
+			}
+			s.end, s.tail, s.wrapped = s.tail, 0, true
+			continue
+		}
+
+		if s.tail+size <= s.head {
+			off := s.tail
+			s.tail += size
+			return off
+		}
+		s.dropOldest()
+	}
+
+}
+
+// dropOldest removes the entry at head. Must only be called while wrapped.
+func (s *shard) dropOldest() {
+
+	hash, _, key, value := s.entry(s.head)
+	if off, found := s.index[hash]; found && int(off) == s.head {
+		delete(s.index, hash)
+		s.evictions++
+	}
+	s.head += headerLen + len(key) + len(value)
+	if s.head >= s.end {
+		s.head, s.end, s.wrapped = 0, 0, false
+	}
+
+}
+
+// entry decodes the entry at off. key and value alias the buffer.
+func (s *shard) entry(off int) (hash uint64, expires int64, key, value []byte) {
+
+	b := s.buf[off:]
+	hash = binary.LittleEndian.Uint64(b)
+	expires = int64(binary.LittleEndian.Uint64(b[8:]))
+	keyLen := int(binary.LittleEndian.Uint16(b[16:]))
+	valueLen := int(binary.LittleEndian.Uint32(b[18:]))
+	key = b[headerLen : headerLen+keyLen]
+	value = b[headerLen+keyLen : headerLen+keyLen+valueLen]
+	return hash, expires, key, value
+
+}
+
+// used returns the number of bytes occupied by entries, stale or not.
+func (s *shard) used() int {
+	if s.wrapped {
+		return s.end - s.head + s.tail
+	}
+	return s.tail - s.head
+}
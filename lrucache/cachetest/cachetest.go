@@ -0,0 +1,41 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+// Package cachetest provides helpers for testing code that uses lrucache.
+package cachetest
+
+import (
+	"testing"
+	"time"
+)
+
+// shutdownTimeout is how long VerifyShutdown waits for goroutines to end.
+const shutdownTimeout = 2 * time.Second
+
+// GoroutineOwner is implemented by caches that account for their
+// background goroutines, such as *lrucache.LRUCache.
+type GoroutineOwner interface {
+	Goroutines() int
+}
+
+// VerifyShutdown fails the test if the cache still owns background
+// goroutines shortly after it was closed. Call it after Close (or
+// StopCleanup) to catch goroutine leaks.
+func VerifyShutdown(t testing.TB, cache GoroutineOwner) {
+
+	t.Helper()
+
+	deadline := time.Now().Add(shutdownTimeout)
+	for {
+		n := cache.Goroutines()
+		if n == 0 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("cachetest: %d background goroutine(s) still running after shutdown", n)
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+}
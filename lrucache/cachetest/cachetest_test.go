@@ -0,0 +1,68 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package cachetest
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/georghagn/nexcache/lrucache"
+)
+
+// recorder is a testing.TB that records Fatalf instead of stopping.
+type recorder struct {
+	testing.TB
+	failed string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Fatalf(format string, args ...interface{}) {
+	r.failed = fmt.Sprintf(format, args...)
+}
+
+type owner struct{ n atomic.Int64 }
+
+func (o *owner) Goroutines() int { return int(o.n.Load()) }
+
+func TestVerifyShutdownAfterClose(t *testing.T) {
+
+	c := lrucache.New(10, time.Hour, time.Second)
+	if c.Goroutines() == 0 {
+		t.Fatal("cache with a cleanup interval reports no goroutines")
+	}
+	c.Close()
+	VerifyShutdown(t, c)
+
+}
+
+// Goroutines that end within the timeout are waited for.
+func TestVerifyShutdownWaits(t *testing.T) {
+
+	o := &owner{}
+	o.n.Store(1)
+	time.AfterFunc(50*time.Millisecond, func() { o.n.Store(0) })
+
+	r := &recorder{}
+	VerifyShutdown(r, o)
+	if r.failed != "" {
+		t.Errorf("VerifyShutdown failed: %s", r.failed)
+	}
+
+}
+
+func TestVerifyShutdownReportsLeak(t *testing.T) {
+
+	o := &owner{}
+	o.n.Store(2)
+
+	r := &recorder{}
+	VerifyShutdown(r, o)
+	if r.failed == "" {
+		t.Error("leaked goroutines were not reported")
+	}
+
+}
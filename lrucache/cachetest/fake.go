@@ -0,0 +1,321 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package cachetest
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/georghagn/nexcache/lrucache"
+	"github.com/georghagn/nexcache/lrucache/clocktest"
+)
+
+// ---------------------- Fake cache ----------------------
+
+// Call is a method call recorded by Fake. Value is the stored value for
+// Set and nil otherwise.
+type Call struct {
+	Method string
+	Key    string
+	Value  interface{}
+}
+
+// Fake is an in-memory lrucache.Cache for unit tests of code that uses a
+// cache. It records every call, can be told to miss or fail loads for
+// chosen keys, and expires entries by a fake clock that only moves when
+// the test advances it. It has no capacity limit and no background
+// goroutines; loaders run without deduplication. Fake is safe for
+// concurrent use.
+type Fake struct {
+	// Clock decides expiry; advance it to expire entries.
+	Clock *clocktest.Fake
+
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]fakeEntry
+	misses  map[string]bool
+	fails   map[string]error
+	calls   []Call
+	hits    int
+	missed  int
+}
+
+type fakeEntry struct {
+	value     interface{}
+	expiresAt time.Time
+}
+
+var _ lrucache.Cache = (*Fake)(nil)
+
+// NewFake returns an empty Fake whose entries expire ttl after they were
+// set; 0 means they never expire. Its clock starts at the Unix epoch.
+func NewFake(ttl time.Duration) *Fake {
+	return &Fake{
+		Clock:   clocktest.New(time.Unix(0, 0)),
+		ttl:     ttl,
+		entries: make(map[string]fakeEntry),
+		misses:  make(map[string]bool),
+		fails:   make(map[string]error),
+	}
+}
+
+// ForceMiss makes lookups of keys miss even if they are stored, e.g. to
+// exercise the loader path of GetOrLoad.
+func (f *Fake) ForceMiss(keys ...string) {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, key := range keys {
+		f.misses[key] = true
+	}
+
+}
+
+// FailLoad makes GetOrLoad and GetOrLoadContext return err for key,
+// wrapped in an *lrucache.LoaderError like the real cache, without calling
+// the loader. A nil err removes the failure.
+func (f *Fake) FailLoad(key string, err error) {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err == nil {
+		delete(f.fails, key)
+		return
+	}
+	f.fails[key] = err
+
+}
+
+// Calls returns the recorded calls in order.
+func (f *Fake) Calls() []Call {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]Call(nil), f.calls...)
+
+}
+
+// CallCount returns how often method was called.
+func (f *Fake) CallCount(method string) int {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	n := 0
+	for _, call := range f.calls {
+		if call.Method == method {
+			n++
+		}
+	}
+	return n
+
+}
+
+// Hits returns the number of lookups by Get and the GetOrLoad family that
+// found a value.
+func (f *Fake) Hits() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.hits
+}
+
+// Misses returns the number of lookups by Get and the GetOrLoad family
+// that found no value.
+func (f *Fake) Misses() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.missed
+}
+
+// Reset forgets the recorded calls, the counters and the forced misses
+// and failures. The stored entries are kept.
+func (f *Fake) Reset() {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls = nil
+	f.hits, f.missed = 0, 0
+	f.misses = make(map[string]bool)
+	f.fails = make(map[string]error)
+
+}
+
+// ---------------------- lrucache.Cache ----------------------
+
+// Get returns the live value of key and counts a hit or miss.
+func (f *Fake) Get(key string) (interface{}, bool) {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.record("Get", key, nil)
+	return f.lookupLocked(key, true)
+
+}
+
+// Peek is like Get but does not count the lookup.
+func (f *Fake) Peek(key string) (interface{}, bool) {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.record("Peek", key, nil)
+	return f.lookupLocked(key, false)
+
+}
+
+// Contains reports whether key holds a live value without counting the
+// lookup.
+func (f *Fake) Contains(key string) bool {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.record("Contains", key, nil)
+	_, found := f.lookupLocked(key, false)
+	return found
+
+}
+
+// Set stores value under key with the TTL of the Fake.
+func (f *Fake) Set(key string, value interface{}) {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.record("Set", key, value)
+	f.storeLocked(key, value)
+
+}
+
+// Delete removes key and reports whether it held a live value.
+func (f *Fake) Delete(key string) bool {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.record("Delete", key, nil)
+	_, found := f.lookupLocked(key, false)
+	delete(f.entries, key)
+	return found
+
+}
+
+// GetOrLoad returns the value of key or stores and returns the result of
+// loader. Loader errors and failures set with FailLoad are returned as
+// *lrucache.LoaderError and not cached.
+func (f *Fake) GetOrLoad(key string, loader func() (interface{}, error)) (interface{}, error) {
+	return f.load("GetOrLoad", key, loader)
+}
+
+// GetOrLoadContext is like GetOrLoad and passes ctx to loader.
+func (f *Fake) GetOrLoadContext(ctx context.Context, key string, loader lrucache.LoaderFunc) (interface{}, error) {
+	return f.load("GetOrLoadContext", key, func() (interface{}, error) {
+		return loader(ctx)
+	})
+}
+
+// Len returns the number of live entries.
+func (f *Fake) Len() int {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.record("Len", "", nil)
+	n := 0
+	now := f.Clock.Now()
+	for _, entry := range f.entries {
+		if !entry.expired(now) {
+			n++
+		}
+	}
+	return n
+
+}
+
+// Clear removes all entries. Recorded calls and counters are kept.
+func (f *Fake) Clear() {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.record("Clear", "", nil)
+	f.entries = make(map[string]fakeEntry)
+
+}
+
+// ---------------------- Internals ----------------------
+
+// load serves key or calls loader without holding the lock, so loaders
+// may use the Fake.
+func (f *Fake) load(method, key string, loader func() (interface{}, error)) (interface{}, error) {
+
+	f.mu.Lock()
+	f.record(method, key, nil)
+	if value, found := f.lookupLocked(key, true); found {
+		f.mu.Unlock()
+		return value, nil
+	}
+	failure := f.fails[key]
+	f.mu.Unlock()
+
+	if failure != nil {
+		return nil, &lrucache.LoaderError{Key: key, Err: failure}
+	}
+	value, err := loader()
+	if err != nil {
+		return nil, &lrucache.LoaderError{Key: key, Err: err}
+	}
+
+	f.mu.Lock()
+	f.storeLocked(key, value)
+	f.mu.Unlock()
+	return value, nil
+
+}
+
+// lookupLocked returns the live value of key; count says whether the
+// lookup is counted as hit or miss.
+func (f *Fake) lookupLocked(key string, count bool) (interface{}, bool) {
+
+	entry, found := f.entries[key]
+	if found && entry.expired(f.Clock.Now()) {
+		delete(f.entries, key)
+		found = false
+	}
+	found = found && !f.misses[key]
+	if count {
+		if found {
+			f.hits++
+		} else {
+			f.missed++
+		}
+	}
+	if !found {
+		return nil, false
+	}
+	return entry.value, true
+
+}
+
+func (f *Fake) storeLocked(key string, value interface{}) {
+	entry := fakeEntry{value: value}
+	if f.ttl > 0 {
+		entry.expiresAt = f.Clock.Now().Add(f.ttl)
+	}
+	f.entries[key] = entry
+}
+
+func (f *Fake) record(method, key string, value interface{}) {
+	f.calls = append(f.calls, Call{Method: method, Key: key, Value: value})
+}
+
+func (e fakeEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && now.After(e.expiresAt)
+}
@@ -0,0 +1,117 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package cachetest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/georghagn/nexcache/lrucache"
+)
+
+// Entries expire like in the cache: still alive at exactly their TTL,
+// gone after it.
+func TestFakeExpiry(t *testing.T) {
+
+	f := NewFake(time.Minute)
+	f.Set("k", "v")
+
+	f.Clock.Advance(time.Minute)
+	if _, found := f.Get("k"); !found {
+		t.Error("entry expired at exactly its TTL")
+	}
+	f.Clock.Advance(time.Second)
+	if _, found := f.Get("k"); found {
+		t.Error("entry outlived its TTL")
+	}
+	if f.Len() != 0 {
+		t.Error("expired entry is counted by Len")
+	}
+
+	forever := NewFake(0)
+	forever.Set("k", "v")
+	forever.Clock.Advance(1000 * time.Hour)
+	if !forever.Contains("k") {
+		t.Error("entry of a Fake without TTL expired")
+	}
+
+}
+
+func TestFakeRecordsCallsAndCounts(t *testing.T) {
+
+	f := NewFake(0)
+	f.Set("a", 1)
+	f.Get("a")
+	f.Get("b")
+	f.Peek("a")
+	f.Delete("a")
+
+	want := []Call{{"Set", "a", 1}, {"Get", "a", nil}, {"Get", "b", nil}, {"Peek", "a", nil}, {"Delete", "a", nil}}
+	calls := f.Calls()
+	if len(calls) != len(want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Errorf("call %d = %v, want %v", i, calls[i], want[i])
+		}
+	}
+	if f.Hits() != 1 || f.Misses() != 1 || f.CallCount("Get") != 2 {
+		t.Errorf("hits %d, misses %d, Get calls %d, want 1, 1, 2", f.Hits(), f.Misses(), f.CallCount("Get"))
+	}
+
+	f.Reset()
+	if len(f.Calls()) != 0 || f.Hits() != 0 || f.Misses() != 0 {
+		t.Error("Reset kept calls or counters")
+	}
+
+}
+
+func TestFakeForceMissRunsLoader(t *testing.T) {
+
+	f := NewFake(0)
+	f.Set("k", "cached")
+	f.ForceMiss("k")
+
+	val, err := f.GetOrLoad("k", func() (interface{}, error) {
+		// Loaders may use the Fake.
+		f.Peek("other")
+		return "loaded", nil
+	})
+	if err != nil || val != "loaded" {
+		t.Fatalf("GetOrLoad = %v, %v, want loaded", val, err)
+	}
+	f.Reset()
+	if val, _ := f.Get("k"); val != "loaded" {
+		t.Errorf("Get after Reset = %v, want the loaded value", val)
+	}
+
+}
+
+func TestFakeFailLoad(t *testing.T) {
+
+	f := NewFake(0)
+	errDown := errors.New("down")
+	f.FailLoad("k", errDown)
+
+	called := false
+	_, err := f.GetOrLoad("k", func() (interface{}, error) {
+		called = true
+		return "v", nil
+	})
+	var le *lrucache.LoaderError
+	if !errors.As(err, &le) || le.Key != "k" || !errors.Is(err, errDown) {
+		t.Errorf("err = %v, want a LoaderError wrapping the failure", err)
+	}
+	if called || f.Contains("k") {
+		t.Error("failed load called the loader or cached a value")
+	}
+
+	f.FailLoad("k", nil)
+	if val, err := f.GetOrLoad("k", func() (interface{}, error) { return "v", nil }); err != nil || val != "v" {
+		t.Errorf("GetOrLoad after removing the failure = %v, %v", val, err)
+	}
+
+}
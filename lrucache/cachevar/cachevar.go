@@ -0,0 +1,32 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+// Package cachevar publishes cache statistics as expvar variables, so they
+// appear under /debug/vars without any external dependency. It lives in
+// its own package because importing expvar registers an HTTP handler.
+package cachevar
+
+import (
+	"expvar"
+
+	"github.com/georghagn/nexcache/lrucache"
+)
+
+// Publish registers c under name. The values are read from the cache only
+// when /debug/vars (or expvar.Get) is queried. Like expvar.Publish, it
+// panics if name is already in use.
+func Publish(name string, c *lrucache.LRUCache) {
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		s := c.Stats()
+		return map[string]interface{}{
+			"size":             c.Len(),
+			"hits":             s.Hits,
+			"misses":           s.Misses,
+			"hitRate":          s.HitRate(),
+			"evictions":        s.Evictions,
+			"loads":            s.Loads,
+			"loadErrors":       s.LoadErrors,
+			"compressionRatio": s.CompressionRatio(),
+		}
+	}))
+}
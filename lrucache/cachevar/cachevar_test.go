@@ -0,0 +1,76 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package cachevar
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/georghagn/nexcache/lrucache"
+)
+
+var seq atomic.Int64
+
+// uniqueName returns a fresh variable name, as expvar names cannot be
+// reused within the process, also not by repeated test runs.
+func uniqueName(prefix string) string {
+	return fmt.Sprintf("%s_%d", prefix, seq.Add(1))
+}
+
+// read returns the published variable name as /debug/vars shows it.
+func read(t *testing.T, name string) map[string]float64 {
+
+	t.Helper()
+	v := expvar.Get(name)
+	if v == nil {
+		t.Fatalf("%s is not published", name)
+	}
+	var out map[string]float64
+	if err := json.Unmarshal([]byte(v.String()), &out); err != nil {
+		t.Fatal(err)
+	}
+	return out
+
+}
+
+// The values are read from the cache when queried, not when published.
+func TestPublishReadsLive(t *testing.T) {
+
+	c := lrucache.New(10, time.Hour, time.Hour)
+	defer c.Close()
+	name := uniqueName("cachevar_test_live")
+	Publish(name, c)
+
+	if got := read(t, name); got["size"] != 0 || got["hits"] != 0 {
+		t.Errorf("fresh cache = %v", got)
+	}
+	c.Set("k", "v")
+	c.Get("k")
+	c.Get("missing")
+	got := read(t, name)
+	if got["size"] != 1 || got["hits"] != 1 || got["misses"] != 1 || got["hitRate"] != 0.5 {
+		t.Errorf("after one hit and one miss = %v", got)
+	}
+
+}
+
+func TestPublishTwicePanics(t *testing.T) {
+
+	c := lrucache.New(10, time.Hour, time.Hour)
+	defer c.Close()
+	name := uniqueName("cachevar_test_twice")
+	Publish(name, c)
+
+	defer func() {
+		if recover() == nil {
+			t.Error("second Publish under the same name did not panic")
+		}
+	}()
+	Publish(name, c)
+
+}
@@ -0,0 +1,161 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ---------------------- Canary ----------------------
+
+// ErrCanaryTimeout is reported by Health when a canary check did not
+// finish within its timeout, which usually means the cache lock is stuck.
+var ErrCanaryTimeout = errors.New("lrucache: canary check timed out")
+
+// canaryKey is the sentinel key of the canary entry. It is only present
+// while the cache lock is held, so callers never observe it.
+const canaryKey = "\x00lrucache/canary"
+
+// EnableCanary periodically writes, reads back and removes a sentinel entry
+// to verify the cache machinery itself. A check that does not complete
+// within timeout (measured in wall-clock time) or finds the internal index
+// inconsistent is reported by Health. The canary runs until Close.
+func (c *LRUCache) EnableCanary(interval, timeout time.Duration) error {
+
+	if interval <= 0 || timeout <= 0 {
+		return errors.New("lrucache: canary needs a positive interval and timeout")
+	}
+
+	c.lock()
+	if c.closedLocked() {
+		c.unlock()
+		return ErrClosed
+	}
+	if c.canary {
+		c.unlock()
+		return errors.New("lrucache: canary already enabled")
+	}
+	c.canary = true
+	c.unlock()
+
+	ticker := c.clock.NewTicker(interval)
+	c.spawn(func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C():
+				if !c.runCanary(timeout) {
+					return
+				}
+			case <-c.stopCh:
+				return
+			}
+		}
+	})
+	return nil
+
+}
+
+// Health returns the result of the most recent canary check: nil if the
+// cache is healthy or the canary is not enabled. It never takes the cache
+// lock, so it can be called even if the cache is deadlocked.
+func (c *LRUCache) Health() error {
+
+	c.canaryMu.Lock()
+	defer c.canaryMu.Unlock()
+
+	return c.canaryErr
+
+}
+
+// runCanary performs one check and records its outcome. It reports false
+// if the cache was stopped while waiting.
+func (c *LRUCache) runCanary(timeout time.Duration) bool {
+
+	done := make(chan error, 1)
+	c.spawn(func() { done <- c.probeCanary() })
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-done:
+		c.setHealth(err)
+		return true
+	case <-timer.C:
+		c.setHealth(ErrCanaryTimeout)
+	case <-c.stopCh:
+		return false
+	}
+
+	// Keep reporting the timeout until the stalled check completes.
+	select {
+	case err := <-done:
+		c.setHealth(err)
+		return true
+	case <-c.stopCh:
+		return false
+	}
+
+}
+
+func (c *LRUCache) setHealth(err error) {
+	c.canaryMu.Lock()
+	c.canaryErr = err
+	c.canaryMu.Unlock()
+}
+
+// probeCanary links a sentinel entry, looks it up again and unlinks it,
+// checking that the key index and the recency list stay consistent.
+func (c *LRUCache) probeCanary() error {
+
+	c.lock()
+	defer c.unlock()
+
+	if c.closed {
+		return nil
+	}
+	if err := c.checkIndexLocked(); err != nil {
+		return err
+	}
+	if _, ok := c.cache[canaryKey]; ok {
+		return errors.New("lrucache: canary: sentinel key already present")
+	}
+
+	c.canarySeq++
+	token := c.canarySeq
+	elem := c.list.PushFront(&CacheEntry{Key: canaryKey, Value: token})
+	c.cache[canaryKey] = elem
+
+	// Read the sentinel before unlinking it: Remove recycles the element
+	// and clears its value.
+	got, ok := c.cache[canaryKey]
+	var value interface{}
+	if ok {
+		if entry, isEntry := got.Value.(*CacheEntry); isEntry {
+			value = entry.Value
+		}
+	}
+	c.list.Remove(elem)
+	delete(c.cache, canaryKey)
+
+	if !ok || got != elem {
+		return errors.New("lrucache: canary: sentinel entry not found after write")
+	}
+	if v, _ := value.(uint64); v != token {
+		return fmt.Errorf("lrucache: canary: sentinel value corrupted: got %v, want %d", value, token)
+	}
+	return c.checkIndexLocked()
+
+}
+
+// checkIndexLocked verifies that the key map and the recency list agree.
+func (c *LRUCache) checkIndexLocked() error {
+	if len(c.cache) != c.list.Len() {
+		return fmt.Errorf("lrucache: canary: index holds %d keys but list holds %d entries", len(c.cache), c.list.Len())
+	}
+	return nil
+}
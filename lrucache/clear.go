@@ -0,0 +1,62 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+// ---------------------- Clear / Purge ----------------------
+
+// Clear removes all entries. OnEvict is called for each of them with
+// EvictCleared. Forks keep their view, an enabled WAL is compacted, and a
+// backing store is not touched.
+func (c *LRUCache) Clear() {
+
+	c.lock()
+	defer c.unlock()
+
+	if c.closedLocked() {
+		return
+	}
+	c.clearLocked()
+
+}
+
+// Purge is like Clear and additionally resets the statistics of the cache
+// and all its namespaces.
+func (c *LRUCache) Purge() {
+
+	c.lock()
+	defer c.unlock()
+
+	if c.closedLocked() {
+		return
+	}
+	c.clearLocked()
+
+	c.hits.Store(0)
+	c.misses.Store(0)
+	c.evictions.Store(0)
+	c.loads.Store(0)
+	c.loadErrors.Store(0)
+	c.compressedIn.Store(0)
+	c.compressedOut.Store(0)
+	for _, ns := range c.namespaces {
+		ns.hits.Store(0)
+		ns.misses.Store(0)
+	}
+
+}
+
+func (c *LRUCache) clearLocked() {
+
+	if c.onEvict != nil || len(c.subscribers) > 0 {
+		for element := c.list.Front(); element != nil; element = element.Next() {
+			c.evicted(element.Value.(*CacheEntry), EvictCleared)
+		}
+	}
+	c.resetLocked()
+	c.forgetFailures()
+	if c.wal != nil {
+		c.compactWALLocked()
+	}
+
+}
@@ -0,0 +1,53 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"time"
+)
+
+// ---------------------- Clock ----------------------
+
+// Clock is the time source of the cache. All expiry decisions, the
+// background tickers, the loader retry backoff and the rate limiter waits
+// use it, so tests can replace it with a fake (see package clocktest) and
+// check TTL and retry behavior without sleeping.
+type Clock interface {
+	Now() time.Time
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker delivers ticks like time.Ticker.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// WithClock replaces the wall clock.
+func WithClock(clock Clock) Option {
+	return func(c *LRUCache) {
+		c.clock = clock
+	}
+}
+
+// Clock returns the time source of the cache, so that code working next
+// to the cache, e.g. a second cache level, can share it.
+func (c *LRUCache) Clock() Clock {
+	return c.clock
+}
+
+// realClock is the default Clock backed by package time.
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) NewTicker(d time.Duration) Ticker {
+	return realTicker{time.NewTicker(d)}
+}
+
+type realTicker struct {
+	*time.Ticker
+}
+
+func (t realTicker) C() <-chan time.Time { return t.Ticker.C }
@@ -0,0 +1,124 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+// Package clocktest provides a manually advanced clock for testing caches
+// created with lrucache.WithClock.
+package clocktest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/georghagn/nexcache/lrucache"
+)
+
+// Fake is a Clock whose time only moves when Advance or Set is called.
+// Tickers created from it fire during Advance, like time.Ticker they drop
+// ticks if the receiver is not ready.
+type Fake struct {
+	mu      sync.Mutex
+	now     time.Time
+	tickers []*fakeTicker
+}
+
+var _ lrucache.Clock = (*Fake)(nil)
+
+// New returns a fake clock set to start.
+func New(start time.Time) *Fake {
+	return &Fake{now: start}
+}
+
+// Now returns the current fake time.
+func (f *Fake) Now() time.Time {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.now
+
+}
+
+// NewTicker returns a ticker driven by Advance.
+func (f *Fake) NewTicker(d time.Duration) lrucache.Ticker {
+
+	if d <= 0 {
+		panic("clocktest: non-positive interval for NewTicker")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	t := &fakeTicker{clock: f, period: d, next: f.now.Add(d), ch: make(chan time.Time, 1)}
+	f.tickers = append(f.tickers, t)
+	return t
+
+}
+
+// Advance moves the clock forward by d and fires all tickers that became due.
+func (f *Fake) Advance(d time.Duration) {
+
+	f.mu.Lock()
+	f.now = f.now.Add(d)
+	now := f.now
+	tickers := append([]*fakeTicker(nil), f.tickers...)
+	f.mu.Unlock()
+
+	for _, t := range tickers {
+		t.fire(now)
+	}
+
+}
+
+// Set moves the clock to t without firing tickers.
+func (f *Fake) Set(t time.Time) {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.now = t
+
+}
+
+func (f *Fake) remove(t *fakeTicker) {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for i, other := range f.tickers {
+		if other == t {
+			f.tickers = append(f.tickers[:i], f.tickers[i+1:]...)
+			return
+		}
+	}
+
+}
+
+type fakeTicker struct {
+	clock  *Fake
+	period time.Duration
+	mu     sync.Mutex
+	next   time.Time
+	ch     chan time.Time
+}
+
+func (t *fakeTicker) C() <-chan time.Time { return t.ch }
+
+func (t *fakeTicker) Stop() { t.clock.remove(t) }
+
+func (t *fakeTicker) fire(now time.Time) {
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if now.Before(t.next) {
+		return
+	}
+	for !now.Before(t.next) {
+		t.next = t.next.Add(t.period)
+	}
+	select {
+	case t.ch <- now:
+	default:
+	}
+
+}
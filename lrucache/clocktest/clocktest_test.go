@@ -0,0 +1,106 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package clocktest
+
+import (
+	"testing"
+	"time"
+)
+
+var start = time.Unix(1700000000, 0)
+
+// ticked reports whether a tick is waiting on ch, and consumes it.
+func ticked(ch <-chan time.Time) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestAdvanceAndSet(t *testing.T) {
+
+	f := New(start)
+
+	f.Advance(time.Minute)
+	if got := f.Now(); !got.Equal(start.Add(time.Minute)) {
+		t.Errorf("Now after Advance = %v", got)
+	}
+	f.Set(start)
+	if got := f.Now(); !got.Equal(start) {
+		t.Errorf("Now after Set = %v", got)
+	}
+
+}
+
+func TestTickerFiresWhenDue(t *testing.T) {
+
+	f := New(start)
+	tk := f.NewTicker(time.Second)
+	defer tk.Stop()
+
+	f.Advance(500 * time.Millisecond)
+	if ticked(tk.C()) {
+		t.Error("ticker fired before its interval")
+	}
+	f.Advance(500 * time.Millisecond)
+	select {
+	case at := <-tk.C():
+		if !at.Equal(start.Add(time.Second)) {
+			t.Errorf("tick at %v, want %v", at, start.Add(time.Second))
+		}
+	default:
+		t.Fatal("ticker did not fire at its interval")
+	}
+
+}
+
+// Like time.Ticker, a ticker whose receiver is not ready drops ticks, and
+// a long Advance fires only once.
+func TestTickerDropsTicks(t *testing.T) {
+
+	f := New(start)
+	tk := f.NewTicker(time.Second)
+	defer tk.Stop()
+
+	f.Advance(10 * time.Second)
+	f.Advance(time.Second)
+	if !ticked(tk.C()) || ticked(tk.C()) {
+		t.Error("pending ticks were not dropped to one")
+	}
+	f.Advance(500 * time.Millisecond)
+	if ticked(tk.C()) {
+		t.Error("ticker fired off its schedule")
+	}
+
+}
+
+func TestSetAndStopDoNotFire(t *testing.T) {
+
+	f := New(start)
+	tk := f.NewTicker(time.Second)
+
+	f.Set(start.Add(time.Hour))
+	if ticked(tk.C()) {
+		t.Error("Set fired the ticker")
+	}
+	tk.Stop()
+	f.Advance(2 * time.Hour)
+	if ticked(tk.C()) {
+		t.Error("stopped ticker fired")
+	}
+
+}
+
+func TestNewTickerPanicsOnBadInterval(t *testing.T) {
+
+	defer func() {
+		if recover() == nil {
+			t.Error("NewTicker(0) did not panic")
+		}
+	}()
+	New(start).NewTicker(0)
+
+}
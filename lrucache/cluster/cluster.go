@@ -0,0 +1,252 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+// Package cluster partitions the key space of several cache nodes with a
+// consistent-hash ring, in the style of groupcache: every key has one
+// owner node, and Get on any node runs the loader for that key only on
+// its owner. Values fetched from other nodes can be kept in a small local
+// hot cache.
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/georghagn/nexcache/lrucache"
+)
+
+// DefaultBasePath is the URL path under which nodes serve each other.
+const DefaultBasePath = "/_nexcache/"
+
+// Getter loads the value of a key on its owner node.
+type Getter func(ctx context.Context, key string) (interface{}, error)
+
+// Fetcher retrieves a key from the peer at addr. The default fetcher
+// uses HTTP; values travel as JSON.
+type Fetcher interface {
+	Fetch(ctx context.Context, addr, key string) (interface{}, error)
+}
+
+// Node is one member of the cluster.
+type Node struct {
+	self    string
+	local   *lrucache.LRUCache // keys owned by this node
+	hot     *lrucache.LRUCache // copies of remote keys, may be nil
+	getter  Getter
+	fetcher Fetcher
+	base    string
+	ring    atomic.Pointer[Ring]
+	flights flightGroup
+}
+
+// Option configures a Node.
+type Option func(*Node)
+
+// WithHotCache keeps values fetched from other nodes in hot, so hot keys
+// are served locally instead of crossing the network on every Get. Size
+// and TTL of hot bound how stale such copies can get.
+func WithHotCache(hot *lrucache.LRUCache) Option {
+	return func(n *Node) {
+		n.hot = hot
+	}
+}
+
+// WithFetcher replaces the HTTP transport, e.g. with a gRPC client.
+func WithFetcher(f Fetcher) Option {
+	return func(n *Node) {
+		n.fetcher = f
+	}
+}
+
+// WithBasePath changes DefaultBasePath for serving and fetching.
+func WithBasePath(path string) Option {
+	return func(n *Node) {
+		n.base = path
+	}
+}
+
+// New creates the node self (its base URL, e.g. "http://10.0.0.1:8080")
+// that caches owned keys in local and loads them with getter.
+// peers must contain all nodes of the cluster, including self.
+func New(self string, peers []string, local *lrucache.LRUCache, getter Getter, opts ...Option) *Node {
+
+	n := &Node{self: self, local: local, getter: getter, base: DefaultBasePath}
+	for _, opt := range opts {
+		opt(n)
+	}
+	if n.fetcher == nil {
+		n.fetcher = &httpFetcher{client: http.DefaultClient, base: n.base}
+	}
+	n.SetPeers(peers...)
+	return n
+
+}
+
+// SetPeers replaces the cluster membership.
+func (n *Node) SetPeers(peers ...string) {
+	n.ring.Store(NewRing(64, peers...))
+}
+
+// Get returns the value for key. Keys owned by this node are served from
+// the local cache and loaded at most once at a time; other keys are
+// fetched from their owner.
+func (n *Node) Get(ctx context.Context, key string) (interface{}, error) {
+
+	owner := n.ring.Load().Owner(key)
+	if owner == "" || owner == n.self {
+		return n.load(ctx, key)
+	}
+
+	if n.hot != nil {
+		if val, found := n.hot.Get(key); found {
+			return val, nil
+		}
+	}
+	val, err := n.flights.do(ctx, key, func(ctx context.Context) (interface{}, error) {
+		return n.fetcher.Fetch(ctx, owner, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if n.hot != nil {
+		n.hot.Set(key, val)
+	}
+	return val, nil
+
+}
+
+// load serves an owned key, running the getter once for concurrent misses.
+func (n *Node) load(ctx context.Context, key string) (interface{}, error) {
+	return n.local.GetOrLoadContext(ctx, key, func(ctx context.Context) (interface{}, error) {
+		return n.flights.do(ctx, key, func(ctx context.Context) (interface{}, error) {
+			return n.getter(ctx, key)
+		})
+	})
+}
+
+// ServeHTTP answers fetches from other nodes. Mount it at the base path.
+func (n *Node) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	key, err := url.PathUnescape(strings.TrimPrefix(r.URL.EscapedPath(), n.base))
+	if err != nil || !strings.HasPrefix(r.URL.Path, n.base) {
+		http.Error(w, "bad key", http.StatusBadRequest)
+		return
+	}
+
+	val, err := n.load(r.Context(), key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(val)
+
+}
+
+// httpFetcher fetches keys from peers over HTTP.
+type httpFetcher struct {
+	client *http.Client
+	base   string
+}
+
+func (f *httpFetcher) Fetch(ctx context.Context, addr, key string) (interface{}, error) {
+
+	u := strings.TrimSuffix(addr, "/") + f.base + url.PathEscape(key)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("cluster: %s returned %s", addr, resp.Status)
+	}
+	var val interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&val); err != nil {
+		return nil, err
+	}
+	return val, nil
+
+}
+
+// flightGroup runs one call per key at a time; concurrent callers share
+// its result. The call runs on its own goroutine with a context that is
+// canceled only when every caller has given up, so one canceled caller
+// does not fail the others. A panic is returned as *lrucache.PanicError.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flight
+}
+
+type flight struct {
+	done    chan struct{}
+	val     interface{}
+	err     error
+	waiters int // guarded by flightGroup.mu
+	cancel  context.CancelFunc
+}
+
+func (g *flightGroup) do(ctx context.Context, key string, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flight)
+	}
+	f, ok := g.calls[key]
+	if !ok {
+		callCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		f = &flight{done: make(chan struct{}), cancel: cancel}
+		g.calls[key] = f
+		go g.run(callCtx, key, f, fn)
+	}
+	f.waiters++
+	g.mu.Unlock()
+
+	select {
+	case <-f.done:
+		return f.val, f.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		f.waiters--
+		if f.waiters == 0 {
+			// Nobody waits any more; later callers start a new call.
+			f.cancel()
+			if g.calls[key] == f {
+				delete(g.calls, key)
+			}
+		}
+		g.mu.Unlock()
+		return nil, ctx.Err()
+	}
+
+}
+
+func (g *flightGroup) run(ctx context.Context, key string, f *flight, fn func(ctx context.Context) (interface{}, error)) {
+
+	defer func() {
+		if r := recover(); r != nil {
+			f.val, f.err = nil, &lrucache.PanicError{Value: r, Stack: debug.Stack()}
+		}
+		g.mu.Lock()
+		if g.calls[key] == f {
+			delete(g.calls, key)
+		}
+		g.mu.Unlock()
+		f.cancel()
+		close(f.done)
+	}()
+
+	f.val, f.err = fn(ctx)
+
+}
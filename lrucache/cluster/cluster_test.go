@@ -0,0 +1,226 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/georghagn/nexcache/lrucache"
+)
+
+func TestRingOwner(t *testing.T) {
+
+	if owner := NewRing(8).Owner("k"); owner != "" {
+		t.Errorf("empty ring owner = %q, want none", owner)
+	}
+
+	r := NewRing(64, "a", "b", "c")
+	grown := NewRing(64, "a", "b", "c", "d")
+	moved := 0
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprint("key", i)
+		before, after := r.Owner(key), grown.Owner(key)
+		if before != r.Owner(key) {
+			t.Fatalf("owner of %s is not stable", key)
+		}
+		if before != after {
+			if after != "d" {
+				t.Fatalf("%s moved from %s to %s, not to the new node", key, before, after)
+			}
+			moved++
+		}
+	}
+	if moved == 0 || moved > 500 {
+		t.Errorf("%d of 1000 keys moved to the new node, want about a quarter", moved)
+	}
+
+}
+
+// newCluster starts two nodes on test servers. Each getter counts its
+// calls and answers with the name of its node.
+func newCluster(t *testing.T, opts ...Option) (a, b *Node, calls *atomic.Int64) {
+
+	calls = new(atomic.Int64)
+	var handlers [2]http.Handler
+	var servers [2]*httptest.Server
+	for i := range servers {
+		i := i
+		servers[i] = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			handlers[i].ServeHTTP(w, r)
+		}))
+		t.Cleanup(servers[i].Close)
+	}
+	peers := []string{servers[0].URL, servers[1].URL}
+
+	nodes := make([]*Node, 2)
+	for i := range nodes {
+		local := lrucache.New(100, time.Hour, time.Hour)
+		t.Cleanup(func() { local.Close() })
+		self := peers[i]
+		nodes[i] = New(self, peers, local, func(ctx context.Context, key string) (interface{}, error) {
+			calls.Add(1)
+			return self, nil
+		}, opts...)
+		handlers[i] = nodes[i]
+	}
+	return nodes[0], nodes[1], calls
+
+}
+
+// keyOwnedBy returns a key whose owner is n.
+func keyOwnedBy(t *testing.T, n *Node) string {
+
+	t.Helper()
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprint("key/", i)
+		if n.ring.Load().Owner(key) == n.self {
+			return key
+		}
+	}
+	t.Fatal("no key found for node")
+	return ""
+
+}
+
+// Every key is loaded on its owner, whichever node is asked.
+func TestGetLoadsOnOwner(t *testing.T) {
+
+	a, b, calls := newCluster(t)
+	key := keyOwnedBy(t, b)
+
+	for _, n := range []*Node{a, b, a} {
+		val, err := n.Get(context.Background(), key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if val != b.self {
+			t.Errorf("Get = %v, want the value loaded by the owner %s", val, b.self)
+		}
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("getter ran %d times, want 1", n)
+	}
+	if !b.local.Contains(key) || a.local.Contains(key) {
+		t.Error("the key is not cached on its owner only")
+	}
+
+}
+
+func TestHotCacheServesRemoteKeys(t *testing.T) {
+
+	hot := lrucache.New(10, time.Minute, time.Hour)
+	defer hot.Close()
+	a, b, _ := newCluster(t)
+	WithHotCache(hot)(a)
+	key := keyOwnedBy(t, b)
+
+	if _, err := a.Get(context.Background(), key); err != nil {
+		t.Fatal(err)
+	}
+	if !hot.Contains(key) {
+		t.Fatal("fetched value was not kept in the hot cache")
+	}
+	b.local.Delete(key)
+	hot.Set(key, "hot copy")
+	if val, _ := a.Get(context.Background(), key); val != "hot copy" {
+		t.Errorf("Get = %v, want the hot copy", val)
+	}
+
+}
+
+func TestGetterErrorReachesRemoteCaller(t *testing.T) {
+
+	a, b, _ := newCluster(t)
+	b.getter = func(ctx context.Context, key string) (interface{}, error) {
+		return nil, errors.New("backend down")
+	}
+	key := keyOwnedBy(t, b)
+
+	if _, err := a.Get(context.Background(), key); err == nil {
+		t.Error("Get of a failing remote key succeeded")
+	}
+
+}
+
+// waiters returns the number of callers waiting for key.
+func (g *flightGroup) waiters(key string) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if f := g.calls[key]; f != nil {
+		return f.waiters
+	}
+	return 0
+}
+
+// The first caller giving up must not cancel the call for the others.
+func TestFlightOutlivesFirstCaller(t *testing.T) {
+
+	var g flightGroup
+	release := make(chan struct{})
+	var callErr error
+	fn := func(ctx context.Context) (interface{}, error) {
+		<-release
+		callErr = ctx.Err()
+		return "v", nil
+	}
+
+	first, cancel := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := g.do(first, "k", fn)
+		firstErr <- err
+	}()
+	var wg sync.WaitGroup
+	var val interface{}
+	var err error
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		val, err = g.do(context.Background(), "k", fn)
+	}()
+	for g.waiters("k") < 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	cancel()
+	if err := <-firstErr; err != context.Canceled {
+		t.Errorf("first caller got %v, want context.Canceled", err)
+	}
+	close(release)
+	wg.Wait()
+	if val != "v" || err != nil {
+		t.Errorf("second caller got %v, %v, want v", val, err)
+	}
+	if callErr != nil {
+		t.Errorf("call saw a canceled context: %v", callErr)
+	}
+
+}
+
+func TestFlightPanicIsReturned(t *testing.T) {
+
+	var g flightGroup
+	_, err := g.do(context.Background(), "k", func(ctx context.Context) (interface{}, error) {
+		panic("boom")
+	})
+
+	var pe *lrucache.PanicError
+	if !errors.As(err, &pe) || pe.Value != "boom" {
+		t.Fatalf("err = %v, want a PanicError for boom", err)
+	}
+	if val, err := g.do(context.Background(), "k", func(ctx context.Context) (interface{}, error) {
+		return "v", nil
+	}); val != "v" || err != nil {
+		t.Errorf("call after the panic = %v, %v, want v", val, err)
+	}
+
+}
@@ -0,0 +1,54 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package cluster
+
+import (
+	"hash/crc32"
+	"sort"
+	"strconv"
+)
+
+// Ring maps keys to nodes by consistent hashing. Each node is placed on
+// the ring replicas times, so keys spread evenly and only about 1/N of
+// them move when a node joins or leaves. A Ring is not safe for
+// concurrent modification; Node replaces its ring atomically.
+type Ring struct {
+	replicas int
+	hashes   []uint32
+	nodes    map[uint32]string
+}
+
+// NewRing creates a ring with replicas virtual points per node.
+func NewRing(replicas int, nodes ...string) *Ring {
+
+	if replicas < 1 {
+		replicas = 1
+	}
+	r := &Ring{replicas: replicas, nodes: make(map[uint32]string)}
+	for _, node := range nodes {
+		for i := 0; i < replicas; i++ {
+			h := crc32.ChecksumIEEE([]byte(strconv.Itoa(i) + node))
+			r.hashes = append(r.hashes, h)
+			r.nodes[h] = node
+		}
+	}
+	sort.Slice(r.hashes, func(i, j int) bool { return r.hashes[i] < r.hashes[j] })
+	return r
+
+}
+
+// Owner returns the node responsible for key, or "" for an empty ring.
+func (r *Ring) Owner(key string) string {
+
+	if len(r.hashes) == 0 {
+		return ""
+	}
+	h := crc32.ChecksumIEEE([]byte(key))
+	i := sort.Search(len(r.hashes), func(i int) bool { return r.hashes[i] >= h })
+	if i == len(r.hashes) {
+		i = 0
+	}
+	return r.nodes[r.hashes[i]]
+
+}
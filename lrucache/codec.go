@@ -0,0 +1,61 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"io"
+)
+
+// ---------------------- Codecs ----------------------
+
+// Encoder writes values to an underlying stream.
+// *json.Encoder and *gob.Encoder satisfy it.
+type Encoder interface {
+	Encode(v interface{}) error
+}
+
+// Decoder reads values from an underlying stream.
+// *json.Decoder and *gob.Decoder satisfy it.
+type Decoder interface {
+	Decode(v interface{}) error
+}
+
+// Codec defines the encoding used by SaveToFile and LoadFromFile.
+// Further formats (e.g. msgpack) can be plugged in by implementing it.
+type Codec interface {
+	NewEncoder(w io.Writer) Encoder
+	NewDecoder(r io.Reader) Decoder
+}
+
+var (
+	// JSONCodec encodes snapshots as JSON (default). Human readable, but
+	// type information is lost: structs come back as map[string]interface{}
+	// and numbers as float64, unless the type was registered with
+	// RegisterType.
+	JSONCodec Codec = jsonCodec{}
+
+	// GobCodec encodes snapshots with encoding/gob and keeps the Go types
+	// of the values. Concrete types stored as values must be registered
+	// with gob.Register before saving and loading.
+	GobCodec Codec = gobCodec{}
+)
+
+// WithCodec selects the encoding for persistence.
+func WithCodec(codec Codec) Option {
+	return func(c *LRUCache) {
+		c.codec = codec
+	}
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) NewEncoder(w io.Writer) Encoder { return json.NewEncoder(w) }
+func (jsonCodec) NewDecoder(r io.Reader) Decoder { return json.NewDecoder(r) }
+
+type gobCodec struct{}
+
+func (gobCodec) NewEncoder(w io.Writer) Encoder { return gob.NewEncoder(w) }
+func (gobCodec) NewDecoder(r io.Reader) Decoder { return gob.NewDecoder(r) }
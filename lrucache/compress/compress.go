@@ -0,0 +1,93 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+// Package compress provides lrucache.Compressor implementations for the
+// compression formats of the standard library. Other formats such as zstd
+// or snappy can be plugged in by implementing lrucache.Compressor.
+package compress
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"io"
+
+	"github.com/georghagn/nexcache/lrucache"
+)
+
+// ---------------------- Gzip ----------------------
+
+type gzipCompressor struct {
+	level int
+}
+
+// Gzip returns a Compressor using the gzip format with the given level,
+// for example gzip.BestSpeed or gzip.DefaultCompression.
+func Gzip(level int) lrucache.Compressor {
+	return gzipCompressor{level: level}
+}
+
+func (g gzipCompressor) Compress(data []byte) ([]byte, error) {
+
+	var buf bytes.Buffer
+	w, err := gzip.NewWriterLevel(&buf, g.level)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+
+}
+
+func (g gzipCompressor) Decompress(data []byte) ([]byte, error) {
+
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+
+}
+
+// ---------------------- Flate ----------------------
+
+type flateCompressor struct {
+	level int
+}
+
+// Flate returns a Compressor using raw DEFLATE with the given level. It
+// has less framing overhead than Gzip, which pays off for small values.
+func Flate(level int) lrucache.Compressor {
+	return flateCompressor{level: level}
+}
+
+func (f flateCompressor) Compress(data []byte) ([]byte, error) {
+
+	var buf bytes.Buffer
+	w, err := flate.NewWriter(&buf, f.level)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+
+}
+
+func (f flateCompressor) Decompress(data []byte) ([]byte, error) {
+
+	r := flate.NewReader(bytes.NewReader(data))
+	defer r.Close()
+	return io.ReadAll(r)
+
+}
@@ -0,0 +1,91 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package compress
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"crypto/rand"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/georghagn/nexcache/lrucache"
+)
+
+var compressors = map[string]lrucache.Compressor{
+	"gzip":  Gzip(gzip.BestSpeed),
+	"flate": Flate(flate.DefaultCompression),
+}
+
+func TestRoundTrip(t *testing.T) {
+
+	data := []byte(strings.Repeat("nexcache ", 1000))
+	for name, comp := range compressors {
+		packed, err := comp.Compress(data)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if len(packed) >= len(data) {
+			t.Errorf("%s: %d bytes compressed to %d", name, len(data), len(packed))
+		}
+		out, err := comp.Decompress(packed)
+		if err != nil || !bytes.Equal(out, data) {
+			t.Errorf("%s: round trip = %d bytes, %v", name, len(out), err)
+		}
+	}
+
+}
+
+func TestErrors(t *testing.T) {
+
+	if _, err := Gzip(42).Compress([]byte("x")); err == nil {
+		t.Error("gzip accepted an invalid level")
+	}
+	if _, err := Flate(42).Compress([]byte("x")); err == nil {
+		t.Error("flate accepted an invalid level")
+	}
+	for name, comp := range compressors {
+		if _, err := comp.Decompress([]byte("not compressed")); err == nil {
+			t.Errorf("%s: decompressing garbage succeeded", name)
+		}
+	}
+
+}
+
+// In a cache, values come back with their type, and only values above
+// the threshold that shrink are kept compressed.
+func TestWithCompression(t *testing.T) {
+
+	for name, comp := range compressors {
+		c := lrucache.New(10, time.Hour, time.Hour, lrucache.WithCompression(comp, 64))
+
+		text := strings.Repeat("a", 1000)
+		noise := make([]byte, 1000)
+		rand.Read(noise)
+		c.Set("small", "tiny")
+		c.Set("noise", noise)
+		if s := c.Stats(); s.CompressedIn != 0 {
+			t.Errorf("%s: small or incompressible values were compressed: %+v", name, s)
+		}
+
+		c.Set("text", text)
+		c.Set("bytes", []byte(text))
+		if val, _ := c.Get("text"); val != text {
+			t.Errorf("%s: string value came back as %T", name, val)
+		}
+		if val, _ := c.Get("bytes"); !bytes.Equal(val.([]byte), []byte(text)) {
+			t.Errorf("%s: []byte value changed", name)
+		}
+		if val, _ := c.Get("noise"); !bytes.Equal(val.([]byte), noise) {
+			t.Errorf("%s: incompressible value changed", name)
+		}
+		if ratio := c.Stats().CompressionRatio(); ratio <= 0 || ratio >= 0.5 {
+			t.Errorf("%s: compression ratio = %v", name, ratio)
+		}
+		c.Close()
+	}
+
+}
@@ -0,0 +1,86 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+// ---------------------- Value compression ----------------------
+
+// Compressor compresses and decompresses value payloads. Implementations
+// for the standard library formats live in package lrucache/compress.
+type Compressor interface {
+	Compress(data []byte) ([]byte, error)
+	Decompress(data []byte) ([]byte, error)
+}
+
+// compressedValue is the stored form of a compressed string or []byte value.
+type compressedValue struct {
+	comp Compressor
+	data []byte
+	str  bool // original value was a string
+}
+
+// WithCompression compresses string and []byte values larger than
+// threshold bytes when they are stored and decompresses them on read.
+// A value is kept uncompressed if compression does not make it smaller.
+// Compressed values are not deduplicated by WithValueDedup, and every read
+// returns a fresh copy. Persistence, the WAL, events and the Store always
+// see the uncompressed value.
+func WithCompression(comp Compressor, threshold int) Option {
+	return func(c *LRUCache) {
+		c.compressor = comp
+		c.compressMin = threshold
+	}
+}
+
+// compress returns the form in which value is kept in the cache.
+func (c *LRUCache) compress(value interface{}) interface{} {
+
+	if c.compressor == nil {
+		return value
+	}
+
+	var data []byte
+	str := false
+	switch v := value.(type) {
+	case string:
+		if len(v) <= c.compressMin {
+			return value
+		}
+		data, str = []byte(v), true
+	case []byte:
+		if len(v) <= c.compressMin {
+			return value
+		}
+		data = v
+	default:
+		return value
+	}
+
+	packed, err := c.compressor.Compress(data)
+	if err != nil || len(packed) >= len(data) {
+		return value
+	}
+	c.compressedIn.Add(uint64(len(data)))
+	c.compressedOut.Add(uint64(len(packed)))
+	return &compressedValue{comp: c.compressor, data: packed, str: str}
+
+}
+
+// plain returns the value of entry with compression undone. Lazily loaded
+// values are returned as they are.
+func plain(entry *CacheEntry) interface{} {
+
+	packed, ok := entry.Value.(*compressedValue)
+	if !ok {
+		return entry.Value
+	}
+	data, err := packed.comp.Decompress(packed.data)
+	if err != nil {
+		return nil
+	}
+	if packed.str {
+		return string(data)
+	}
+	return data
+
+}
@@ -0,0 +1,119 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"time"
+)
+
+// ---------------------- Conditional writes ----------------------
+
+// Add stores value only if key is missing or expired and reports whether
+// the value was stored. Check and insert happen under a single lock
+// acquisition, so Add can be used for lock-style "insert if absent" logic.
+func (c *LRUCache) Add(key string, value interface{}) bool {
+	return c.AddWithTTL(key, value, c.ttl)
+}
+
+// AddWithTTL is like Add, but uses ttl instead of the cache's default TTL.
+func (c *LRUCache) AddWithTTL(key string, value interface{}, ttl time.Duration) bool {
+
+	c.acquireWrite()
+	defer c.releaseWrite()
+
+	c.lock()
+	defer c.unlock()
+
+	if c.closedLocked() {
+		return false
+	}
+
+	now := c.clock.Now()
+	if element, found := c.cache[key]; found {
+		if !element.Value.(*CacheEntry).expired(now) {
+			return false
+		}
+	}
+
+	c.putLocked(key, value, c.deadline(now, ttl)).ttl = ttl
+	return true
+
+}
+
+// CompareAndSwap replaces the value of key with new if the current value
+// equals old, and reports whether it did. Missing and expired entries never
+// match. Like Set, a successful swap resets the TTL. Values are compared
+// with ==, so CompareAndSwap panics if old and the current value have the
+// same non-comparable type.
+func (c *LRUCache) CompareAndSwap(key string, old, new interface{}) bool {
+
+	c.acquireWrite()
+	defer c.releaseWrite()
+
+	c.lock()
+	defer c.unlock()
+
+	if c.closedLocked() {
+		return false
+	}
+
+	now := c.clock.Now()
+	element, found := c.cache[key]
+	if !found {
+		return false
+	}
+	entry := element.Value.(*CacheEntry)
+	if entry.expired(now) || hydrate(entry) != old {
+		return false
+	}
+
+	c.putLocked(key, new, c.deadline(now, c.ttl))
+	return true
+
+}
+
+// Update atomically runs a read-modify-write cycle on key. fn receives the
+// current value (nil, false if missing or expired) and returns the new value
+// and whether to store it; if it returns false, the entry is left unchanged.
+// fn runs while the cache is locked and must not call methods of the cache.
+func (c *LRUCache) Update(key string, fn func(old interface{}, exists bool) (interface{}, bool)) {
+	c.update(key, fn, c.ttl, false)
+}
+
+// UpdateWithTTL is like Update, but a stored value gets ttl instead of the
+// cache's default TTL.
+func (c *LRUCache) UpdateWithTTL(key string, ttl time.Duration, fn func(old interface{}, exists bool) (interface{}, bool)) {
+	c.update(key, fn, ttl, true)
+}
+
+func (c *LRUCache) update(key string, fn func(old interface{}, exists bool) (interface{}, bool), ttl time.Duration, own bool) {
+
+	c.acquireWrite()
+	defer c.releaseWrite()
+
+	c.lock()
+	defer c.unlock()
+
+	if c.closedLocked() {
+		return
+	}
+
+	now := c.clock.Now()
+	var old interface{}
+	exists := false
+	if element, found := c.cache[key]; found {
+		entry := element.Value.(*CacheEntry)
+		if !entry.expired(now) {
+			old, exists = hydrate(entry), true
+		}
+	}
+
+	if value, store := c.runLocked(fn, old, exists); store {
+		entry := c.putLocked(key, value, c.deadline(now, ttl))
+		if own {
+			entry.ttl = ttl
+		}
+	}
+
+}
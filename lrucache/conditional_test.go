@@ -0,0 +1,99 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/georghagn/nexcache/lrucache"
+)
+
+func TestAddOnlyIfAbsent(t *testing.T) {
+
+	clock := newClock()
+	c := lrucache.New(10, time.Minute, time.Hour, lrucache.WithClock(clock))
+	defer c.Close()
+
+	if !c.Add("k", 1) || c.Add("k", 2) {
+		t.Fatal("Add must store only the first value")
+	}
+	clock.Advance(2 * time.Minute)
+	if !c.AddWithTTL("k", 3, time.Second) {
+		t.Error("Add did not replace an expired entry")
+	}
+	if ttl, _ := c.TTL("k"); ttl != time.Second {
+		t.Errorf("TTL = %v, want 1s", ttl)
+	}
+
+}
+
+func TestCompareAndSwap(t *testing.T) {
+
+	c := lrucache.New(10, time.Hour, time.Hour)
+	defer c.Close()
+	c.Set("k", 1)
+
+	if c.CompareAndSwap("k", 2, 3) {
+		t.Error("swap with a wrong old value succeeded")
+	}
+	if !c.CompareAndSwap("k", 1, 3) {
+		t.Error("swap with the current value failed")
+	}
+	if c.CompareAndSwap("missing", nil, 1) {
+		t.Error("swap of a missing key succeeded")
+	}
+	if val, _ := c.Get("k"); val != 3 {
+		t.Errorf("value = %v, want 3", val)
+	}
+
+}
+
+func TestUpdateIsAtomic(t *testing.T) {
+
+	c := lrucache.New(10, time.Hour, time.Hour)
+	defer c.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.Update("n", func(old interface{}, exists bool) (interface{}, bool) {
+				if !exists {
+					return 1, true
+				}
+				return old.(int) + 1, true
+			})
+		}()
+	}
+	wg.Wait()
+
+	if val, _ := c.Get("n"); val != 50 {
+		t.Errorf("n = %v after 50 updates", val)
+	}
+	c.Update("n", func(interface{}, bool) (interface{}, bool) { return 0, false })
+	if val, _ := c.Get("n"); val != 50 {
+		t.Error("Update stored a value although fn declined")
+	}
+
+}
+
+// When the cache is full, the lowest priority is evicted first.
+func TestSetWithPriority(t *testing.T) {
+
+	c := lrucache.New(2, time.Hour, time.Hour)
+	defer c.Close()
+
+	c.SetWithPriority("expensive", 1, 10)
+	c.SetWithPriority("cheap", 2, 0)
+	c.Get("cheap")
+	c.Set("new", 3)
+
+	if !c.Contains("expensive") || c.Contains("cheap") {
+		t.Errorf("keys = %v, want expensive kept over the recently used cheap entry", c.Keys())
+	}
+
+}
@@ -0,0 +1,166 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"reflect"
+	"time"
+)
+
+// ---------------------- Value copies ----------------------
+
+// CopyMode selects when the value copier runs. The modes can be combined.
+type CopyMode int
+
+const (
+	// CopyOnSet stores a copy of every written value, so the writer can
+	// keep modifying its own value.
+	CopyOnSet CopyMode = 1 << iota
+
+	// CopyOnGet hands every reader its own copy, so a reader modifying the
+	// result does not change what others see.
+	CopyOnGet
+)
+
+// WithValueCopier makes the cache copy values with fn, to prevent bugs
+// from callers modifying a cached map, slice or struct behind a pointer.
+// A nil fn uses DeepCopy. By default values are copied on both Set and
+// Get; use WithCopyMode to choose. Copies on Set are made under the cache
+// lock. Get here covers Get, Lookup, GetMulti, GetWithExpiry, Peek, fork
+// reads and the GetOrLoad family. Since readers receive copies, pointer
+// values no longer match in CompareAndSwap.
+func WithValueCopier(fn func(v interface{}) interface{}) Option {
+	return func(c *LRUCache) {
+		if fn == nil {
+			fn = DeepCopy
+		}
+		c.copier = fn
+		if c.copyMode == 0 {
+			c.copyMode = CopyOnSet | CopyOnGet
+		}
+	}
+}
+
+// WithCopyMode selects when the value copier of WithValueCopier runs.
+func WithCopyMode(mode CopyMode) Option {
+	return func(c *LRUCache) {
+		c.copyMode = mode
+	}
+}
+
+// copyIn returns the value to store for a written value.
+func (c *LRUCache) copyIn(value interface{}) interface{} {
+	if c.copier == nil || c.copyMode&CopyOnSet == 0 {
+		return value
+	}
+	return c.copier(value)
+}
+
+// copyOut returns the value to hand to a reader.
+func (c *LRUCache) copyOut(value interface{}) interface{} {
+	if c.copier == nil || c.copyMode&CopyOnGet == 0 || value == nil {
+		return value
+	}
+	return c.copier(value)
+}
+
+// DeepCopy returns a deep copy of v, the default copier of
+// WithValueCopier. It copies maps, slices, arrays, pointers, interfaces
+// and the exported fields of structs recursively, keeping shared and
+// cyclic references intact. Unexported struct fields, channels and
+// functions are copied shallowly.
+func DeepCopy(v interface{}) interface{} {
+
+	switch v := v.(type) {
+	case nil, bool, string, int, int64, uint64, float64, time.Time:
+		return v
+	case []byte:
+		if v == nil {
+			return v
+		}
+		return append([]byte{}, v...)
+	}
+
+	return deepCopy(reflect.ValueOf(v), make(map[copyRef]reflect.Value)).Interface()
+
+}
+
+// copyRef identifies an already copied pointer or map. The type is part
+// of it because a struct and its first field share an address.
+type copyRef struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+func deepCopy(v reflect.Value, seen map[copyRef]reflect.Value) reflect.Value {
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		ref := copyRef{v.Pointer(), v.Type()}
+		if out, ok := seen[ref]; ok {
+			return out
+		}
+		out := reflect.New(v.Type().Elem())
+		seen[ref] = out
+		out.Elem().Set(deepCopy(v.Elem(), seen))
+		return out
+
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		ref := copyRef{v.Pointer(), v.Type()}
+		if out, ok := seen[ref]; ok {
+			return out
+		}
+		out := reflect.MakeMapWithSize(v.Type(), v.Len())
+		seen[ref] = out
+		iter := v.MapRange()
+		for iter.Next() {
+			out.SetMapIndex(deepCopy(iter.Key(), seen), deepCopy(iter.Value(), seen))
+		}
+		return out
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(deepCopy(v.Index(i), seen))
+		}
+		return out
+
+	case reflect.Array:
+		out := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(deepCopy(v.Index(i), seen))
+		}
+		return out
+
+	case reflect.Struct:
+		out := reflect.New(v.Type()).Elem()
+		out.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if field := out.Field(i); field.CanSet() {
+				field.Set(deepCopy(v.Field(i), seen))
+			}
+		}
+		return out
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type()).Elem()
+		out.Set(deepCopy(v.Elem(), seen))
+		return out
+	}
+
+	return v
+
+}
@@ -0,0 +1,89 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"errors"
+	"math"
+)
+
+// ---------------------- Counters ----------------------
+
+// ErrNotNumeric is returned by Increment and Decrement if the existing
+// value is not an integer.
+var ErrNotNumeric = errors.New("lrucache: value is not an integer")
+
+// Increment atomically adds delta to the integer stored under key and
+// returns the new value, which is stored as int64. A missing or expired key
+// is created with the value delta and the default TTL; an existing entry
+// keeps its expiry, its own TTL and its idle limit. Any Go integer type is
+// accepted as existing value, as are float64 values without fraction (as
+// produced by JSON persistence).
+func (c *LRUCache) Increment(key string, delta int64) (int64, error) {
+
+	c.acquireWrite()
+	defer c.releaseWrite()
+
+	c.lock()
+	defer c.unlock()
+
+	if c.closedLocked() {
+		return 0, ErrClosed
+	}
+
+	now := c.clock.Now()
+	if element, found := c.cache[key]; found {
+		entry := element.Value.(*CacheEntry)
+		if !entry.expired(now) {
+			n, ok := toInt64(hydrate(entry))
+			if !ok {
+				return 0, ErrNotNumeric
+			}
+			n += delta
+			ttl, idle := entry.ttl, entry.idle
+			c.putLocked(key, n, entry.ExpiresAt)
+			entry.ttl, entry.idle = ttl, idle
+			return n, nil
+		}
+	}
+
+	c.putLocked(key, delta, c.deadline(now, c.ttl))
+	return delta, nil
+
+}
+
+// Decrement atomically subtracts delta; see Increment.
+func (c *LRUCache) Decrement(key string, delta int64) (int64, error) {
+	return c.Increment(key, -delta)
+}
+
+func toInt64(value interface{}) (int64, bool) {
+
+	switch v := value.(type) {
+	case int:
+		return int64(v), true
+	case int8:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint:
+		return int64(v), v <= math.MaxInt64
+	case uint8:
+		return int64(v), true
+	case uint16:
+		return int64(v), true
+	case uint32:
+		return int64(v), true
+	case uint64:
+		return int64(v), v <= math.MaxInt64
+	case float64:
+		return int64(v), v == math.Trunc(v) && v >= math.MinInt64 && v < math.MaxInt64
+	}
+	return 0, false
+
+}
@@ -0,0 +1,66 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/georghagn/nexcache/lrucache"
+	"github.com/georghagn/nexcache/lrucache/clocktest"
+)
+
+func TestIncrementKeepsOwnTTL(t *testing.T) {
+
+	clock := clocktest.New(time.Unix(0, 0))
+	c := lrucache.New(10, time.Hour, time.Hour, lrucache.WithClock(clock), lrucache.WithSlidingTTL(true))
+	defer c.Close()
+
+	c.SetWithTTL("n", 1, time.Second)
+	if n, err := c.Increment("n", 2); err != nil || n != 3 {
+		t.Fatalf("Increment = %d, %v, want 3", n, err)
+	}
+	c.Get("n") // sliding renewal with the entry's own TTL
+	if ttl, _ := c.TTL("n"); ttl != time.Second {
+		t.Errorf("TTL after Increment and Get = %v, want 1s", ttl)
+	}
+
+	clock.Advance(2 * time.Second)
+	if _, found := c.Get("n"); found {
+		t.Error("counter outlived its own TTL")
+	}
+
+}
+
+func TestIncrementKeepsIdleLimit(t *testing.T) {
+
+	clock := clocktest.New(time.Unix(0, 0))
+	c := lrucache.New(10, time.Hour, time.Hour, lrucache.WithClock(clock))
+	defer c.Close()
+
+	c.SetWithMaxIdle("n", 1, time.Second)
+	if _, err := c.Increment("n", 1); err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(2 * time.Second)
+	if _, found := c.Get("n"); found {
+		t.Error("counter outlived its idle limit")
+	}
+
+}
+
+func TestIncrementCreatesAndRejects(t *testing.T) {
+
+	c := lrucache.New(10, time.Hour, time.Hour)
+	defer c.Close()
+
+	if n, err := c.Decrement("n", 4); err != nil || n != -4 {
+		t.Fatalf("Decrement on missing key = %d, %v, want -4", n, err)
+	}
+	c.Set("s", "text")
+	if _, err := c.Increment("s", 1); err != lrucache.ErrNotNumeric {
+		t.Errorf("Increment on a string = %v, want ErrNotNumeric", err)
+	}
+
+}
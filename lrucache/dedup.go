@@ -0,0 +1,93 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"bytes"
+	"crypto/sha256"
+)
+
+// ---------------------- Value deduplication ----------------------
+
+// sharedValue is a payload stored once for all entries holding identical content.
+type sharedValue struct {
+	digest [sha256.Size]byte
+	value  interface{}
+	refs   int
+}
+
+// WithValueDedup stores identical string and []byte values of at least
+// minSize bytes only once, no matter under how many keys they are set.
+// Values are identified by their SHA-256 digest and reference counted.
+// Shared []byte values must not be modified by callers.
+func WithValueDedup(minSize int) Option {
+	return func(c *LRUCache) {
+		c.dedupMin = minSize
+		c.dedup = make(map[[sha256.Size]byte]*sharedValue)
+	}
+}
+
+// share sets the value of entry, replacing it with an identical shared
+// instance if deduplication applies. Must be called with c.mu held.
+func (c *LRUCache) share(entry *CacheEntry, value interface{}) {
+
+	entry.Value = value
+	if c.dedup == nil {
+		return
+	}
+
+	var content []byte
+	switch v := value.(type) {
+	case string:
+		if len(v) < c.dedupMin {
+			return
+		}
+		content = []byte(v)
+	case []byte:
+		if len(v) < c.dedupMin {
+			return
+		}
+		content = v
+	default:
+		return
+	}
+
+	digest := sha256.Sum256(content)
+	shared, found := c.dedup[digest]
+	if !found {
+		shared = &sharedValue{digest: digest, value: value}
+		c.dedup[digest] = shared
+	} else if !sameContent(shared.value, content) {
+		return
+	}
+	shared.refs++
+	entry.Value = shared.value
+	entry.shared = shared
+
+}
+
+// unshare drops the reference of entry to its shared value.
+// Must be called with c.mu held.
+func (c *LRUCache) unshare(entry *CacheEntry) {
+
+	shared := entry.shared
+	if shared == nil {
+		return
+	}
+	entry.shared = nil
+	if shared.refs--; shared.refs == 0 {
+		delete(c.dedup, shared.digest)
+	}
+
+}
+
+func sameContent(value interface{}, content []byte) bool {
+	switch v := value.(type) {
+	case string:
+		return v == string(content)
+	case []byte:
+		return bytes.Equal(v, content)
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"path"
+	"strings"
+)
+
+// ---------------------- Bulk deletion ----------------------
+
+// DeletePrefix removes all keys starting with prefix and returns how many
+// live entries were removed. Unlike InvalidatePrefix it runs synchronously
+// under a single lock acquisition and, like Delete, reaches the backing
+// store.
+func (c *LRUCache) DeletePrefix(prefix string) int {
+	return c.DeleteFunc(func(key string) bool {
+		return strings.HasPrefix(key, prefix)
+	})
+}
+
+// DeleteMatch removes all keys matching the glob pattern (see path.Match,
+// e.g. "user:*:session") and returns how many live entries were removed.
+// It fails only for a malformed pattern.
+func (c *LRUCache) DeleteMatch(pattern string) (int, error) {
+
+	if _, err := path.Match(pattern, ""); err != nil {
+		return 0, err
+	}
+	return c.DeleteFunc(func(key string) bool {
+		ok, _ := path.Match(pattern, key)
+		return ok
+	}), nil
+
+}
+
+// DeleteFunc removes all keys for which match returns true and returns how
+// many live entries were removed. match runs under the cache lock and must
+// not call into the cache.
+func (c *LRUCache) DeleteFunc(match func(key string) bool) int {
+
+	c.lock()
+	defer c.unlock()
+
+	if c.closedLocked() {
+		return 0
+	}
+
+	now := c.clock.Now()
+	removed := 0
+	for key, element := range c.cache {
+		if !match(key) {
+			continue
+		}
+		if !element.Value.(*CacheEntry).expired(now) {
+			removed++
+		}
+		c.removeElement(element)
+		c.logDelete(key)
+		c.storeDelete(key)
+	}
+	return removed
+
+}
@@ -0,0 +1,87 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/georghagn/nexcache/lrucache"
+)
+
+func TestBulkDelete(t *testing.T) {
+
+	clock := newClock()
+	store := newMemStore()
+	c := lrucache.New(10, time.Minute, time.Hour, lrucache.WithClock(clock), lrucache.WithWriteThrough(store))
+	defer c.Close()
+
+	c.SetWithTTL("user:1:expired", 0, time.Second)
+	clock.Advance(2 * time.Second)
+	for _, key := range []string{"user:1:session", "user:2:session", "user:2:profile", "order:1"} {
+		c.Set(key, 0)
+	}
+
+	if n, err := c.DeleteMatch("user:*:session"); err != nil || n != 2 {
+		t.Errorf("DeleteMatch = %d, %v, want 2", n, err)
+	}
+	if n := c.DeletePrefix("user:"); n != 1 {
+		t.Errorf("DeletePrefix = %d, want 1 live entry", n)
+	}
+	if n := c.DeleteFunc(func(key string) bool { return strings.HasSuffix(key, ":1") }); n != 1 {
+		t.Errorf("DeleteFunc = %d, want 1", n)
+	}
+	if c.Len() != 0 {
+		t.Errorf("keys left: %v", c.Keys())
+	}
+	if _, found := store.get("user:2:profile"); found {
+		t.Error("bulk deletion did not reach the store")
+	}
+	if _, err := c.DeleteMatch("["); err == nil {
+		t.Error("malformed pattern was accepted")
+	}
+
+}
+
+func TestInvalidateSubtree(t *testing.T) {
+
+	for name, opts := range map[string][]lrucache.Option{"trie": {lrucache.WithKeyHierarchy("/")}, "scan": nil} {
+		c := lrucache.New(10, time.Hour, time.Hour, opts...)
+		for _, key := range []string{"org/1", "org/1/project/2", "org/12", "org/2/x"} {
+			c.Set(key, 0)
+		}
+		if n := c.InvalidateSubtree("org/1"); n != 2 {
+			t.Errorf("%s: InvalidateSubtree = %d, want 2", name, n)
+		}
+		if !c.Contains("org/12") || !c.Contains("org/2/x") || c.Contains("org/1/project/2") {
+			t.Errorf("%s: keys = %v, want org/12 and org/2/x", name, c.Keys())
+		}
+		c.Close()
+	}
+
+}
+
+func TestClearAndPurge(t *testing.T) {
+
+	c := lrucache.New(10, time.Hour, time.Hour)
+	defer c.Close()
+	var reasons []lrucache.EvictReason
+	c.OnEvict(func(_ string, _ interface{}, reason lrucache.EvictReason) { reasons = append(reasons, reason) })
+
+	c.Set("a", 1)
+	c.Get("a")
+	c.Clear()
+	if c.Len() != 0 || len(reasons) != 1 || reasons[0] != lrucache.EvictCleared {
+		t.Errorf("after Clear: Len %d, reasons %v", c.Len(), reasons)
+	}
+	if c.Stats().Hits != 1 {
+		t.Error("Clear reset the statistics")
+	}
+	c.Purge()
+	if c.Stats().Hits != 0 {
+		t.Error("Purge kept the statistics")
+	}
+
+}
@@ -0,0 +1,129 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+// Package diskstore is an lrucache.Store that keeps one file per key in a
+// directory. Combined with package tiered it serves as a disk overflow for
+// an in-memory cache without any external service:
+//
+//	disk, err := diskstore.Open("/var/cache/app")
+//	...
+//	cache := tiered.New(lrucache.New(10000, ttl, time.Minute), disk, tiered.WithDemotion())
+//
+// Entries evicted from memory are then spilled to disk, and Get falls back
+// to disk and promotes hits back into memory.
+//
+// Every key is written on its own; there are no writes spanning several
+// keys and no compaction. An embedded database such as bbolt can be used
+// instead through the lrucache.Store interface.
+package diskstore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/georghagn/nexcache/lrucache"
+)
+
+// record is the on-disk form of an entry. The key is kept to detect
+// hash collisions.
+type record struct {
+	Key   string
+	Value interface{}
+}
+
+// Store is a directory-backed lrucache.Store. It is safe for concurrent
+// use: every write replaces the file of its key atomically.
+type Store struct {
+	dir   string
+	codec lrucache.Codec
+}
+
+// Option configures a Store.
+type Option func(*Store)
+
+// WithCodec selects the encoding of the files (lrucache.JSONCodec by
+// default). With lrucache.GobCodec the Go types of values are kept.
+func WithCodec(codec lrucache.Codec) Option {
+	return func(s *Store) {
+		s.codec = codec
+	}
+}
+
+// Open returns a Store in dir, creating the directory if needed. Existing
+// files are kept, so the content survives restarts.
+func Open(dir string, opts ...Option) (*Store, error) {
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	s := &Store{dir: dir, codec: lrucache.JSONCodec}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
+
+}
+
+// Load returns the value stored for key, or lrucache.ErrNotFound.
+func (s *Store) Load(key string) (interface{}, error) {
+
+	file, err := os.Open(s.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, lrucache.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var rec record
+	if err := s.codec.NewDecoder(file).Decode(&rec); err != nil {
+		return nil, err
+	}
+	if rec.Key != key {
+		return nil, lrucache.ErrNotFound
+	}
+	return rec.Value, nil
+
+}
+
+// Store writes value for key.
+func (s *Store) Store(key string, value interface{}) error {
+
+	tmp, err := os.CreateTemp(s.dir, ".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := s.codec.NewEncoder(tmp).Encode(record{Key: key, Value: value}); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(key))
+
+}
+
+// Delete removes key. Deleting a missing key is not an error.
+func (s *Store) Delete(key string) error {
+
+	err := os.Remove(s.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+
+}
+
+// path maps key to a file name that is safe for any key.
+func (s *Store) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:]))
+}
@@ -0,0 +1,126 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package diskstore
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/georghagn/nexcache/lrucache"
+	"github.com/georghagn/nexcache/lrucache/clocktest"
+	"github.com/georghagn/nexcache/lrucache/tiered"
+)
+
+func open(t *testing.T, dir string, opts ...Option) *Store {
+	t.Helper()
+	s, err := Open(dir, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestStoreLoadDelete(t *testing.T) {
+
+	s := open(t, t.TempDir())
+
+	if _, err := s.Load("k"); err != lrucache.ErrNotFound {
+		t.Fatalf("Load of missing key = %v, want ErrNotFound", err)
+	}
+	if err := s.Store("k", "v"); err != nil {
+		t.Fatal(err)
+	}
+	if val, err := s.Load("k"); err != nil || val != "v" {
+		t.Fatalf("Load = %v, %v, want v", val, err)
+	}
+	if err := s.Delete("k"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Load("k"); err != lrucache.ErrNotFound {
+		t.Errorf("Load after Delete = %v, want ErrNotFound", err)
+	}
+	if err := s.Delete("k"); err != nil {
+		t.Errorf("Delete of missing key = %v, want nil", err)
+	}
+
+}
+
+// Keys that are not valid file names are stored under their hash, and no
+// temporary files are left behind.
+func TestAnyKeyAndNoTempFiles(t *testing.T) {
+
+	dir := t.TempDir()
+	s := open(t, dir)
+
+	keys := []string{"../escape", "a/b", "", "with space"}
+	for i, key := range keys {
+		if err := s.Store(key, float64(i)); err != nil {
+			t.Fatalf("Store(%q): %v", key, err)
+		}
+	}
+	for i, key := range keys {
+		if val, err := s.Load(key); err != nil || val != float64(i) {
+			t.Errorf("Load(%q) = %v, %v, want %d", key, val, err, i)
+		}
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != len(keys) {
+		t.Errorf("%d files in the directory, want %d", len(entries), len(keys))
+	}
+
+}
+
+func TestContentSurvivesReopen(t *testing.T) {
+
+	dir := t.TempDir()
+	open(t, dir).Store("k", "v")
+
+	if val, err := open(t, dir).Load("k"); err != nil || val != "v" {
+		t.Errorf("Load after reopen = %v, %v, want v", val, err)
+	}
+
+}
+
+// JSON turns numbers into float64; gob keeps the Go type.
+func TestGobCodecKeepsTypes(t *testing.T) {
+
+	s := open(t, t.TempDir(), WithCodec(lrucache.GobCodec))
+
+	s.Store("n", 42)
+	if val, err := s.Load("n"); err != nil || val != 42 {
+		t.Errorf("Load = %#v, %v, want int 42", val, err)
+	}
+
+}
+
+// As the L2 of a tiered cache, entries evicted from memory spill to disk
+// and keep their expiry.
+func TestOverflowTier(t *testing.T) {
+
+	clock := clocktest.New(time.Unix(1700000000, 0))
+	l1 := lrucache.New(1, time.Minute, time.Hour, lrucache.WithClock(clock))
+	disk := open(t, t.TempDir(), WithCodec(lrucache.GobCodec))
+	tc := tiered.New(l1, disk, tiered.WithDemotion())
+	defer tc.Close()
+
+	l1.Set("a", "x")
+	l1.Set("b", "y") // spills a
+	if val, found := tc.Get("a"); !found || val != "x" {
+		t.Fatalf("Get of spilled entry = %v, %v, want x", val, found)
+	}
+
+	l1.Delete("a")
+	clock.Advance(2 * time.Minute)
+	if _, found := tc.Get("a"); found {
+		t.Error("expired entry was served from disk")
+	}
+	if _, err := disk.Load("a"); err != lrucache.ErrNotFound {
+		t.Errorf("expired entry is still on disk: %v", err)
+	}
+
+}
@@ -0,0 +1,65 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"sync/atomic"
+)
+
+// ---------------------- Epochs ----------------------
+
+// Epoch identifies a generation of writes, see NewEpoch.
+type Epoch uint64
+
+// epochState is shared by all entries written during one epoch, so a whole
+// generation is invalidated by a single store.
+type epochState struct {
+	id   Epoch
+	dead atomic.Bool
+}
+
+// NewEpoch starts a new epoch and returns it. Entries written from now on
+// belong to it; entries written earlier keep their epoch until they are
+// written again. Before the first call all entries belong to epoch 0.
+func (c *LRUCache) NewEpoch() Epoch {
+
+	c.lock()
+	defer c.unlock()
+
+	next := &epochState{id: c.epoch.id + 1}
+	c.epoch = next
+	c.epochs = append(c.epochs, next)
+	return next.id
+
+}
+
+// InvalidateBefore invalidates all entries whose last write happened
+// before epoch, e.g. everything cached before a deploy. It only marks the
+// older epochs, so its cost does not depend on the number of entries. The
+// entries count as expired from now on; like expired entries they are
+// removed when they are accessed, by the cleanup or as they are evicted.
+// With WithExpiryHeap the cleanup skips them. The current epoch is never
+// invalidated.
+func (c *LRUCache) InvalidateBefore(epoch Epoch) {
+
+	c.lock()
+	defer c.unlock()
+
+	live := c.epochs[:0]
+	for _, state := range c.epochs {
+		if state.id < epoch && state != c.epoch {
+			state.dead.Store(true)
+			continue
+		}
+		live = append(live, state)
+	}
+	clear(c.epochs[len(live):])
+	c.epochs = live
+
+}
+
+// invalidated reports whether the epoch of the entry was invalidated.
+func (e *CacheEntry) invalidated() bool {
+	return e.epoch != nil && e.epoch.dead.Load()
+}
@@ -0,0 +1,115 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"sync"
+	"time"
+)
+
+// ---------------------- Event stream ----------------------
+
+// EventType is the kind of mutation an Event describes.
+type EventType string
+
+const (
+	EventSet    EventType = "set"    // a value was written or its expiry changed
+	EventLoad   EventType = "load"   // filled by a loader, read-through or Replicate
+	EventDelete EventType = "delete" // removed by Delete or an invalidation
+	EventEvict  EventType = "evict"  // evicted for capacity or by Clear
+	EventExpire EventType = "expire" // the TTL ran out
+
+	// EventPressure reports an eviction run under memory pressure. Key is
+	// empty and Value holds the number of evicted entries; the entries
+	// themselves are reported as EventEvict.
+	EventPressure EventType = "pressure"
+
+	// EventLost reports that events were dropped because the subscriber
+	// fell behind. Key is empty and Value holds the number of dropped
+	// events (an int). It is sent ahead of the next event that fits into
+	// the buffer.
+	EventLost EventType = "lost"
+)
+
+// Event describes one cache mutation. Value and ExpiresAt are zero for
+// deletions.
+type Event struct {
+	Type      EventType
+	Key       string
+	Value     interface{}
+	ExpiresAt time.Time
+	Time      time.Time
+}
+
+type subscriber struct {
+	ch   chan Event
+	once sync.Once
+	lost int // events dropped since the last EventLost, guarded by c.mu
+}
+
+// Subscribe returns a channel receiving an Event for every mutation and a
+// function that ends the subscription and closes the channel. The channel
+// holds up to buffer events; if the subscriber falls behind, further
+// events are dropped rather than blocking the cache, and an EventLost
+// reports how many. Events are sent in the order the mutations happened.
+func (c *LRUCache) Subscribe(buffer int) (<-chan Event, func()) {
+
+	sub := &subscriber{ch: make(chan Event, buffer)}
+
+	c.lock()
+	if c.subscribers == nil {
+		c.subscribers = make(map[*subscriber]struct{})
+	}
+	c.subscribers[sub] = struct{}{}
+	c.unlock()
+
+	cancel := func() {
+		c.lock()
+		delete(c.subscribers, sub)
+		c.unlock()
+		sub.once.Do(func() { close(sub.ch) })
+	}
+	return sub.ch, cancel
+
+}
+
+// publish sends an event to all subscribers without blocking. entry is
+// nil for deletions. Must be called with c.mu held.
+func (c *LRUCache) publish(typ EventType, key string, entry *CacheEntry) {
+
+	if len(c.subscribers) == 0 || c.replaying {
+		return
+	}
+
+	event := Event{Type: typ, Key: key, Time: c.clock.Now()}
+	if entry != nil {
+		event.Value = hydrate(entry)
+		event.ExpiresAt = entry.ExpiresAt
+	}
+	c.broadcast(event)
+
+}
+
+// broadcast sends event to all subscribers without blocking, preceded by
+// an EventLost if events were dropped before. Must be called with c.mu held.
+func (c *LRUCache) broadcast(event Event) {
+
+	for sub := range c.subscribers {
+		if sub.lost > 0 {
+			select {
+			case sub.ch <- Event{Type: EventLost, Value: sub.lost, Time: event.Time}:
+				sub.lost = 0
+			default:
+				sub.lost++
+				continue
+			}
+		}
+		select {
+		case sub.ch <- event:
+		default:
+			sub.lost++
+		}
+	}
+
+}
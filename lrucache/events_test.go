@@ -0,0 +1,68 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/georghagn/nexcache/lrucache"
+)
+
+// drain cancels the subscription and returns the buffered events.
+func drain(events <-chan lrucache.Event, cancel func()) []lrucache.Event {
+	cancel()
+	var all []lrucache.Event
+	for event := range events {
+		all = append(all, event)
+	}
+	return all
+}
+
+func TestSubscribeReportsMutations(t *testing.T) {
+
+	clock := newClock()
+	c := lrucache.New(1, time.Minute, time.Hour, lrucache.WithClock(clock))
+	defer c.Close()
+	events, cancel := c.Subscribe(16)
+
+	c.Set("a", 1)
+	c.Delete("a")
+	c.GetOrLoad("b", func() (interface{}, error) { return 2, nil })
+	c.Set("c", 3) // evicts b
+	clock.Advance(2 * time.Minute)
+	c.Get("c")
+
+	var got []string
+	for _, event := range drain(events, cancel) {
+		got = append(got, string(event.Type)+" "+event.Key)
+	}
+	want := []string{"set a", "delete a", "load b", "evict b", "set c", "expire c"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("events = %v, want %v", got, want)
+	}
+
+}
+
+func TestSubscribeReportsLostEvents(t *testing.T) {
+
+	c := lrucache.New(10, time.Hour, time.Hour)
+	defer c.Close()
+	events, cancel := c.Subscribe(1)
+
+	c.Set("a", 1)
+	c.Set("b", 1) // dropped
+	c.Set("c", 1) // dropped
+	if event := <-events; event.Key != "a" {
+		t.Fatalf("first event for %q, want a", event.Key)
+	}
+	c.Set("d", 1)
+
+	all := drain(events, cancel)
+	if len(all) != 1 || all[0].Type != lrucache.EventLost || all[0].Value != 2 {
+		t.Errorf("events = %+v, want EventLost with 2", all)
+	}
+
+}
@@ -0,0 +1,157 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// ---------------------- TTL jitter ----------------------
+
+// NoExpiration as a TTL (in New, SetDefaultTTL, AddWithTTL, Expire or for
+// a namespace) writes entries that never expire; they are only removed by
+// eviction or Delete. Such entries report a TTL of -1 and are persisted
+// with a zero expiry time.
+const NoExpiration time.Duration = 0
+
+// WithTTLJitter spreads expiry times: every TTL applied to an entry is
+// changed by a random amount of up to ±fraction of itself, so entries
+// written in a burst do not all expire at the same moment. fraction is
+// clamped to [0, 1]. GetWithExpiry and TTL report the effective expiry.
+func WithTTLJitter(fraction float64) Option {
+	return func(c *LRUCache) {
+		c.jitter = min(max(fraction, 0), 1)
+	}
+}
+
+// deadline returns the expiry of an entry written at now with ttl, or
+// the zero time for NoExpiration.
+func (c *LRUCache) deadline(now time.Time, ttl time.Duration) time.Time {
+
+	if ttl == NoExpiration {
+		return time.Time{}
+	}
+	if c.jitter > 0 {
+		ttl += time.Duration((rand.Float64()*2 - 1) * c.jitter * float64(ttl))
+	}
+	return now.Add(ttl)
+
+}
+
+// ---------------------- Expiry introspection ----------------------
+
+// TTL returns the remaining lifetime of key, or false if it is missing or
+// expired. Entries without expiry (see Persist and NoExpiration) report -1.
+// Neither the expiry nor the LRU position is changed.
+func (c *LRUCache) TTL(key string) (time.Duration, bool) {
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.closedLocked() {
+		return 0, false
+	}
+
+	if element, found := c.cache[key]; found {
+		entry := element.Value.(*CacheEntry)
+		now := c.clock.Now()
+		if entry.ExpiresAt.IsZero() {
+			return -1, true
+		}
+		if !entry.expired(now) {
+			return entry.ExpiresAt.Sub(now), true
+		}
+	}
+	return 0, false
+
+}
+
+// GetWithExpiry retrieves a value like Get together with its expiry time.
+// The entry is promoted, but its expiry is left unchanged even in sliding
+// mode, so the returned time is exactly the stored one.
+func (c *LRUCache) GetWithExpiry(key string) (interface{}, time.Time, bool) {
+
+	c.lock()
+	defer c.unlock()
+
+	if c.closedLocked() {
+		return nil, time.Time{}, false
+	}
+
+	if element, found := c.cache[key]; found {
+		entry := element.Value.(*CacheEntry)
+		if entry.expired(c.clock.Now()) {
+			c.expireElement(element)
+			c.misses.Add(1)
+			return nil, time.Time{}, false
+		}
+		c.promote(element)
+		if c.analytics != nil {
+			c.recordHit(entry, c.clock.Now())
+		}
+		entry.touch(c.clock.Now())
+		c.hits.Add(1)
+		return c.copyOut(hydrate(entry)), entry.ExpiresAt, true
+	}
+	c.misses.Add(1)
+	return nil, time.Time{}, false
+
+}
+
+// ---------------------- Expiry manipulation ----------------------
+
+// Expire sets the lifetime of an existing entry to ttl from now, without
+// rewriting its value, and reports whether a non-expired entry was found.
+// NoExpiration removes the expiry like Persist. In sliding mode, ttl also
+// becomes the renewal period of the entry.
+func (c *LRUCache) Expire(key string, ttl time.Duration) bool {
+	return c.setExpiry(key, func(entry *CacheEntry, now time.Time) {
+		entry.ExpiresAt = c.deadline(now, ttl)
+		entry.ttl = ttl
+	})
+}
+
+// ExpireAt sets the expiry of an existing entry to t and reports whether
+// a non-expired entry was found.
+func (c *LRUCache) ExpireAt(key string, t time.Time) bool {
+	return c.setExpiry(key, func(entry *CacheEntry, _ time.Time) {
+		entry.ExpiresAt = t
+	})
+}
+
+// Persist removes the expiry of an existing entry, so it is only removed
+// by eviction or Delete, and reports whether a non-expired entry was found.
+func (c *LRUCache) Persist(key string) bool {
+	return c.setExpiry(key, func(entry *CacheEntry, _ time.Time) {
+		entry.ExpiresAt = time.Time{}
+	})
+}
+
+func (c *LRUCache) setExpiry(key string, update func(entry *CacheEntry, now time.Time)) bool {
+
+	c.lock()
+	defer c.unlock()
+
+	if c.closedLocked() {
+		return false
+	}
+
+	element, found := c.cache[key]
+	if !found {
+		return false
+	}
+	entry := element.Value.(*CacheEntry)
+	now := c.clock.Now()
+	if entry.expired(now) {
+		return false
+	}
+
+	c.preserveForForks(key)
+	update(entry, now)
+	c.schedule(entry)
+	c.logSet(entry)
+	return true
+
+}
@@ -0,0 +1,179 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/georghagn/nexcache/lrucache"
+)
+
+func TestEntriesExpireAfterTTL(t *testing.T) {
+
+	clock := newClock()
+	c := lrucache.New(10, time.Minute, time.Hour, lrucache.WithClock(clock))
+	defer c.Close()
+
+	c.Set("k", "v")
+	clock.Advance(59 * time.Second)
+	if ttl, _ := c.TTL("k"); ttl != time.Second {
+		t.Errorf("TTL = %v, want 1s", ttl)
+	}
+	if _, found := c.Get("k"); !found {
+		t.Fatal("entry expired early")
+	}
+
+	clock.Advance(2 * time.Second)
+	if _, found := c.Get("k"); found {
+		t.Error("entry outlived its TTL")
+	}
+
+}
+
+func TestCleanupRemovesExpiredEntries(t *testing.T) {
+
+	clock := newClock()
+	c := lrucache.New(10, time.Minute, time.Second, lrucache.WithClock(clock))
+	defer c.Close()
+
+	var mu sync.Mutex
+	var expired []string
+	c.OnExpire(func(key string, _ interface{}) {
+		mu.Lock()
+		expired = append(expired, key)
+		mu.Unlock()
+	})
+
+	c.Set("old", 1)
+	clock.Advance(30 * time.Second)
+	c.Set("young", 2)
+	clock.Advance(45 * time.Second)
+
+	waitFor(t, "cleanup", func() bool { return c.Len() == 1 })
+	mu.Lock()
+	defer mu.Unlock()
+	if len(expired) != 1 || expired[0] != "old" {
+		t.Errorf("OnExpire called for %v, want [old]", expired)
+	}
+
+}
+
+func TestExpirePersistAndNoExpiration(t *testing.T) {
+
+	clock := newClock()
+	c := lrucache.New(10, time.Minute, time.Hour, lrucache.WithClock(clock))
+	defer c.Close()
+
+	c.Set("short", 1)
+	c.Set("kept", 2)
+	c.SetWithTTL("forever", 3, lrucache.NoExpiration)
+	if !c.Expire("short", time.Second) || !c.Persist("kept") {
+		t.Fatal("Expire or Persist did not find the entry")
+	}
+	if ttl, _ := c.TTL("kept"); ttl != -1 {
+		t.Errorf("TTL of a persisted entry = %v, want -1", ttl)
+	}
+
+	clock.Advance(24 * time.Hour)
+	if c.Contains("short") {
+		t.Error("entry outlived the TTL set by Expire")
+	}
+	if !c.Contains("kept") || !c.Contains("forever") {
+		t.Error("entry without expiry was dropped")
+	}
+	if c.Expire("short", time.Hour) {
+		t.Error("Expire revived an expired entry")
+	}
+
+}
+
+func TestMaxIdle(t *testing.T) {
+
+	clock := newClock()
+	c := lrucache.New(10, time.Hour, time.Hour, lrucache.WithClock(clock), lrucache.WithMaxIdle(time.Minute))
+	defer c.Close()
+
+	c.Set("k", "v")
+	for i := 0; i < 3; i++ {
+		clock.Advance(50 * time.Second)
+		if _, found := c.Get("k"); !found {
+			t.Fatalf("entry read every 50s expired after read %d", i)
+		}
+	}
+
+	clock.Advance(50 * time.Second)
+	c.Peek("k") // does not count as access
+	clock.Advance(20 * time.Second)
+	if _, found := c.Get("k"); found {
+		t.Error("entry outlived its idle limit")
+	}
+
+}
+
+func TestSetWithMaxIdleOverridesLimit(t *testing.T) {
+
+	clock := newClock()
+	c := lrucache.New(10, time.Hour, time.Hour, lrucache.WithClock(clock), lrucache.WithMaxIdle(time.Minute))
+	defer c.Close()
+
+	c.SetWithMaxIdle("none", 1, 0)
+	c.SetWithMaxIdle("short", 2, time.Second)
+	clock.Advance(2 * time.Minute)
+
+	if !c.Contains("none") {
+		t.Error("entry without idle limit expired")
+	}
+	if c.Contains("short") {
+		t.Error("entry outlived its own idle limit")
+	}
+
+}
+
+func TestSlidingTTL(t *testing.T) {
+
+	clock := newClock()
+	c := lrucache.New(10, time.Minute, time.Hour, lrucache.WithClock(clock), lrucache.WithSlidingTTL(true))
+	defer c.Close()
+
+	c.Set("read", 1)
+	c.Set("unread", 2)
+	for i := 0; i < 3; i++ {
+		clock.Advance(50 * time.Second)
+		if _, found := c.Get("read"); !found {
+			t.Fatalf("entry read every 50s expired after read %d", i)
+		}
+	}
+
+	if c.Contains("unread") {
+		t.Error("entry that was not read had its TTL renewed")
+	}
+	if ttl, _ := c.TTL("read"); ttl != time.Minute {
+		t.Errorf("TTL after a hit = %v, want 1m", ttl)
+	}
+
+}
+
+func TestTouchExtendsWithoutSliding(t *testing.T) {
+
+	clock := newClock()
+	c := lrucache.New(10, time.Minute, time.Hour, lrucache.WithClock(clock))
+	defer c.Close()
+
+	c.SetWithTTL("k", "v", 10*time.Second)
+	clock.Advance(8 * time.Second)
+	if !c.Touch("k") {
+		t.Fatal("Touch did not find the entry")
+	}
+	clock.Advance(8 * time.Second)
+	if !c.Contains("k") {
+		t.Error("Touch did not extend the entry by its own TTL")
+	}
+	clock.Advance(3 * time.Second)
+	if c.Touch("k") {
+		t.Error("Touch revived an expired entry")
+	}
+
+}
@@ -0,0 +1,121 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"container/heap"
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// ---------------------- Expiry heap ----------------------
+
+// WithExpiryHeap keeps all entries with an expiry in a min-heap ordered by
+// their due time, so the background cleanup only touches entries that are
+// actually due instead of scanning the whole cache. Each write that
+// changes an expiry costs O(log n). Idle limits (see WithMaxIdle) are
+// scheduled by the last access seen at write time; entries found still in
+// use when they come due are rescheduled. With the heap, a cleanup budget
+// set by WithCleanupBudget is ignored.
+func WithExpiryHeap() Option {
+	return func(c *LRUCache) {
+		c.expiries = &expiryHeap{}
+	}
+}
+
+// expiryHeap orders entries by due time. Each entry stores its position
+// plus one in slot, so 0 means "not in the heap".
+type expiryHeap []*CacheEntry
+
+func (h expiryHeap) Len() int           { return len(h) }
+func (h expiryHeap) Less(i, j int) bool { return h[i].due < h[j].due }
+
+func (h expiryHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].slot = i + 1
+	h[j].slot = j + 1
+}
+
+func (h *expiryHeap) Push(x interface{}) {
+	entry := x.(*CacheEntry)
+	entry.slot = len(*h) + 1
+	*h = append(*h, entry)
+}
+
+func (h *expiryHeap) Pop() interface{} {
+	old := *h
+	entry := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	entry.slot = 0
+	return entry
+}
+
+// dueAt returns when entry expires by TTL or idle limit, in Unix
+// nanoseconds, or math.MaxInt64 if it never does.
+func (e *CacheEntry) dueAt() int64 {
+
+	due := int64(math.MaxInt64)
+	if !e.ExpiresAt.IsZero() {
+		due = e.ExpiresAt.UnixNano()
+	}
+	if e.idle > 0 {
+		due = min(due, atomic.LoadInt64(&e.lastUse)+int64(e.idle))
+	}
+	return due
+
+}
+
+// schedule updates the position of entry after its expiry changed.
+// Must be called with c.mu held.
+func (c *LRUCache) schedule(entry *CacheEntry) {
+
+	if c.expiries == nil {
+		return
+	}
+	due := entry.dueAt()
+	switch {
+	case due == math.MaxInt64:
+		c.unschedule(entry)
+	case entry.slot == 0:
+		entry.due = due
+		heap.Push(c.expiries, entry)
+	default:
+		entry.due = due
+		heap.Fix(c.expiries, entry.slot-1)
+	}
+
+}
+
+// unschedule removes entry from the heap. Must be called with c.mu held.
+func (c *LRUCache) unschedule(entry *CacheEntry) {
+	if c.expiries != nil && entry.slot != 0 {
+		heap.Remove(c.expiries, entry.slot-1)
+	}
+}
+
+// expireDueLocked removes all entries that are due at now.
+// Must be called with c.mu held.
+func (c *LRUCache) expireDueLocked(now time.Time) {
+
+	limit := now.UnixNano()
+	for c.expiries.Len() > 0 {
+		entry := (*c.expiries)[0]
+		if entry.due >= limit {
+			return
+		}
+		if !entry.expired(now) {
+			// Accessed since it was scheduled.
+			c.schedule(entry)
+			continue
+		}
+		if element, found := c.cache[entry.Key]; found && element.Value == entry {
+			c.expireElement(element)
+		} else {
+			c.unschedule(entry)
+		}
+	}
+
+}
@@ -0,0 +1,97 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ---------------------- Anonymized export ----------------------
+
+// ExportRules controls ExportAnonymized. Keys are always replaced by a
+// hash; the zero value also redacts all values.
+type ExportRules struct {
+	// Salt is mixed into the key hashes so they cannot be matched against
+	// hashes of guessed keys.
+	Salt string
+
+	// KeepKeyPrefix keeps the part of a key before the first ':' in clear
+	// text, e.g. "user:42" becomes "user:3f2a...".
+	KeepKeyPrefix bool
+
+	// ValueBytes keeps at most this many bytes of string and []byte
+	// values. Other values are replaced by their type name.
+	ValueBytes int
+
+	// Value, if set, replaces the built-in value handling.
+	Value func(key string, value interface{}) interface{}
+}
+
+// ExportAnonymized writes a snapshot in the SaveToFile format in which
+// keys are hashed and values truncated or redacted according to rules.
+// The result can be shared for support and bug reports without leaking
+// the cached data; recency order and expiry times are preserved.
+func (c *LRUCache) ExportAnonymized(filename string, rules ExportRules) error {
+
+	c.lock()
+	closed := c.closedLocked()
+	c.unlock()
+	if closed {
+		return ErrClosed
+	}
+
+	entries := c.snapshot()
+	for i := range entries {
+		key := entries[i].Key
+		entries[i].Key = rules.anonymizeKey(key)
+		entries[i].Value = rules.anonymizeValue(key, entries[i].Value)
+	}
+
+	return writeFileAtomic(filename, func(w io.Writer) error {
+		return c.codec.NewEncoder(w).Encode(entries)
+	})
+
+}
+
+func (r ExportRules) anonymizeKey(key string) string {
+
+	prefix := ""
+	if r.KeepKeyPrefix {
+		if i := strings.IndexByte(key, ':'); i >= 0 {
+			prefix = key[:i+1]
+		}
+	}
+
+	sum := sha256.Sum256([]byte(r.Salt + key))
+	return prefix + hex.EncodeToString(sum[:12])
+
+}
+
+func (r ExportRules) anonymizeValue(key string, value interface{}) interface{} {
+
+	if r.Value != nil {
+		return r.Value(key, value)
+	}
+
+	switch v := value.(type) {
+	case string:
+		return truncate(v, r.ValueBytes)
+	case []byte:
+		return truncate(string(v), r.ValueBytes)
+	default:
+		return fmt.Sprintf("[redacted %T]", value)
+	}
+
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + fmt.Sprintf("...[%d bytes redacted]", len(s)-n)
+}
@@ -0,0 +1,148 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"time"
+)
+
+// Fork is a read-only, point-in-time view of the cache.
+//
+// Creating a fork is O(1): nothing is copied up front. Instead, every
+// mutation of the live cache first saves the previous state of the
+// affected key into all active forks (copy-on-write). Reads on a fork
+// therefore see the cache exactly as it was when Fork was called, while
+// writers continue unimpeded.
+//
+// A fork must be released with Release once it is no longer needed,
+// otherwise the cache keeps recording pre-images for it.
+type Fork struct {
+	cache    *LRUCache
+	at       time.Time
+	saved    map[string]*CacheEntry // nil value = key was absent at fork time
+	epochs   map[*epochState]bool   // epochs not invalidated at fork time
+	detached bool                   // true = saved holds the complete view
+	released bool
+}
+
+// Fork creates a consistent view of the current cache content.
+func (c *LRUCache) Fork() *Fork {
+
+	c.lock()
+	defer c.unlock()
+
+	f := &Fork{
+		cache:  c,
+		at:     c.clock.Now(),
+		saved:  make(map[string]*CacheEntry),
+		epochs: make(map[*epochState]bool, len(c.epochs)),
+	}
+	for _, state := range c.epochs {
+		f.epochs[state] = true
+	}
+	c.forks[f] = struct{}{}
+	return f
+
+}
+
+// Get retrieves a value as it was at fork time.
+// The LRU order of the live cache is not changed.
+func (f *Fork) Get(key string) (interface{}, bool) {
+
+	if f.cache.strict {
+		f.cache.checkReentrant()
+	}
+	f.cache.mu.Lock()
+	defer f.cache.mu.Unlock()
+
+	if f.released {
+		return nil, false
+	}
+
+	entry, saved := f.saved[key]
+	if !saved && !f.detached {
+		if element, found := f.cache.cache[key]; found {
+			entry = element.Value.(*CacheEntry)
+		}
+	}
+	if entry == nil || f.expired(entry) {
+		return nil, false
+	}
+	return f.cache.copyOut(hydrate(entry)), true
+
+}
+
+// expired reports whether entry was expired at fork time. Entries read
+// from the live cache may have been invalidated since, so epochs are
+// checked against those live at fork time. The idle check needs no
+// snapshot: the last use of an entry only moves forward, and an entry
+// idle at fork time is preserved before it is removed or rewritten.
+func (f *Fork) expired(entry *CacheEntry) bool {
+	return expiredAt(entry.ExpiresAt, f.at) || entry.idleExpired(f.at) ||
+		(entry.epoch != nil && !f.epochs[entry.epoch])
+}
+
+// Release detaches the fork from the cache. Subsequent reads return nothing.
+func (f *Fork) Release() {
+
+	f.cache.mu.Lock()
+	defer f.cache.mu.Unlock()
+
+	f.released = true
+	f.saved = nil
+	f.epochs = nil
+	delete(f.cache.forks, f)
+
+}
+
+// ---------------------- Helpers ----------------------
+
+// preserveForForks records the current state of key in every active fork
+// that has not seen a change to it yet. Must be called with c.mu held and
+// before the key is modified.
+func (c *LRUCache) preserveForForks(key string) {
+
+	if len(c.forks) == 0 {
+		return
+	}
+
+	var pre *CacheEntry
+	if element, found := c.cache[key]; found {
+		cp := *element.Value.(*CacheEntry)
+		pre = &cp
+	}
+	for f := range c.forks {
+		if f.detached {
+			continue
+		}
+		if _, done := f.saved[key]; !done {
+			f.saved[key] = pre
+		}
+	}
+
+}
+
+// detachForks materializes the complete view into every active fork.
+// Used before the live cache is replaced wholesale. Must be called with c.mu held.
+func (c *LRUCache) detachForks() {
+
+	for f := range c.forks {
+		if f.detached {
+			continue
+		}
+		for key, element := range c.cache {
+			if _, done := f.saved[key]; !done {
+				cp := *element.Value.(*CacheEntry)
+				f.saved[key] = &cp
+			}
+		}
+		for key, entry := range f.saved {
+			if entry == nil {
+				delete(f.saved, key)
+			}
+		}
+		f.detached = true
+	}
+
+}
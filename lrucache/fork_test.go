@@ -0,0 +1,108 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/georghagn/nexcache/lrucache"
+)
+
+func TestForkIsPointInTime(t *testing.T) {
+
+	c := lrucache.New(10, time.Hour, time.Hour)
+	defer c.Close()
+	c.Set("changed", 1)
+	c.Set("deleted", 1)
+
+	f := c.Fork()
+	c.Set("changed", 2)
+	c.Delete("deleted")
+	c.Set("added", 1)
+
+	if val, _ := f.Get("changed"); val != 1 {
+		t.Errorf("fork sees changed = %v, want 1", val)
+	}
+	if _, found := f.Get("deleted"); !found {
+		t.Error("fork lost a key deleted after the fork")
+	}
+	if _, found := f.Get("added"); found {
+		t.Error("fork sees a key added after the fork")
+	}
+	if val, _ := c.Get("changed"); val != 2 {
+		t.Errorf("live cache has changed = %v, want 2", val)
+	}
+
+	f.Release()
+	if _, found := f.Get("changed"); found {
+		t.Error("released fork still answers")
+	}
+
+}
+
+// Entries are judged by the fork time, not by the time of the read.
+func TestForkExpiryAtForkTime(t *testing.T) {
+
+	clock := newClock()
+	c := lrucache.New(10, time.Minute, time.Hour, lrucache.WithClock(clock))
+	defer c.Close()
+	c.Set("k", 1)
+
+	f := c.Fork()
+	defer f.Release()
+	clock.Advance(2 * time.Minute)
+
+	if _, found := f.Get("k"); !found {
+		t.Error("fork dropped an entry that expired after the fork")
+	}
+	if c.Contains("k") {
+		t.Error("live entry outlived its TTL")
+	}
+
+}
+
+// InvalidateBefore after the fork does not change what the fork sees,
+// while invalidations before it do.
+func TestForkJudgesEpochsAtForkTime(t *testing.T) {
+
+	c := lrucache.New(10, time.Hour, time.Hour)
+	defer c.Close()
+
+	c.Set("dead", 1)
+	c.InvalidateBefore(c.NewEpoch())
+	c.Set("old", 1)
+
+	f := c.Fork()
+	defer f.Release()
+	c.InvalidateBefore(c.NewEpoch())
+
+	if _, found := f.Get("old"); !found {
+		t.Error("fork lost an entry invalidated after the fork")
+	}
+	if _, found := f.Get("dead"); found {
+		t.Error("fork sees an entry invalidated before the fork")
+	}
+	if c.Contains("old") {
+		t.Error("live entry survived InvalidateBefore")
+	}
+
+}
+
+func TestForkSurvivesClear(t *testing.T) {
+
+	c := lrucache.New(10, time.Hour, time.Hour)
+	defer c.Close()
+	c.Set("k", 1)
+
+	f := c.Fork()
+	defer f.Release()
+	c.Clear()
+	c.Set("k", 2)
+
+	if val, _ := f.Get("k"); val != 1 {
+		t.Errorf("fork sees %v after Clear, want 1", val)
+	}
+
+}
@@ -0,0 +1,25 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+// ---------------------- Goroutine accounting ----------------------
+
+// Goroutines returns the number of background goroutines currently owned
+// by the cache (cleanup routine, auto-save, running invalidations, ...).
+// After Close it drops to zero once all of them have finished; see
+// cachetest.VerifyShutdown.
+func (c *LRUCache) Goroutines() int {
+	return int(c.goroutines.Load())
+}
+
+// spawn runs fn on a new goroutine that is counted by Goroutines.
+func (c *LRUCache) spawn(fn func()) {
+
+	c.goroutines.Add(1)
+	go func() {
+		defer c.goroutines.Add(-1)
+		fn()
+	}()
+
+}
@@ -0,0 +1,103 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// ---------------------- Graceful persistence ----------------------
+
+// EnableGracefulPersistence makes restarts warm. It first restores the
+// snapshot at path if the file exists and is not older than maxAge
+// (0 = any age); a missing or stale file is not an error. Afterwards a
+// snapshot is written to path on shutdown:
+//
+//   - when ctx is done, for services that manage shutdown themselves
+//     (e.g. with signal.NotifyContext);
+//   - with a nil ctx, when the process receives SIGINT or SIGTERM. After
+//     the snapshot is written, the signal is delivered again with its
+//     default action, so the process terminates as it would without the
+//     handler;
+//   - on Close in both cases.
+//
+// Errors of the shutdown snapshot are reported by AutoSaveError and, on
+// Close, returned. The handler ends with StopCleanup or Close. Call it once
+// per cache.
+func (c *LRUCache) EnableGracefulPersistence(ctx context.Context, path string, maxAge time.Duration) error {
+
+	if path == "" {
+		return errors.New("lrucache: graceful persistence needs a path")
+	}
+
+	info, err := os.Stat(path)
+	switch {
+	case err == nil:
+		if maxAge <= 0 || c.clock.Now().Sub(info.ModTime()) <= maxAge {
+			if err := c.LoadFromFile(path); err != nil {
+				return err
+			}
+		}
+	case !errors.Is(err, fs.ErrNotExist):
+		return err
+	}
+
+	c.lock()
+	if c.closedLocked() {
+		c.unlock()
+		return ErrClosed
+	}
+	c.gracefulPath = path
+	c.unlock()
+
+	var signals chan os.Signal
+	if ctx == nil {
+		ctx = context.Background()
+		signals = make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	}
+	c.spawn(func() { c.awaitShutdown(ctx, signals) })
+	return nil
+
+}
+
+// awaitShutdown writes the shutdown snapshot once ctx is done or a signal
+// arrives. A nil signals channel never delivers.
+func (c *LRUCache) awaitShutdown(ctx context.Context, signals chan os.Signal) {
+
+	if signals != nil {
+		defer signal.Stop(signals)
+	}
+
+	select {
+	case <-c.stopCh:
+	case <-ctx.Done():
+		c.saveGraceful()
+	case sig := <-signals:
+		c.saveGraceful()
+		signal.Stop(signals)
+		if p, err := os.FindProcess(os.Getpid()); err == nil {
+			p.Signal(sig)
+		}
+	}
+
+}
+
+func (c *LRUCache) saveGraceful() {
+
+	c.lock()
+	defer c.unlock()
+
+	if c.closed || c.gracefulPath == "" {
+		return
+	}
+	c.autoSaveErr = c.snapshotFileLocked(c.gracefulPath)
+
+}
@@ -0,0 +1,140 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"strings"
+)
+
+// ---------------------- Hierarchical keys ----------------------
+
+// WithKeyHierarchy treats keys as paths separated by sep (for example
+// "org/123/project/456") and maintains a trie over them, so that
+// InvalidateSubtree only touches the affected entries instead of scanning
+// the whole cache.
+func WithKeyHierarchy(sep string) Option {
+	return func(c *LRUCache) {
+		if sep != "" {
+			c.keyIndex = newKeyTrie(sep)
+		}
+	}
+}
+
+// InvalidateSubtree removes the entry path and all entries below it
+// (path+sep+...) and returns how many were removed. "org/1" matches
+// "org/1" and "org/1/x", but not "org/12". Without WithKeyHierarchy the
+// separator "/" is assumed and all keys are scanned.
+func (c *LRUCache) InvalidateSubtree(path string) int {
+
+	c.lock()
+	defer c.unlock()
+
+	if c.closedLocked() {
+		return 0
+	}
+
+	var keys []string
+	if c.keyIndex != nil {
+		keys = c.keyIndex.subtree(path)
+	} else {
+		for key := range c.cache {
+			if key == path || strings.HasPrefix(key, path+"/") {
+				keys = append(keys, key)
+			}
+		}
+	}
+
+	for _, key := range keys {
+		c.removeElement(c.cache[key])
+		c.logDelete(key)
+	}
+	return len(keys)
+
+}
+
+// keyTrie indexes keys by their path segments.
+type keyTrie struct {
+	sep  string
+	root *trieNode
+}
+
+type trieNode struct {
+	children map[string]*trieNode
+	key      string
+	hasKey   bool
+}
+
+func newKeyTrie(sep string) *keyTrie {
+	return &keyTrie{sep: sep, root: &trieNode{}}
+}
+
+func (t *keyTrie) insert(key string) {
+
+	node := t.root
+	for _, segment := range strings.Split(key, t.sep) {
+		child, found := node.children[segment]
+		if !found {
+			if node.children == nil {
+				node.children = make(map[string]*trieNode)
+			}
+			child = &trieNode{}
+			node.children[segment] = child
+		}
+		node = child
+	}
+	node.key = key
+	node.hasKey = true
+
+}
+
+func (t *keyTrie) remove(key string) {
+
+	segments := strings.Split(key, t.sep)
+	path := make([]*trieNode, 0, len(segments)+1)
+	node := t.root
+	path = append(path, node)
+	for _, segment := range segments {
+		if node = node.children[segment]; node == nil {
+			return
+		}
+		path = append(path, node)
+	}
+	node.hasKey = false
+	node.key = ""
+
+	// Prune nodes that neither hold a key nor lead to one.
+	for i := len(path) - 1; i > 0; i-- {
+		if path[i].hasKey || len(path[i].children) > 0 {
+			break
+		}
+		delete(path[i-1].children, segments[i-1])
+	}
+
+}
+
+// subtree returns all keys at or below prefix.
+func (t *keyTrie) subtree(prefix string) []string {
+
+	node := t.root
+	for _, segment := range strings.Split(prefix, t.sep) {
+		if node = node.children[segment]; node == nil {
+			return nil
+		}
+	}
+
+	var keys []string
+	stack := []*trieNode{node}
+	for len(stack) > 0 {
+		node = stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if node.hasKey {
+			keys = append(keys, node.key)
+		}
+		for _, child := range node.children {
+			stack = append(stack, child)
+		}
+	}
+	return keys
+
+}
@@ -0,0 +1,67 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"time"
+)
+
+// entryVersion is a previous value of an entry, kept for Rollback.
+type entryVersion struct {
+	Value     interface{}
+	ExpiresAt time.Time
+	Meta      map[string]string
+	ttl       time.Duration
+}
+
+// WithHistory keeps the previous value and expiry of every entry when it is
+// overwritten, so that Rollback can restore it. Only one version is kept.
+func WithHistory() Option {
+	return func(c *LRUCache) {
+		c.history = true
+	}
+}
+
+// Rollback restores the previous value, expiry and metadata of key and
+// reports whether there was a version to restore. It requires WithHistory.
+// The rollback is a write like Set: it is logged to the WAL, published to
+// subscribers and written to a backing store. A restored entry whose
+// original expiry has passed is treated as expired.
+func (c *LRUCache) Rollback(key string) bool {
+
+	c.lock()
+	defer c.unlock()
+
+	if c.closedLocked() {
+		return false
+	}
+
+	element, found := c.cache[key]
+	if !found {
+		return false
+	}
+	entry := element.Value.(*CacheEntry)
+	if entry.prev == nil {
+		return false
+	}
+
+	prev := entry.prev
+	c.meta = prev.Meta
+	if c.meta == nil {
+		c.meta = map[string]string{}
+	}
+	restored := c.putLocked(key, hydrate(&CacheEntry{Value: prev.Value}), prev.ExpiresAt)
+	c.meta = nil
+	restored.ttl = prev.ttl
+	restored.prev = nil
+	return true
+
+}
+
+// remember stores the current version of entry before it is overwritten.
+func (c *LRUCache) remember(entry *CacheEntry) {
+	if c.history {
+		entry.prev = &entryVersion{Value: entry.Value, ExpiresAt: entry.ExpiresAt, Meta: entry.Meta, ttl: entry.ttl}
+	}
+}
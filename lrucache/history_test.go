@@ -0,0 +1,42 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/georghagn/nexcache/lrucache"
+)
+
+func TestRollbackRestoresPreviousVersion(t *testing.T) {
+
+	clock := newClock()
+	store := newMemStore()
+	c := lrucache.New(10, time.Hour, time.Hour, lrucache.WithClock(clock), lrucache.WithHistory(), lrucache.WithWriteThrough(store))
+	defer c.Close()
+
+	c.SetWithMeta("k", "v1", map[string]string{"rev": "1"})
+	c.Expire("k", time.Minute)
+	_, firstExpiry, _ := c.GetWithExpiry("k")
+	c.Set("k", "v2")
+
+	if !c.Rollback("k") {
+		t.Fatal("Rollback found no version")
+	}
+	val, expiry, _ := c.GetWithExpiry("k")
+	if val != "v1" || !expiry.Equal(firstExpiry) {
+		t.Errorf("after Rollback = %v (expires %v), want v1 (expires %v)", val, expiry, firstExpiry)
+	}
+	if meta, _ := c.GetMeta("k"); meta["rev"] != "1" {
+		t.Errorf("metadata = %v, want rev 1", meta)
+	}
+	if stored, _ := store.get("k"); stored != "v1" {
+		t.Errorf("store has %v, want the rolled back value", stored)
+	}
+	if c.Rollback("k") {
+		t.Error("a second Rollback found a version")
+	}
+
+}
@@ -0,0 +1,159 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"container/heap"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// ---------------------- Hot keys ----------------------
+
+// KeyStats describes a frequently requested key, see HotKeys.
+type KeyStats struct {
+	Key string
+
+	// Lookups is the estimated number of lookups of the key, hits and
+	// misses, since tracking started. It may overestimate by up to
+	// MaxError.
+	Lookups  uint64
+	MaxError uint64
+}
+
+// WithHotKeyTracking tracks the most frequently looked-up keys for
+// HotKeys. Only every sampleEvery-th lookup is recorded (1 = all), and at
+// most size keys are counted, using the Space-Saving algorithm: a new key
+// replaces the least counted one and inherits its count as error bound.
+// The cost per lookup is an atomic increment, plus O(log size) under a
+// separate mutex for sampled lookups. size should be a few times the
+// number of keys that HotKeys is asked for.
+func WithHotKeyTracking(size, sampleEvery int) Option {
+	return func(c *LRUCache) {
+		if size < 1 {
+			size = 1
+		}
+		if sampleEvery < 1 {
+			sampleEvery = 1
+		}
+		c.hot = &hotKeys{
+			size:  size,
+			every: uint64(sampleEvery),
+			index: make(map[string]*hotCounter, size),
+		}
+	}
+}
+
+// HotKeys returns up to n of the most looked-up keys, most frequent first.
+// Without WithHotKeyTracking it returns nil. Lookups are those of Get,
+// GetMulti and the GetOrLoad family.
+func (c *LRUCache) HotKeys(n int) []KeyStats {
+
+	if c.hot == nil || n <= 0 {
+		return nil
+	}
+
+	h := c.hot
+	h.mu.Lock()
+	stats := make([]KeyStats, 0, len(h.counters))
+	for _, counter := range h.counters {
+		stats = append(stats, KeyStats{
+			Key:      counter.key,
+			Lookups:  counter.count * h.every,
+			MaxError: counter.err * h.every,
+		})
+	}
+	h.mu.Unlock()
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Lookups != stats[j].Lookups {
+			return stats[i].Lookups > stats[j].Lookups
+		}
+		return stats[i].Key < stats[j].Key
+	})
+	if len(stats) > n {
+		stats = stats[:n]
+	}
+	return stats
+
+}
+
+// hotKeys is a Space-Saving summary. counters is a min-heap by count, so
+// the key to replace is always at the root.
+type hotKeys struct {
+	size  int
+	every uint64
+	tick  atomic.Uint64
+
+	mu       sync.Mutex
+	index    map[string]*hotCounter
+	counters hotHeap
+}
+
+type hotCounter struct {
+	key   string
+	count uint64
+	err   uint64
+	pos   int
+}
+
+// recordLookup counts a lookup of key if it is sampled.
+func (c *LRUCache) recordLookup(key string) {
+
+	h := c.hot
+	if h == nil || h.tick.Add(1)%h.every != 0 {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if counter, found := h.index[key]; found {
+		counter.count++
+		heap.Fix(&h.counters, counter.pos)
+		return
+	}
+	if len(h.counters) < h.size {
+		counter := &hotCounter{key: key, count: 1}
+		h.index[key] = counter
+		heap.Push(&h.counters, counter)
+		return
+	}
+
+	// Replace the least counted key; its count bounds the error.
+	counter := h.counters[0]
+	delete(h.index, counter.key)
+	counter.key = key
+	counter.err = counter.count
+	counter.count++
+	h.index[key] = counter
+	heap.Fix(&h.counters, 0)
+
+}
+
+type hotHeap []*hotCounter
+
+func (h hotHeap) Len() int           { return len(h) }
+func (h hotHeap) Less(i, j int) bool { return h[i].count < h[j].count }
+
+func (h hotHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].pos = i
+	h[j].pos = j
+}
+
+func (h *hotHeap) Push(x interface{}) {
+	counter := x.(*hotCounter)
+	counter.pos = len(*h)
+	*h = append(*h, counter)
+}
+
+func (h *hotHeap) Pop() interface{} {
+	old := *h
+	counter := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	return counter
+}
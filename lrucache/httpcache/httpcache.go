@@ -0,0 +1,246 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+// Package httpcache provides net/http middleware that caches complete
+// responses of a handler in an LRUCache.
+package httpcache
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/georghagn/nexcache/lrucache"
+)
+
+// KeyFunc derives the cache key of a request. Requests for which it
+// returns "" are not cached. HEAD requests are passed in as GET.
+type KeyFunc func(r *http.Request) string
+
+// Option configures the middleware.
+type Option func(*config)
+
+type config struct {
+	key      KeyFunc
+	match    func(r *http.Request) bool
+	statuses map[int]bool
+	ttl      time.Duration
+	maxBody  int
+}
+
+// WithKeyFunc replaces the default key (method, path and query).
+func WithKeyFunc(fn KeyFunc) Option {
+	return func(c *config) {
+		c.key = fn
+	}
+}
+
+// WithHeaderKey adds the values of the named request headers to the
+// default key, e.g. "Accept-Language".
+func WithHeaderKey(names ...string) Option {
+	return func(c *config) {
+		c.key = func(r *http.Request) string {
+			key := defaultKey(r)
+			for _, name := range names {
+				key += "|" + name + "=" + strings.Join(r.Header.Values(name), ",")
+			}
+			return key
+		}
+	}
+}
+
+// WithRoutes restricts caching to requests whose path starts with one of
+// the prefixes.
+func WithRoutes(prefixes ...string) Option {
+	return func(c *config) {
+		c.match = func(r *http.Request) bool {
+			for _, prefix := range prefixes {
+				if strings.HasPrefix(r.URL.Path, prefix) {
+					return true
+				}
+			}
+			return false
+		}
+	}
+}
+
+// WithStatusCodes sets the cacheable status codes (default 200).
+func WithStatusCodes(codes ...int) Option {
+	return func(c *config) {
+		c.statuses = make(map[int]bool, len(codes))
+		for _, code := range codes {
+			c.statuses[code] = true
+		}
+	}
+}
+
+// WithTTL sets the TTL for responses without Cache-Control max-age.
+// Without it, such responses use the cache's default TTL.
+func WithTTL(ttl time.Duration) Option {
+	return func(c *config) {
+		c.ttl = ttl
+	}
+}
+
+// WithMaxBodySize sets the largest body that is cached (default 1 MiB).
+func WithMaxBodySize(n int) Option {
+	return func(c *config) {
+		c.maxBody = n
+	}
+}
+
+// response is a captured response.
+type response struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+// Middleware caches responses of GET requests and serves HEAD requests
+// from them; HEAD responses themselves are not stored. Responses are
+// marked with "X-Cache: HIT" or "X-Cache: MISS". Responses that set
+// Cache-Control no-store or private, or that set cookies, are not
+// cached; max-age (or s-maxage) determines the TTL when present.
+func Middleware(cache *lrucache.LRUCache, opts ...Option) func(http.Handler) http.Handler {
+
+	cfg := &config{key: defaultKey, statuses: map[int]bool{http.StatusOK: true}, maxBody: 1 << 20}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			if r.Method != http.MethodGet && r.Method != http.MethodHead || cfg.match != nil && !cfg.match(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			key := cfg.key(keyRequest(r))
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// Values of other types, e.g. restored from a JSON snapshot,
+			// count as a miss.
+			if val, found := cache.Get(key); found {
+				if cached, ok := val.(*response); ok {
+					for name, values := range cached.header {
+						w.Header()[name] = append([]string(nil), values...)
+					}
+					w.Header().Set("X-Cache", "HIT")
+					w.WriteHeader(cached.status)
+					if r.Method != http.MethodHead {
+						w.Write(cached.body)
+					}
+					return
+				}
+			}
+
+			rec := &recorder{ResponseWriter: w, status: http.StatusOK, limit: cfg.maxBody}
+			w.Header().Set("X-Cache", "MISS")
+			next.ServeHTTP(rec, r)
+
+			ttl, ok := cfg.cacheable(rec)
+			if !ok || r.Method == http.MethodHead {
+				return
+			}
+			header := rec.Header().Clone()
+			header.Del("X-Cache")
+			cached := &response{status: rec.status, header: header, body: rec.body.Bytes()}
+			if ttl > 0 {
+				cache.SetWithTTL(key, cached, ttl)
+			} else {
+				cache.Set(key, cached)
+			}
+
+		})
+	}
+
+}
+
+// cacheable decides whether a recorded response may be stored and for how
+// long; a zero TTL means the cache default. As this is a shared cache,
+// s-maxage takes precedence over max-age.
+func (cfg *config) cacheable(rec *recorder) (time.Duration, bool) {
+
+	if !cfg.statuses[rec.status] || rec.overflow || rec.Header().Get("Set-Cookie") != "" {
+		return 0, false
+	}
+
+	var maxAge, sMaxAge string // raw directive values, "" = absent
+	for _, v := range rec.Header().Values("Cache-Control") {
+		for _, d := range strings.Split(v, ",") {
+			d = strings.ToLower(strings.TrimSpace(d))
+			switch {
+			case d == "no-store" || d == "private" || d == "no-cache":
+				return 0, false
+			case strings.HasPrefix(d, "s-maxage="):
+				sMaxAge = strings.TrimPrefix(d, "s-maxage=")
+			case strings.HasPrefix(d, "max-age="):
+				maxAge = strings.TrimPrefix(d, "max-age=")
+			}
+		}
+	}
+
+	age := sMaxAge
+	if age == "" {
+		age = maxAge
+	}
+	if age == "" {
+		return cfg.ttl, true
+	}
+	seconds, err := strconv.Atoi(age)
+	if err != nil || seconds <= 0 {
+		return 0, false
+	}
+	return time.Duration(seconds) * time.Second, true
+
+}
+
+// keyRequest returns the request the key is derived from: HEAD requests
+// are keyed like GET, so they find the stored GET response.
+func keyRequest(r *http.Request) *http.Request {
+	if r.Method != http.MethodHead {
+		return r
+	}
+	get := *r
+	get.Method = http.MethodGet
+	return &get
+}
+
+func defaultKey(r *http.Request) string {
+	return r.Method + " " + r.URL.RequestURI()
+}
+
+// recorder passes the response through and keeps a copy.
+type recorder struct {
+	http.ResponseWriter
+	status   int
+	body     bytes.Buffer
+	limit    int
+	overflow bool
+	wrote    bool
+}
+
+func (r *recorder) WriteHeader(status int) {
+	if !r.wrote {
+		r.status, r.wrote = status, true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *recorder) Write(p []byte) (int, error) {
+	r.wrote = true
+	if !r.overflow {
+		if r.body.Len()+len(p) > r.limit {
+			r.overflow = true
+			r.body.Reset()
+		} else {
+			r.body.Write(p)
+		}
+	}
+	return r.ResponseWriter.Write(p)
+}
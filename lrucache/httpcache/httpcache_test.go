@@ -0,0 +1,204 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package httpcache
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/georghagn/nexcache/lrucache"
+	"github.com/georghagn/nexcache/lrucache/clocktest"
+)
+
+// serve runs one request through the middleware.
+func serve(h http.Handler, method, target string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(method, target, nil))
+	return w
+}
+
+// counting returns a handler that answers with body and the header set
+// by setup, and counts its calls.
+func counting(calls *int, body string, setup func(h http.Header)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		if setup != nil {
+			setup(w.Header())
+		}
+		w.Write([]byte(body))
+	})
+}
+
+func TestMissThenHit(t *testing.T) {
+
+	cache := lrucache.New(10, time.Hour, time.Hour)
+	defer cache.Close()
+	calls := 0
+	h := Middleware(cache)(counting(&calls, "hello", nil))
+
+	first := serve(h, http.MethodGet, "/x")
+	second := serve(h, http.MethodGet, "/x")
+
+	if got := first.Header().Get("X-Cache"); got != "MISS" {
+		t.Errorf("first X-Cache = %q, want MISS", got)
+	}
+	if got := second.Header().Get("X-Cache"); got != "HIT" {
+		t.Errorf("second X-Cache = %q, want HIT", got)
+	}
+	if second.Body.String() != "hello" || calls != 1 {
+		t.Errorf("body %q after %d handler calls, want hello after 1", second.Body.String(), calls)
+	}
+
+}
+
+// HEAD requests are served from the stored GET response, without a body.
+func TestHeadHitsGetEntry(t *testing.T) {
+
+	cache := lrucache.New(10, time.Hour, time.Hour)
+	defer cache.Close()
+	calls := 0
+	h := Middleware(cache)(counting(&calls, "hello", nil))
+
+	serve(h, http.MethodGet, "/x")
+	head := serve(h, http.MethodHead, "/x")
+
+	if got := head.Header().Get("X-Cache"); got != "HIT" {
+		t.Errorf("HEAD X-Cache = %q, want HIT", got)
+	}
+	if head.Body.Len() != 0 {
+		t.Errorf("HEAD response has body %q", head.Body.String())
+	}
+	if calls != 1 {
+		t.Errorf("handler called %d times, want 1", calls)
+	}
+
+}
+
+func TestHeadIsNotStored(t *testing.T) {
+
+	cache := lrucache.New(10, time.Hour, time.Hour)
+	defer cache.Close()
+	calls := 0
+	h := Middleware(cache)(counting(&calls, "hello", nil))
+
+	serve(h, http.MethodHead, "/x")
+	get := serve(h, http.MethodGet, "/x")
+
+	if got := get.Header().Get("X-Cache"); got != "MISS" {
+		t.Errorf("GET after HEAD X-Cache = %q, want MISS", got)
+	}
+	if get.Body.String() != "hello" {
+		t.Errorf("GET body = %q", get.Body.String())
+	}
+
+}
+
+func TestSMaxAgeTakesPrecedence(t *testing.T) {
+
+	clock := clocktest.New(time.Unix(0, 0))
+	cache := lrucache.New(10, time.Hour, time.Hour, lrucache.WithClock(clock))
+	defer cache.Close()
+	calls := 0
+	h := Middleware(cache)(counting(&calls, "x", func(h http.Header) {
+		h.Set("Cache-Control", "public, max-age=5, s-maxage=60")
+	}))
+
+	serve(h, http.MethodGet, "/x")
+	if ttl, _ := cache.TTL(defaultKey(httptest.NewRequest(http.MethodGet, "/x", nil))); ttl != time.Minute {
+		t.Errorf("TTL = %v, want s-maxage 1m", ttl)
+	}
+	clock.Advance(30 * time.Second)
+	serve(h, http.MethodGet, "/x")
+	if calls != 1 {
+		t.Errorf("handler called %d times within s-maxage, want 1", calls)
+	}
+
+}
+
+func TestUncacheableResponses(t *testing.T) {
+
+	cases := map[string]func(h http.Header){
+		"no-store":   func(h http.Header) { h.Set("Cache-Control", "no-store") },
+		"private":    func(h http.Header) { h.Set("Cache-Control", "private, max-age=60") },
+		"set-cookie": func(h http.Header) { h.Set("Set-Cookie", "a=b") },
+		"max-age=0":  func(h http.Header) { h.Set("Cache-Control", "max-age=0") },
+	}
+	for name, setup := range cases {
+		cache := lrucache.New(10, time.Hour, time.Hour)
+		calls := 0
+		h := Middleware(cache)(counting(&calls, "x", setup))
+		serve(h, http.MethodGet, "/x")
+		serve(h, http.MethodGet, "/x")
+		if calls != 2 {
+			t.Errorf("%s: response was cached", name)
+		}
+		cache.Close()
+	}
+
+}
+
+func TestStatusAndMethodFilter(t *testing.T) {
+
+	cache := lrucache.New(10, time.Hour, time.Hour)
+	defer cache.Close()
+	calls := 0
+	h := Middleware(cache)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+
+	serve(h, http.MethodGet, "/missing")
+	serve(h, http.MethodGet, "/missing")
+	serve(h, http.MethodPost, "/missing")
+	if calls != 3 {
+		t.Errorf("handler called %d times, want 3 (404 and POST are not cached)", calls)
+	}
+
+}
+
+// A handler changing the header it gets on a HIT must not change the
+// cached response.
+func TestHitHeaderIsACopy(t *testing.T) {
+
+	cache := lrucache.New(10, time.Hour, time.Hour)
+	defer cache.Close()
+	calls := 0
+	inner := counting(&calls, "x", func(h http.Header) { h.Set("X-Tag", "a") })
+	mutate := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r)
+			if values := w.Header()["X-Tag"]; len(values) > 0 {
+				values[0] = "changed"
+			}
+		})
+	}
+	h := mutate(Middleware(cache)(inner))
+
+	serve(h, http.MethodGet, "/x")
+	serve(h, http.MethodGet, "/x")
+	third := serve(Middleware(cache)(inner), http.MethodGet, "/x")
+	if got := third.Header().Get("X-Tag"); got != "a" {
+		t.Errorf("cached header = %q, want a", got)
+	}
+
+}
+
+func TestMaxBodySize(t *testing.T) {
+
+	cache := lrucache.New(10, time.Hour, time.Hour)
+	defer cache.Close()
+	calls := 0
+	h := Middleware(cache, WithMaxBodySize(3))(counting(&calls, "too long", nil))
+
+	if body := serve(h, http.MethodGet, "/x").Body.String(); body != "too long" {
+		t.Errorf("body = %q, oversized bodies must still pass through", body)
+	}
+	serve(h, http.MethodGet, "/x")
+	if calls != 2 {
+		t.Error("oversized body was cached")
+	}
+
+}
@@ -0,0 +1,204 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+// Package httpclientcache provides an http.RoundTripper that caches GET
+// responses in an LRUCache, honoring Cache-Control max-age, Expires and
+// Vary, so outbound API calls are cached transparently.
+package httpclientcache
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/georghagn/nexcache/lrucache"
+)
+
+// DefaultMaxBodySize is the largest response body that is cached.
+const DefaultMaxBodySize = 1 << 20
+
+// Transport caches responses of the wrapped RoundTripper.
+type Transport struct {
+	cache       *lrucache.LRUCache
+	next        http.RoundTripper
+	maxBodySize int64
+}
+
+// New wraps next (http.DefaultTransport if nil) with cache.
+func New(cache *lrucache.LRUCache, next http.RoundTripper) *Transport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Transport{cache: cache, next: next, maxBodySize: DefaultMaxBodySize}
+}
+
+// SetMaxBodySize changes the largest response body that is cached.
+func (t *Transport) SetMaxBodySize(n int64) {
+	t.maxBodySize = n
+}
+
+// Client returns an http.Client that uses t.
+func (t *Transport) Client() *http.Client {
+	return &http.Client{Transport: t}
+}
+
+// RoundTrip serves GET requests from the cache when possible. Cached
+// responses carry the header "X-From-Cache: 1". Requests with
+// Cache-Control no-cache or no-store bypass the cache.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+
+	if req.Method != http.MethodGet || hasDirective(req.Header, "no-store") || hasDirective(req.Header, "no-cache") {
+		return t.next.RoundTrip(req)
+	}
+
+	base := req.Method + " " + req.URL.String()
+	if resp, ok := t.lookup(base, req); ok {
+		return resp, nil
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	ttl, ok := freshness(resp)
+	if !ok || resp.ContentLength > t.maxBodySize {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, t.maxBodySize+1))
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if int64(len(body)) > t.maxBodySize {
+		return resp, nil
+	}
+
+	raw, err := httputil.DumpResponse(resp, true)
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return resp, nil
+	}
+
+	names := varyNames(resp.Header)
+	t.store("vary|"+base, names, ttl)
+	t.store(variantKey(base, names, req.Header), raw, ttl)
+	return resp, nil
+
+}
+
+// lookup returns the cached response for req. Entries of other types,
+// e.g. after a JSON snapshot was restored or a foreign key collided, count
+// as a miss.
+func (t *Transport) lookup(base string, req *http.Request) (*http.Response, bool) {
+
+	cached, found := t.cache.Get("vary|" + base)
+	if !found {
+		return nil, false
+	}
+	names, ok := cached.([]string)
+	if !ok {
+		return nil, false
+	}
+	cached, found = t.cache.Get(variantKey(base, names, req.Header))
+	if !found {
+		return nil, false
+	}
+	raw, ok := cached.([]byte)
+	if !ok {
+		return nil, false
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(raw)), req)
+	if err != nil {
+		return nil, false
+	}
+	resp.Header.Set("X-From-Cache", "1")
+	return resp, true
+
+}
+
+func (t *Transport) store(key string, value interface{}, ttl time.Duration) {
+	t.cache.SetWithTTL(key, value, ttl)
+}
+
+// freshness returns how long resp may be cached.
+func freshness(resp *http.Response) (time.Duration, bool) {
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, false
+	}
+	if hasDirective(resp.Header, "no-store") || hasDirective(resp.Header, "no-cache") || hasDirective(resp.Header, "private") {
+		return 0, false
+	}
+	for _, name := range varyNames(resp.Header) {
+		if name == "*" {
+			return 0, false
+		}
+	}
+
+	for _, directive := range directives(resp.Header) {
+		if v, ok := strings.CutPrefix(directive, "max-age="); ok {
+			seconds, err := strconv.Atoi(v)
+			return time.Duration(seconds) * time.Second, err == nil && seconds > 0
+		}
+	}
+	if expires, err := http.ParseTime(resp.Header.Get("Expires")); err == nil {
+		date, err := http.ParseTime(resp.Header.Get("Date"))
+		if err != nil {
+			date = time.Now()
+		}
+		ttl := expires.Sub(date)
+		return ttl, ttl > 0
+	}
+	return 0, false
+
+}
+
+// variantKey extends base with the request's values of the Vary headers.
+func variantKey(base string, names []string, header http.Header) string {
+	var b strings.Builder
+	b.WriteString(base)
+	for _, name := range names {
+		b.WriteString("|" + name + "=" + strings.Join(header.Values(name), ","))
+	}
+	return b.String()
+}
+
+func varyNames(header http.Header) []string {
+	var names []string
+	for _, v := range header.Values("Vary") {
+		for _, name := range strings.Split(v, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, http.CanonicalHeaderKey(name))
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func directives(header http.Header) []string {
+	var out []string
+	for _, v := range header.Values("Cache-Control") {
+		for _, d := range strings.Split(v, ",") {
+			out = append(out, strings.ToLower(strings.TrimSpace(d)))
+		}
+	}
+	return out
+}
+
+func hasDirective(header http.Header, name string) bool {
+	for _, d := range directives(header) {
+		if d == name {
+			return true
+		}
+	}
+	return false
+}
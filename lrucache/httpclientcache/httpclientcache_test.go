@@ -0,0 +1,204 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package httpclientcache
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/georghagn/nexcache/lrucache"
+	"github.com/georghagn/nexcache/lrucache/clocktest"
+)
+
+// origin answers every request with body and the headers set by setup,
+// and counts its calls.
+type origin struct {
+	calls int
+	body  string
+	setup func(h http.Header)
+}
+
+func (o *origin) RoundTrip(req *http.Request) (*http.Response, error) {
+
+	o.calls++
+	header := http.Header{}
+	if o.setup != nil {
+		o.setup(header)
+	}
+	return &http.Response{
+		StatusCode:    http.StatusOK,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(o.body)),
+		ContentLength: int64(len(o.body)),
+		Request:       req,
+	}, nil
+
+}
+
+func maxAge(h http.Header) { h.Set("Cache-Control", "max-age=60") }
+
+func get(t *testing.T, client *http.Client, url string, header http.Header) *http.Response {
+
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp
+
+}
+
+func body(t *testing.T, resp *http.Response) string {
+	t.Helper()
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestCachesFreshResponses(t *testing.T) {
+
+	clock := clocktest.New(time.Unix(0, 0))
+	cache := lrucache.New(10, time.Hour, time.Hour, lrucache.WithClock(clock))
+	defer cache.Close()
+	o := &origin{body: "hello", setup: maxAge}
+	client := New(cache, o).Client()
+
+	body(t, get(t, client, "http://api/x", nil))
+	resp := get(t, client, "http://api/x", nil)
+	if resp.Header.Get("X-From-Cache") != "1" || body(t, resp) != "hello" || o.calls != 1 {
+		t.Fatalf("second response was not served from the cache (%d origin calls)", o.calls)
+	}
+
+	clock.Advance(2 * time.Minute)
+	body(t, get(t, client, "http://api/x", nil))
+	if o.calls != 2 {
+		t.Error("response was served after max-age")
+	}
+
+}
+
+func TestVarySeparatesVariants(t *testing.T) {
+
+	cache := lrucache.New(10, time.Hour, time.Hour)
+	defer cache.Close()
+	o := &origin{body: "x", setup: func(h http.Header) {
+		maxAge(h)
+		h.Set("Vary", "Accept-Language")
+	}}
+	client := New(cache, o).Client()
+
+	de := http.Header{"Accept-Language": {"de"}}
+	en := http.Header{"Accept-Language": {"en"}}
+	body(t, get(t, client, "http://api/x", de))
+	body(t, get(t, client, "http://api/x", en))
+	body(t, get(t, client, "http://api/x", de))
+	if o.calls != 2 {
+		t.Errorf("origin called %d times, want one per variant", o.calls)
+	}
+
+}
+
+func TestUncacheableResponses(t *testing.T) {
+
+	cases := map[string]func(h http.Header){
+		"no max-age": nil,
+		"no-store":   func(h http.Header) { h.Set("Cache-Control", "no-store, max-age=60") },
+		"private":    func(h http.Header) { h.Set("Cache-Control", "private, max-age=60") },
+		"vary *":     func(h http.Header) { maxAge(h); h.Set("Vary", "*") },
+		"max-age=0":  func(h http.Header) { h.Set("Cache-Control", "max-age=0") },
+	}
+	for name, setup := range cases {
+		cache := lrucache.New(10, time.Hour, time.Hour)
+		o := &origin{body: "x", setup: setup}
+		client := New(cache, o).Client()
+		body(t, get(t, client, "http://api/x", nil))
+		body(t, get(t, client, "http://api/x", nil))
+		if o.calls != 2 {
+			t.Errorf("%s: response was cached", name)
+		}
+		cache.Close()
+	}
+
+}
+
+func TestExpiresHeader(t *testing.T) {
+
+	cache := lrucache.New(10, time.Hour, time.Hour, lrucache.WithClock(clocktest.New(time.Unix(0, 0))))
+	defer cache.Close()
+	date := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	o := &origin{body: "x", setup: func(h http.Header) {
+		h.Set("Date", date.Format(http.TimeFormat))
+		h.Set("Expires", date.Add(time.Minute).Format(http.TimeFormat))
+	}}
+	client := New(cache, o).Client()
+
+	body(t, get(t, client, "http://api/x", nil))
+	if ttl, found := cache.TTL("GET http://api/x"); !found || ttl != time.Minute {
+		t.Errorf("TTL = %v, %v, want Expires - Date = 1m", ttl, found)
+	}
+
+}
+
+func TestRequestNoCacheBypasses(t *testing.T) {
+
+	cache := lrucache.New(10, time.Hour, time.Hour)
+	defer cache.Close()
+	o := &origin{body: "x", setup: maxAge}
+	client := New(cache, o).Client()
+
+	body(t, get(t, client, "http://api/x", nil))
+	body(t, get(t, client, "http://api/x", http.Header{"Cache-Control": {"no-cache"}}))
+	if o.calls != 2 {
+		t.Error("request with no-cache was served from the cache")
+	}
+
+}
+
+// Values of other types under the transport's keys count as a miss.
+func TestForeignCacheValuesAreMisses(t *testing.T) {
+
+	cache := lrucache.New(10, time.Hour, time.Hour)
+	defer cache.Close()
+	cache.Set("vary|GET http://api/x", map[string]interface{}{"restored": true})
+	o := &origin{body: "x", setup: maxAge}
+	client := New(cache, o).Client()
+
+	if got := body(t, get(t, client, "http://api/x", nil)); got != "x" || o.calls != 1 {
+		t.Errorf("body %q after %d origin calls, want x from the origin", got, o.calls)
+	}
+	cache.Set("GET http://api/x", "not a response")
+	if got := body(t, get(t, client, "http://api/x", nil)); got != "x" {
+		t.Errorf("body = %q", got)
+	}
+
+}
+
+func TestMaxBodySize(t *testing.T) {
+
+	cache := lrucache.New(10, time.Hour, time.Hour)
+	defer cache.Close()
+	o := &origin{body: "too long", setup: maxAge}
+	tr := New(cache, o)
+	tr.SetMaxBodySize(3)
+	client := tr.Client()
+
+	if got := body(t, get(t, client, "http://api/x", nil)); got != "too long" {
+		t.Errorf("body = %q, oversized bodies must pass through", got)
+	}
+	body(t, get(t, client, "http://api/x", nil))
+	if o.calls != 2 {
+		t.Error("oversized body was cached")
+	}
+
+}
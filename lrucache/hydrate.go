@@ -0,0 +1,106 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"time"
+)
+
+// ---------------------- Lazy hydration ----------------------
+
+// lazyValue holds the still encoded value of an entry restored by
+// LoadFromFileLazy. It is decoded on first access.
+type lazyValue struct {
+	raw json.RawMessage
+}
+
+// MarshalJSON writes the encoded value unchanged, so saving a cache with
+// values that were never accessed does not decode them.
+func (v *lazyValue) MarshalJSON() ([]byte, error) {
+	return v.raw, nil
+}
+
+// lazyEntry is the on-disk form of a CacheEntry with an undecoded value.
+type lazyEntry struct {
+	Key       string
+	Value     json.RawMessage
+	ExpiresAt time.Time
+	Meta      map[string]string `json:",omitempty"`
+}
+
+// LoadFromFileLazy works like LoadFromFile, but only the index (keys and
+// expiry times) is built immediately; each value is decoded on its first
+// access. This lets a service with a huge snapshot become ready quickly.
+// It requires the JSON codec.
+func (c *LRUCache) LoadFromFileLazy(filename string) error {
+
+	c.lock()
+	defer c.unlock()
+
+	if c.closedLocked() {
+		return ErrClosed
+	}
+	if c.codec != JSONCodec {
+		return errors.New("lrucache: lazy loading requires the JSON codec")
+	}
+
+	file, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	stream, savedAt, err := c.readSnapshot(file)
+	if err != nil {
+		return err
+	}
+
+	if c.loadMode == LoadReplace {
+		c.resetLocked()
+	}
+
+	now := c.clock.Now()
+	for {
+		var entry lazyEntry
+		err := stream.next(&entry)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			if c.loadMode == LoadReplace {
+				c.resetLocked()
+			}
+			return err
+		}
+		entry.ExpiresAt = c.rebased(entry.ExpiresAt, savedAt, now)
+		if !expiredAt(entry.ExpiresAt, now) {
+			c.restoreLocked(&CacheEntry{
+				Key:       entry.Key,
+				Value:     &lazyValue{raw: entry.Value},
+				ExpiresAt: entry.ExpiresAt,
+				Meta:      entry.Meta,
+			}, now)
+		}
+	}
+
+}
+
+// hydrate decodes a lazily loaded value in place and returns the value,
+// decompressed if needed. Must be called with c.mu held.
+func hydrate(entry *CacheEntry) interface{} {
+
+	if lazy, ok := entry.Value.(*lazyValue); ok {
+		if value, err := decodeValue(lazy.raw); err == nil {
+			entry.Value = value
+		} else {
+			entry.Value = nil
+		}
+	}
+	return plain(entry)
+
+}
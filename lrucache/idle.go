@@ -0,0 +1,64 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// ---------------------- Max idle time ----------------------
+
+// WithMaxIdle expires entries that have not been read or written for d,
+// independently of their TTL: an entry lives until its TTL runs out or it
+// has been idle for d, whichever comes first. Peek, Contains and the
+// introspection methods do not count as access. The limit is kept in
+// memory only; entries restored from a snapshot start a new idle period.
+func WithMaxIdle(d time.Duration) Option {
+	return func(c *LRUCache) {
+		c.maxIdle = d
+	}
+}
+
+// SetWithMaxIdle stores the value like Set, but with its own idle limit
+// instead of the one set by WithMaxIdle. A maxIdle of 0 disables the idle
+// limit for this entry. A later Set restores the cache-wide limit.
+func (c *LRUCache) SetWithMaxIdle(key string, value interface{}, maxIdle time.Duration) {
+
+	c.acquireWrite()
+	defer c.releaseWrite()
+
+	c.lock()
+	defer c.unlock()
+
+	if c.closedLocked() {
+		c.logAdmission(key, AdmissionRejectedClosed, nil)
+		return
+	}
+
+	now := c.clock.Now()
+	entry := c.putLocked(key, value, c.deadline(now, c.ttl))
+	entry.setIdle(maxIdle, now)
+	c.schedule(entry)
+
+}
+
+// setIdle sets the idle limit of the entry and starts a new idle period.
+func (e *CacheEntry) setIdle(idle time.Duration, now time.Time) {
+	e.idle = idle
+	atomic.StoreInt64(&e.lastUse, now.UnixNano())
+}
+
+// touch records an access for the idle limit. It is safe under the read
+// lock.
+func (e *CacheEntry) touch(now time.Time) {
+	if e.idle > 0 {
+		atomic.StoreInt64(&e.lastUse, now.UnixNano())
+	}
+}
+
+// idleExpired reports whether the entry has been idle for too long at now.
+func (e *CacheEntry) idleExpired(now time.Time) bool {
+	return e.idle > 0 && now.UnixNano()-atomic.LoadInt64(&e.lastUse) > int64(e.idle)
+}
@@ -0,0 +1,116 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"time"
+)
+
+// ---------------------- Inspection ----------------------
+
+// Len returns the number of stored entries. Expired entries that have not
+// been removed by the cleanup routine yet are included.
+func (c *LRUCache) Len() int {
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.list.Len()
+
+}
+
+// Peek retrieves a value like Get, but without updating the LRU position.
+// Expired entries are reported as missing.
+func (c *LRUCache) Peek(key string) (interface{}, bool) {
+
+	c.lock()
+	defer c.unlock()
+
+	if c.closedLocked() {
+		return nil, false
+	}
+
+	if element, found := c.cache[key]; found {
+		entry := element.Value.(*CacheEntry)
+		if !entry.expired(c.clock.Now()) {
+			return c.copyOut(hydrate(entry)), true
+		}
+	}
+	return nil, false
+
+}
+
+// Contains reports whether a non-expired entry exists for key,
+// without updating the LRU position.
+func (c *LRUCache) Contains(key string) bool {
+	_, found := c.Peek(key)
+	return found
+}
+
+// Keys returns the keys of all non-expired entries, most recently used first.
+// The LRU order is not changed.
+func (c *LRUCache) Keys() []string {
+
+	c.lock()
+	defer c.unlock()
+
+	now := c.clock.Now()
+	keys := make([]string, 0, c.list.Len())
+	for element := c.list.Front(); element != nil; element = element.Next() {
+		entry := element.Value.(*CacheEntry)
+		if !entry.expired(now) {
+			keys = append(keys, entry.Key)
+		}
+	}
+	return keys
+
+}
+
+// Range calls fn for every non-expired entry, most recently used first,
+// until fn returns false. It iterates over a snapshot taken under the lock,
+// so fn may safely call other cache methods. The LRU order is not changed.
+func (c *LRUCache) Range(fn func(key string, value interface{}, expiresAt time.Time) bool) {
+
+	for _, entry := range c.snapshot() {
+		if !fn(entry.Key, entry.Value, entry.ExpiresAt) {
+			return
+		}
+	}
+
+}
+
+// OldestFirst calls fn for every non-expired entry in eviction order,
+// least recently used first, until fn returns false. Like Range, it works
+// on a snapshot and does not change the LRU order. With PolicySIEVE the
+// order is the insertion order, which the eviction hand follows.
+func (c *LRUCache) OldestFirst(fn func(entry CacheEntry) bool) {
+
+	entries := c.snapshot()
+	for i := len(entries) - 1; i >= 0; i-- {
+		if !fn(entries[i]) {
+			return
+		}
+	}
+
+}
+
+// snapshot copies all non-expired entries in MRU→LRU order.
+func (c *LRUCache) snapshot() []CacheEntry {
+
+	c.lock()
+	defer c.unlock()
+
+	now := c.clock.Now()
+	entries := make([]CacheEntry, 0, c.list.Len())
+	for element := c.list.Front(); element != nil; element = element.Next() {
+		entry := element.Value.(*CacheEntry)
+		if !entry.expired(now) {
+			snap := *entry
+			snap.Value = hydrate(entry)
+			entries = append(entries, snap)
+		}
+	}
+	return entries
+
+}
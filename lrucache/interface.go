@@ -0,0 +1,26 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"context"
+)
+
+// ---------------------- Cache interface ----------------------
+
+// Cache is the core API of LRUCache. Code that depends on Cache instead
+// of *LRUCache can be tested with the fake of package cachetest.
+type Cache interface {
+	Get(key string) (interface{}, bool)
+	Peek(key string) (interface{}, bool)
+	Contains(key string) bool
+	Set(key string, value interface{})
+	Delete(key string) bool
+	GetOrLoad(key string, loader func() (interface{}, error)) (interface{}, error)
+	GetOrLoadContext(ctx context.Context, key string, loader LoaderFunc) (interface{}, error)
+	Len() int
+	Clear()
+}
+
+var _ Cache = (*LRUCache)(nil)
@@ -0,0 +1,154 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+// Package list implements the doubly linked recency list of the cache. It
+// offers the subset of container/list the cache uses, but keeps removed
+// elements on a free list and hands them out again on the next push, so a
+// cache at capacity evicts and inserts without allocating list elements.
+//
+// As a consequence an element must not be used after Remove: it may
+// already hold another value.
+package list
+
+// maxFree bounds the number of removed elements kept for reuse.
+const maxFree = 1024
+
+// Element is an element of a List.
+type Element struct {
+	next, prev *Element
+	list       *List
+
+	// Value is the value stored with the element.
+	Value interface{}
+}
+
+// Next returns the next element or nil.
+func (e *Element) Next() *Element {
+	if p := e.next; e.list != nil && p != &e.list.root {
+		return p
+	}
+	return nil
+}
+
+// Prev returns the previous element or nil.
+func (e *Element) Prev() *Element {
+	if p := e.prev; e.list != nil && p != &e.list.root {
+		return p
+	}
+	return nil
+}
+
+// List is a doubly linked list with a sentinel root element.
+// The zero value is not usable; create lists with New.
+type List struct {
+	root  Element
+	len   int
+	free  *Element // removed elements, chained through next
+	nfree int
+}
+
+// New returns an empty list.
+func New() *List {
+	l := new(List)
+	l.root.next = &l.root
+	l.root.prev = &l.root
+	return l
+}
+
+// Len returns the number of elements in the list.
+func (l *List) Len() int { return l.len }
+
+// Front returns the first element or nil.
+func (l *List) Front() *Element {
+	if l.len == 0 {
+		return nil
+	}
+	return l.root.next
+}
+
+// Back returns the last element or nil.
+func (l *List) Back() *Element {
+	if l.len == 0 {
+		return nil
+	}
+	return l.root.prev
+}
+
+// PushFront inserts v at the front and returns its element.
+func (l *List) PushFront(v interface{}) *Element {
+	return l.insert(l.alloc(v), &l.root)
+}
+
+// PushBack inserts v at the back and returns its element.
+func (l *List) PushBack(v interface{}) *Element {
+	return l.insert(l.alloc(v), l.root.prev)
+}
+
+// Remove unlinks e if it belongs to l and returns its value. The element
+// is recycled.
+func (l *List) Remove(e *Element) interface{} {
+
+	v := e.Value
+	if e.list != l {
+		return v
+	}
+
+	e.prev.next = e.next
+	e.next.prev = e.prev
+	l.len--
+
+	e.prev = nil
+	e.list = nil
+	e.Value = nil
+	if l.nfree < maxFree {
+		e.next = l.free
+		l.free = e
+		l.nfree++
+	} else {
+		e.next = nil
+	}
+	return v
+
+}
+
+// MoveToFront moves e to the front. Elements of other lists and removed
+// elements are ignored.
+func (l *List) MoveToFront(e *Element) {
+
+	if e.list != l || l.root.next == e {
+		return
+	}
+	e.prev.next = e.next
+	e.next.prev = e.prev
+	l.len--
+	l.insert(e, &l.root)
+
+}
+
+// alloc returns a recycled element or a new one.
+func (l *List) alloc(v interface{}) *Element {
+
+	e := l.free
+	if e == nil {
+		e = new(Element)
+	} else {
+		l.free = e.next
+		l.nfree--
+	}
+	e.Value = v
+	return e
+
+}
+
+// insert links e after at.
+func (l *List) insert(e, at *Element) *Element {
+
+	e.prev = at
+	e.next = at.next
+	e.prev.next = e
+	e.next.prev = e
+	e.list = l
+	l.len++
+	return e
+
+}
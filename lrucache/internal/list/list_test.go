@@ -0,0 +1,105 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package list
+
+import (
+	"reflect"
+	"testing"
+)
+
+// values returns the values of l from front to back, and checks that the
+// backward walk agrees.
+func values(t *testing.T, l *List) []interface{} {
+
+	t.Helper()
+	var out []interface{}
+	for e := l.Front(); e != nil; e = e.Next() {
+		out = append(out, e.Value)
+	}
+	var back []interface{}
+	for e := l.Back(); e != nil; e = e.Prev() {
+		back = append([]interface{}{e.Value}, back...)
+	}
+	if !reflect.DeepEqual(out, back) || len(out) != l.Len() {
+		t.Fatalf("forward %v, backward %v, Len %d disagree", out, back, l.Len())
+	}
+	return out
+
+}
+
+func TestPushMoveRemove(t *testing.T) {
+
+	l := New()
+	if l.Front() != nil || l.Back() != nil {
+		t.Fatal("empty list has elements")
+	}
+
+	b := l.PushFront("b")
+	a := l.PushFront("a")
+	c := l.PushBack("c")
+	if got := values(t, l); !reflect.DeepEqual(got, []interface{}{"a", "b", "c"}) {
+		t.Fatalf("list = %v", got)
+	}
+
+	l.MoveToFront(c)
+	l.MoveToFront(c)
+	if got := values(t, l); !reflect.DeepEqual(got, []interface{}{"c", "a", "b"}) {
+		t.Fatalf("after MoveToFront = %v", got)
+	}
+
+	if v := l.Remove(a); v != "a" {
+		t.Errorf("Remove = %v, want a", v)
+	}
+	l.Remove(a) // a second Remove is ignored
+	l.MoveToFront(a)
+	if got := values(t, l); !reflect.DeepEqual(got, []interface{}{"c", "b"}) {
+		t.Fatalf("after Remove = %v", got)
+	}
+	l.Remove(b)
+	l.Remove(c)
+	values(t, l)
+
+}
+
+func TestForeignElementsAreIgnored(t *testing.T) {
+
+	l, other := New(), New()
+	l.PushBack(1)
+	e := other.PushBack(2)
+
+	l.MoveToFront(e)
+	if v := l.Remove(e); v != 2 {
+		t.Errorf("Remove of a foreign element = %v, want its value", v)
+	}
+	if l.Len() != 1 || other.Len() != 1 {
+		t.Errorf("Len = %d and %d, want 1 and 1", l.Len(), other.Len())
+	}
+
+}
+
+// Removed elements are handed out again, up to maxFree of them.
+func TestRecyclesElements(t *testing.T) {
+
+	l := New()
+	e := l.PushBack("old")
+	l.Remove(e)
+	if reused := l.PushBack("new"); reused != e || reused.Value != "new" {
+		t.Error("removed element was not reused")
+	}
+
+	elems := make([]*Element, maxFree+10)
+	for i := range elems {
+		elems[i] = l.PushBack(i)
+	}
+	for _, e := range elems {
+		l.Remove(e)
+	}
+	if l.nfree != maxFree {
+		t.Errorf("%d free elements kept, want %d", l.nfree, maxFree)
+	}
+	if allocs := testing.AllocsPerRun(100, func() { l.Remove(l.PushBack(1)) }); allocs != 0 {
+		t.Errorf("push after remove allocates %v times", allocs)
+	}
+
+}
@@ -0,0 +1,142 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"strings"
+	"time"
+)
+
+// ---------------------- Batch invalidation ----------------------
+
+// invalidationBatchSize is the number of keys removed per lock acquisition
+// by the asynchronous invalidation functions.
+const invalidationBatchSize = 1000
+
+// InvalidateKeys removes keys in the background, in batches that release
+// the lock in between, so large invalidations block neither the caller nor
+// other users of the cache. The returned channel receives nil (or
+// ErrClosed) once all keys are processed and is then closed.
+func (c *LRUCache) InvalidateKeys(keys []string) <-chan error {
+	return c.InvalidateKeysWithProgress(keys, nil)
+}
+
+// InvalidateKeysWithProgress is like InvalidateKeys and additionally calls
+// progress after every batch with the number of processed keys.
+func (c *LRUCache) InvalidateKeysWithProgress(keys []string, progress func(done, total int)) <-chan error {
+
+	keys = append([]string(nil), keys...)
+	result := make(chan error, 1)
+
+	c.spawn(func() {
+		defer close(result)
+		for done := 0; done < len(keys); {
+			end := min(done+invalidationBatchSize, len(keys))
+			if !c.deleteBatch(keys[done:end]) {
+				result <- ErrClosed
+				return
+			}
+			done = end
+			if progress != nil {
+				progress(done, len(keys))
+			}
+		}
+		result <- nil
+	})
+
+	return result
+
+}
+
+// InvalidatePrefix removes all keys starting with prefix in the background;
+// see InvalidateKeys.
+func (c *LRUCache) InvalidatePrefix(prefix string) <-chan error {
+
+	var keys []string
+	for _, key := range c.Keys() {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	return c.InvalidateKeys(keys)
+
+}
+
+// Invalidate drops the cached copy of key and reports whether it was
+// present. Unlike Delete it neither notifies subscribers nor reaches the
+// backing store; it is meant for applying invalidations that originate
+// elsewhere, e.g. from another node.
+func (c *LRUCache) Invalidate(key string) bool {
+	return c.InvalidateIf(key, nil)
+}
+
+// InvalidateIf works like Invalidate, but first calls ok with the cache
+// locked and drops key only if ok returns true. No write can happen in
+// between, so replication layers use it to resolve conflicts against the
+// latest local write. ok must not call the cache; nil means true.
+func (c *LRUCache) InvalidateIf(key string, ok func() bool) bool {
+
+	c.lock()
+	defer c.unlock()
+
+	if c.closedLocked() || (ok != nil && !ok()) {
+		return false
+	}
+
+	element, found := c.cache[key]
+	if !found {
+		return false
+	}
+	c.removeElement(element)
+	c.walDelete(key)
+	return true
+
+}
+
+// Replicate stores a value that was written elsewhere, e.g. on another
+// node, with the given expiry time. Like a loaded value it is published
+// as EventLoad and not written to a backing store, so replication layers
+// do not echo it back.
+func (c *LRUCache) Replicate(key string, value interface{}, expiresAt time.Time) {
+	c.ReplicateIf(key, value, expiresAt, nil)
+}
+
+// ReplicateIf works like Replicate, but first calls ok with the cache
+// locked and stores the value only if ok returns true. It reports whether
+// the value was stored; see InvalidateIf.
+func (c *LRUCache) ReplicateIf(key string, value interface{}, expiresAt time.Time, ok func() bool) bool {
+
+	c.lock()
+	defer c.unlock()
+
+	if c.closedLocked() || (ok != nil && !ok()) {
+		return false
+	}
+
+	c.filling = true
+	c.putLocked(key, value, expiresAt)
+	c.filling = false
+	return true
+
+}
+
+// deleteBatch removes keys under a single lock acquisition.
+// It returns false if the cache is closed.
+func (c *LRUCache) deleteBatch(keys []string) bool {
+
+	c.lock()
+	defer c.unlock()
+
+	if c.closed {
+		return false
+	}
+	for _, key := range keys {
+		if element, found := c.cache[key]; found {
+			c.removeElement(element)
+			c.logDelete(key)
+		}
+	}
+	return true
+
+}
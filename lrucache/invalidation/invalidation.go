@@ -0,0 +1,166 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+// Package invalidation keeps the caches of several instances coherent by
+// broadcasting local writes and deletes over a pub/sub broker (for example
+// Redis Pub/Sub) and dropping the affected keys on all other instances.
+// The module has no Redis client; a Broker adapter for a client of your
+// choice takes a few lines.
+package invalidation
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/georghagn/nexcache/lrucache"
+)
+
+// ErrEventsLost is reported when local events were dropped because Run
+// fell behind; see WithBuffer.
+var ErrEventsLost = errors.New("invalidation: local events lost")
+
+// opFlush asks the other nodes to drop their whole cache.
+const opFlush = "flush"
+
+// Broker is a pub/sub transport. A Redis client can implement it with
+// PUBLISH and SUBSCRIBE on the given channel.
+type Broker interface {
+	Publish(ctx context.Context, channel string, payload []byte) error
+	// Subscribe delivers the payloads published on channel until ctx is done.
+	Subscribe(ctx context.Context, channel string) (<-chan []byte, error)
+}
+
+// message is the payload sent over the broker.
+type message struct {
+	Origin string `json:"origin"`
+	Op     string `json:"op"`
+	Key    string `json:"key"`
+}
+
+// Node connects one cache to the broker.
+type Node struct {
+	cache   *lrucache.LRUCache
+	broker  Broker
+	channel string
+	origin  string
+	buffer  int
+	onError func(err error)
+}
+
+// Option configures a Node.
+type Option func(*Node)
+
+// WithOrigin sets the id that marks this node's own messages. By default
+// a random id is used.
+func WithOrigin(id string) Option {
+	return func(n *Node) {
+		n.origin = id
+	}
+}
+
+// WithBuffer sets how many local events may be queued before they are
+// dropped (default 1024); see LRUCache.Subscribe. When events are lost,
+// the node cannot tell which keys changed, so it reports ErrEventsLost
+// and asks all other nodes to clear their caches.
+func WithBuffer(size int) Option {
+	return func(n *Node) {
+		n.buffer = size
+	}
+}
+
+// WithErrorHandler receives publish errors, malformed messages and
+// ErrEventsLost.
+func WithErrorHandler(fn func(err error)) Option {
+	return func(n *Node) {
+		n.onError = fn
+	}
+}
+
+// New creates a node for cache that communicates on channel.
+func New(cache *lrucache.LRUCache, broker Broker, channel string, opts ...Option) *Node {
+
+	n := &Node{cache: cache, broker: broker, channel: channel, buffer: 1024}
+	for _, opt := range opts {
+		opt(n)
+	}
+	if n.origin == "" {
+		var id [8]byte
+		rand.Read(id[:])
+		n.origin = hex.EncodeToString(id[:])
+	}
+	return n
+
+}
+
+// Run publishes local Set and Delete events and invalidates keys written
+// or deleted on other nodes, until ctx is done. Values filled by loaders
+// are not broadcast, so nodes loading the same key do not invalidate each
+// other. Remote invalidations are applied with LRUCache.Invalidate and
+// therefore not echoed back.
+func (n *Node) Run(ctx context.Context) error {
+
+	remote, err := n.broker.Subscribe(ctx, n.channel)
+	if err != nil {
+		return err
+	}
+	local, cancel := n.cache.Subscribe(n.buffer)
+	defer cancel()
+
+	for {
+		select {
+		case event := <-local:
+			switch event.Type {
+			case lrucache.EventSet, lrucache.EventDelete:
+				n.publish(ctx, string(event.Type), event.Key)
+			case lrucache.EventLost:
+				n.report(fmt.Errorf("%w: %v events, flushing peers", ErrEventsLost, event.Value))
+				n.publish(ctx, opFlush, "")
+			}
+		case payload, ok := <-remote:
+			if !ok {
+				return ctx.Err()
+			}
+			n.apply(payload)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+}
+
+func (n *Node) publish(ctx context.Context, op, key string) {
+
+	payload, err := json.Marshal(message{Origin: n.origin, Op: op, Key: key})
+	if err == nil {
+		err = n.broker.Publish(ctx, n.channel, payload)
+	}
+	n.report(err)
+
+}
+
+func (n *Node) apply(payload []byte) {
+
+	var msg message
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		n.report(err)
+		return
+	}
+	switch {
+	case msg.Origin == n.origin:
+	case msg.Op == opFlush:
+		n.cache.Clear()
+	default:
+		n.cache.Invalidate(msg.Key)
+	}
+
+}
+
+func (n *Node) report(err error) {
+	if err != nil && n.onError != nil {
+		n.onError(err)
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package invalidation
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/georghagn/nexcache/lrucache"
+)
+
+// broker is an in-memory Broker. Publish blocks while hold is open.
+type broker struct {
+	mu         sync.Mutex
+	subs       []chan []byte
+	hold       chan struct{}
+	publishing chan struct{}
+}
+
+func newBroker() *broker {
+	return &broker{publishing: make(chan struct{}, 1)}
+}
+
+func (b *broker) Publish(ctx context.Context, channel string, payload []byte) error {
+
+	select {
+	case b.publishing <- struct{}{}:
+	default:
+	}
+	b.mu.Lock()
+	hold := b.hold
+	b.mu.Unlock()
+	if hold != nil {
+		<-hold
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, ch := range b.subs {
+		ch <- payload
+	}
+	return nil
+
+}
+
+func (b *broker) Subscribe(ctx context.Context, channel string) (<-chan []byte, error) {
+
+	ch := make(chan []byte, 1024)
+	b.mu.Lock()
+	b.subs = append(b.subs, ch)
+	b.mu.Unlock()
+	return ch, nil
+
+}
+
+func (b *broker) subscribers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subs)
+}
+
+// run starts n and returns once it is subscribed to the broker.
+func run(t *testing.T, ctx context.Context, b *broker, n *Node) {
+
+	t.Helper()
+	before := b.subscribers()
+	go n.Run(ctx)
+	waitFor(t, "subscription", func() bool { return b.subscribers() > before })
+
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+}
+
+func TestWritesInvalidateOtherNodes(t *testing.T) {
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	b := newBroker()
+	a := lrucache.New(10, time.Hour, time.Hour)
+	defer a.Close()
+	other := lrucache.New(10, time.Hour, time.Hour)
+	defer other.Close()
+	a.Set("gone", 1)
+	other.Set("k", 1)
+	other.Set("gone", 1)
+	other.Set("loaded", 1)
+
+	run(t, ctx, b, New(a, b, "c", WithOrigin("a")))
+	run(t, ctx, b, New(other, b, "c", WithOrigin("other")))
+
+	a.GetOrLoad("loaded", func() (interface{}, error) { return 2, nil })
+	a.Set("k", 2)
+	a.Delete("gone")
+
+	waitFor(t, "invalidation", func() bool { return !other.Contains("k") && !other.Contains("gone") })
+	if !other.Contains("loaded") {
+		t.Error("a loader fill invalidated another node")
+	}
+	if val, _ := a.Get("k"); val != 2 {
+		t.Error("a node invalidated its own write")
+	}
+
+}
+
+// When local events overflow the buffer, the other nodes are cleared and
+// the loss is reported.
+func TestLostEventsClearOtherNodes(t *testing.T) {
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	b := newBroker()
+	a := lrucache.New(100, time.Hour, time.Hour)
+	defer a.Close()
+	other := lrucache.New(100, time.Hour, time.Hour)
+	defer other.Close()
+	other.Set("stale", 1)
+
+	var mu sync.Mutex
+	var errs []error
+	run(t, ctx, b, New(other, b, "c"))
+	run(t, ctx, b, New(a, b, "c", WithBuffer(1), WithErrorHandler(func(err error) {
+		mu.Lock()
+		errs = append(errs, err)
+		mu.Unlock()
+	})))
+
+	hold := make(chan struct{})
+	b.mu.Lock()
+	b.hold = hold
+	b.mu.Unlock()
+	a.Set("k0", 0)
+	<-b.publishing // Run is stuck publishing k0
+	for _, key := range []string{"k1", "k2", "k3", "k4"} {
+		a.Set(key, 1)
+	}
+	b.mu.Lock()
+	b.hold = nil
+	b.mu.Unlock()
+	close(hold)
+	<-b.publishing // Run has taken k1, the buffer has room again
+	a.Set("k5", 1) // carries the EventLost
+
+	waitFor(t, "flush", func() bool { return !other.Contains("stale") })
+	mu.Lock()
+	defer mu.Unlock()
+	lost := false
+	for _, err := range errs {
+		lost = lost || errors.Is(err, ErrEventsLost)
+	}
+	if !lost {
+		t.Errorf("errors %v do not report ErrEventsLost", errs)
+	}
+
+}
+
+func TestMalformedMessageIsReported(t *testing.T) {
+
+	c := lrucache.New(10, time.Hour, time.Hour)
+	defer c.Close()
+	var reported error
+	n := New(c, nil, "c", WithErrorHandler(func(err error) { reported = err }))
+
+	n.apply([]byte("not json"))
+	if reported == nil {
+		t.Error("malformed message was not reported")
+	}
+
+}
@@ -0,0 +1,56 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"sync"
+)
+
+// ---------------------- Per-key locks ----------------------
+
+// keyLock is a mutex shared by all holders and waiters of one key.
+type keyLock struct {
+	mu   sync.Mutex
+	refs int // holders and waiters, guarded by LRUCache.keyLocksMu
+}
+
+// LockKey blocks until the caller holds the lock for key and returns the
+// function that releases it, which must be called exactly once:
+//
+//	unlock := cache.LockKey("user:42")
+//	defer unlock()
+//	v, _ := cache.Get("user:42")
+//	... update the database and the cache ...
+//
+// The locks are advisory: they serialize callers of LockKey for the same
+// key, but the cache methods do not take them. Locks of different keys do
+// not block each other, and the lock of a key is freed when nobody holds
+// or waits for it. It does not depend on whether the key is cached.
+func (c *LRUCache) LockKey(key string) (unlock func()) {
+
+	c.keyLocksMu.Lock()
+	if c.keyLocks == nil {
+		c.keyLocks = make(map[string]*keyLock)
+	}
+	kl, found := c.keyLocks[key]
+	if !found {
+		kl = &keyLock{}
+		c.keyLocks[key] = kl
+	}
+	kl.refs++
+	c.keyLocksMu.Unlock()
+
+	kl.mu.Lock()
+	return func() {
+		kl.mu.Unlock()
+
+		c.keyLocksMu.Lock()
+		kl.refs--
+		if kl.refs == 0 {
+			delete(c.keyLocks, key)
+		}
+		c.keyLocksMu.Unlock()
+	}
+
+}
@@ -0,0 +1,204 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ---------------------- Loader policies ----------------------
+
+// ErrLoaderFailed matches every *LoaderError with errors.Is.
+var ErrLoaderFailed = errors.New("lrucache: loader failed")
+
+// LoaderError is returned by the GetOrLoad family when the loader failed,
+// after timeouts and retries. Err is the cause; errors.Is and errors.As
+// see through it. For GetOrLoadMulti, Key is the first missing key.
+type LoaderError struct {
+	Key string
+	Err error
+}
+
+func (e *LoaderError) Error() string {
+	return fmt.Sprintf("lrucache: loading %q: %v", e.Key, e.Err)
+}
+
+func (e *LoaderError) Unwrap() error { return e.Err }
+
+// Is reports whether target is ErrLoaderFailed.
+func (e *LoaderError) Is(target error) bool { return target == ErrLoaderFailed }
+
+// ErrLoaderPanic matches every *PanicError with errors.Is.
+var ErrLoaderPanic = errors.New("lrucache: loader panicked")
+
+// PanicError is the cause of a LoaderError when the loader panicked. The
+// panic is recovered, so it neither crashes the process (loaders may run
+// on their own goroutine, see WithLoaderTimeout) nor leaves load state
+// behind; the failed load is not retried and nothing is cached.
+type PanicError struct {
+	Value interface{} // value passed to panic
+	Stack []byte      // stack of the loader goroutine at the panic
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Is reports whether target is ErrLoaderPanic.
+func (e *PanicError) Is(target error) bool { return target == ErrLoaderPanic }
+
+// LoaderFunc loads a value for GetOrLoadContext. It should honor ctx.
+type LoaderFunc func(ctx context.Context) (interface{}, error)
+
+// WithLoaderTimeout cuts off loader attempts that take longer than d; the
+// attempt then fails with context.DeadlineExceeded. Loaders that ignore
+// their context (all loaders passed to GetOrLoad) keep running in the
+// background, but the caller no longer waits for them.
+func WithLoaderTimeout(d time.Duration) Option {
+	return func(c *LRUCache) {
+		c.loaderTimeout = d
+	}
+}
+
+// WithLoaderRetries retries failed loader attempts up to n times. The
+// wait before the first retry is backoff and doubles with every further
+// retry. Validation errors (WithResultValidator) are not retried.
+func WithLoaderRetries(n int, backoff time.Duration) Option {
+	return func(c *LRUCache) {
+		c.loaderRetries = n
+		c.loaderBackoff = backoff
+	}
+}
+
+// LoadTracer observes loader calls, e.g. to record tracing spans. It is
+// called before the loader runs and may return a derived context that is
+// passed to the loader; the returned function receives the final error
+// after timeouts and retries.
+type LoadTracer func(ctx context.Context, key string) (context.Context, func(err error))
+
+// WithLoadTracer installs a LoadTracer. Adapters for tracing systems such
+// as OpenTelemetry can be built on it without the cache depending on them.
+func WithLoadTracer(tracer LoadTracer) Option {
+	return func(c *LRUCache) {
+		c.tracer = tracer
+	}
+}
+
+// GetOrLoadContext is like GetOrLoad, but the loader receives ctx and the
+// wait for timeouts and retries ends when ctx is done.
+func (c *LRUCache) GetOrLoadContext(ctx context.Context, key string, loader LoaderFunc) (interface{}, error) {
+
+	c.checkLoader(loader == nil)
+
+	if val, found, err := c.lookup(key); found || err != nil {
+		return val, err
+	}
+
+	return c.load(ctx, key, loader)
+
+}
+
+// callLoader runs the loader with the configured timeout and retries.
+func (c *LRUCache) callLoader(ctx context.Context, key string, loader LoaderFunc) (val interface{}, err error) {
+
+	if c.tracer != nil {
+		var end func(err error)
+		ctx, end = c.tracer(ctx, key)
+		defer func() { end(err) }()
+	}
+
+	backoff := c.loaderBackoff
+	for attempt := 0; ; attempt++ {
+		val, err = c.attemptLoad(ctx, key, loader)
+		if err == nil || attempt >= c.loaderRetries || ctx.Err() != nil || errors.Is(err, ErrLoaderPanic) {
+			return val, err
+		}
+
+		if backoff > 0 {
+			if err := c.wait(ctx, backoff); err != nil {
+				return nil, err
+			}
+		}
+		backoff *= 2
+	}
+
+}
+
+// wait blocks for d on the cache clock, so a fake clock drives the retry
+// backoff as well, or until ctx is done.
+func (c *LRUCache) wait(ctx context.Context, d time.Duration) error {
+
+	ticker := c.clock.NewTicker(d)
+	defer ticker.Stop()
+
+	select {
+	case <-ticker.C():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+}
+
+// attemptLoad runs a single loader attempt, bounded by the loader timeout.
+func (c *LRUCache) attemptLoad(ctx context.Context, key string, loader LoaderFunc) (interface{}, error) {
+
+	if c.loaderTimeout <= 0 {
+		return c.runLoader(ctx, key, loader)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.loaderTimeout)
+	defer cancel()
+
+	type result struct {
+		val interface{}
+		err error
+	}
+	done := make(chan result, 1)
+	c.spawn(func() {
+		val, err := c.runLoader(ctx, key, loader)
+		done <- result{val, err}
+	})
+
+	select {
+	case r := <-done:
+		return r.val, r.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+}
+
+// ignoreContext adapts a plain loader to a LoaderFunc.
+func ignoreContext(loader func() (interface{}, error)) LoaderFunc {
+	return func(context.Context) (interface{}, error) {
+		return loader()
+	}
+}
+
+// FillPolicy selects how the GetOrLoad family stores a loaded value when
+// the key was written by someone else while the loader ran.
+type FillPolicy int
+
+const (
+	// FillIfAbsent keeps a live value written during the load and returns
+	// it instead of the loaded one, so a concurrent Set is not lost. The
+	// loaded value is stored only if the key is still missing or expired
+	// (default).
+	FillIfAbsent FillPolicy = iota
+
+	// FillOverwrite always stores the loaded value, replacing whatever was
+	// written during the load.
+	FillOverwrite
+)
+
+// WithFillPolicy selects the FillPolicy.
+func WithFillPolicy(policy FillPolicy) Option {
+	return func(c *LRUCache) {
+		c.fillPolicy = policy
+	}
+}
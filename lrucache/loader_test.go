@@ -0,0 +1,178 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/georghagn/nexcache/lrucache"
+	"github.com/georghagn/nexcache/lrucache/clocktest"
+)
+
+// advanceUntil moves clock forward in steps until cond holds, for waits
+// that start on a background goroutine at an unknown moment.
+func advanceUntil(t *testing.T, clock *clocktest.Fake, step time.Duration, cond func() bool) {
+
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not reached while advancing the clock")
+		}
+		clock.Advance(step)
+		time.Sleep(time.Millisecond)
+	}
+
+}
+
+func TestGetOrLoadCachesOnlySuccess(t *testing.T) {
+
+	c := lrucache.New(10, time.Hour, time.Hour)
+	defer c.Close()
+	failure := errors.New("backend down")
+
+	_, err := c.GetOrLoad("k", func() (interface{}, error) { return nil, failure })
+	var loadErr *lrucache.LoaderError
+	if !errors.As(err, &loadErr) || loadErr.Key != "k" || !errors.Is(err, failure) || !errors.Is(err, lrucache.ErrLoaderFailed) {
+		t.Fatalf("error = %v, want a LoaderError for k wrapping the cause", err)
+	}
+	if c.Contains("k") {
+		t.Fatal("failed load was cached")
+	}
+
+	calls := 0
+	for i := 0; i < 2; i++ {
+		val, err := c.GetOrLoad("k", func() (interface{}, error) { calls++; return "v", nil })
+		if err != nil || val != "v" {
+			t.Fatalf("GetOrLoad = %v, %v", val, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("loader called %d times, want 1", calls)
+	}
+	if s := c.Stats(); s.Loads != 2 || s.LoadErrors != 1 {
+		t.Errorf("Loads %d, LoadErrors %d, want 2 and 1", s.Loads, s.LoadErrors)
+	}
+
+}
+
+func TestLoaderPanicIsRecovered(t *testing.T) {
+
+	c := lrucache.New(10, time.Hour, time.Hour, lrucache.WithLoaderRetries(3, 0))
+	defer c.Close()
+
+	calls := 0
+	_, err := c.GetOrLoad("k", func() (interface{}, error) { calls++; panic("boom") })
+	var panicErr *lrucache.PanicError
+	if !errors.Is(err, lrucache.ErrLoaderPanic) || !errors.As(err, &panicErr) || panicErr.Value != "boom" {
+		t.Fatalf("error = %v, want a PanicError", err)
+	}
+	if calls != 1 {
+		t.Errorf("panicking loader ran %d times, want 1 (no retries)", calls)
+	}
+
+}
+
+// The retry backoff runs on the cache clock and doubles per retry.
+func TestLoaderRetriesWaitOnClock(t *testing.T) {
+
+	clock := newClock()
+	c := lrucache.New(10, time.Hour, time.Hour, lrucache.WithClock(clock), lrucache.WithLoaderRetries(2, time.Hour))
+	defer c.Close()
+
+	var mu sync.Mutex
+	var attempts []time.Time
+	type result struct {
+		val interface{}
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		val, err := c.GetOrLoad("k", func() (interface{}, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			attempts = append(attempts, clock.Now())
+			if len(attempts) < 3 {
+				return nil, errors.New("flaky")
+			}
+			return "v", nil
+		})
+		done <- result{val, err}
+	}()
+
+	advanceUntil(t, clock, time.Hour, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(attempts) == 3
+	})
+	if r := <-done; r.err != nil || r.val != "v" {
+		t.Fatalf("GetOrLoad = %v, %v, want v after two retries", r.val, r.err)
+	}
+	if first, second := attempts[1].Sub(attempts[0]), attempts[2].Sub(attempts[1]); first < time.Hour || second < 2*time.Hour {
+		t.Errorf("waited %v and %v between attempts, want at least 1h and 2h", first, second)
+	}
+
+}
+
+func TestLoaderRateLimit(t *testing.T) {
+
+	clock := newClock()
+	c := lrucache.New(10, time.Hour, time.Hour, lrucache.WithClock(clock), lrucache.WithLoaderRateLimit(1, 1))
+	defer c.Close()
+	load := func() (interface{}, error) { return "v", nil }
+
+	if _, err := c.GetOrLoad("a", load); err != nil {
+		t.Fatal(err)
+	}
+	var loaded atomic.Bool
+	go func() {
+		c.GetOrLoad("b", load)
+		loaded.Store(true)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	if loaded.Load() {
+		t.Fatal("second load within the same second was not throttled")
+	}
+	advanceUntil(t, clock, 100*time.Millisecond, loaded.Load)
+
+}
+
+func TestLoaderRateLimitReject(t *testing.T) {
+
+	clock := newClock()
+	c := lrucache.New(10, time.Hour, time.Hour, lrucache.WithClock(clock),
+		lrucache.WithLoaderRateLimit(1, 1), lrucache.WithThrottleMode(lrucache.ThrottleReject))
+	defer c.Close()
+	load := func() (interface{}, error) { return "v", nil }
+
+	c.GetOrLoad("a", load)
+	if _, err := c.GetOrLoad("b", load); err != lrucache.ErrLoaderThrottled {
+		t.Errorf("load over the limit = %v, want ErrLoaderThrottled", err)
+	}
+	clock.Advance(time.Second)
+	if _, err := c.GetOrLoad("b", load); err != nil {
+		t.Errorf("load after refill = %v", err)
+	}
+
+}
+
+func TestLoaderTimeout(t *testing.T) {
+
+	c := lrucache.New(10, time.Hour, time.Hour, lrucache.WithLoaderTimeout(10*time.Millisecond))
+	defer c.Close()
+
+	_, err := c.GetOrLoadContext(context.Background(), "k", func(ctx context.Context) (interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error = %v, want DeadlineExceeded", err)
+	}
+
+}
@@ -0,0 +1,103 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import "time"
+
+// ---------------------- Load modes ----------------------
+
+// LoadMode selects how Load, LoadFromFile and LoadFromFileLazy combine a
+// snapshot with the live content of the cache.
+type LoadMode int
+
+const (
+	// LoadReplace drops the live content and replaces it with the
+	// snapshot (default).
+	LoadReplace LoadMode = iota
+
+	// LoadMerge adds the entries of the snapshot; keys that are already
+	// cached keep their live value.
+	LoadMerge
+
+	// LoadMergeOverwrite adds the entries of the snapshot; for keys that
+	// are already cached the value and expiry from the snapshot win.
+	LoadMergeOverwrite
+)
+
+// WithLoadMode selects how snapshots are loaded. In every mode entries are
+// only added while the cache has room, so the most recently used entries
+// of the snapshot are kept and live entries are never evicted by a load.
+// Added entries keep their order from the snapshot and are placed behind
+// the live entries.
+// If a merge fails halfway because the snapshot is corrupt, the entries
+// merged so far stay in the cache.
+func WithLoadMode(mode LoadMode) Option {
+	return func(c *LRUCache) {
+		c.loadMode = mode
+	}
+}
+
+// WithTTLRebase makes loaded entries live for the time they had left when
+// the snapshot was saved, counted from the load, but at most maxTTL
+// (0 = no limit). Without it, expiry times are absolute, so entries of an
+// old snapshot are mostly expired on a restart. Entries that had already
+// expired when the snapshot was saved, and entries without expiry, are not
+// changed. For snapshots written before the save time was recorded, the
+// remaining time is measured at load.
+func WithTTLRebase(maxTTL time.Duration) Option {
+	return func(c *LRUCache) {
+		c.rebase = true
+		c.rebaseMax = maxTTL
+	}
+}
+
+// rebased returns the expiry of an entry loaded at now from a snapshot
+// saved at savedAt, see WithTTLRebase.
+func (c *LRUCache) rebased(expiresAt, savedAt, now time.Time) time.Time {
+
+	if !c.rebase || expiresAt.IsZero() {
+		return expiresAt
+	}
+	if savedAt.IsZero() {
+		savedAt = now
+	}
+	remaining := expiresAt.Sub(savedAt)
+	if remaining <= 0 {
+		return expiresAt
+	}
+	if c.rebaseMax > 0 && remaining > c.rebaseMax {
+		remaining = c.rebaseMax
+	}
+	return now.Add(remaining)
+
+}
+
+// restoreLocked adds an entry read from a snapshot according to the load
+// mode. Must be called with c.mu held.
+func (c *LRUCache) restoreLocked(entry *CacheEntry, now time.Time) {
+
+	if element, found := c.cache[entry.Key]; found {
+		live := element.Value.(*CacheEntry)
+		if c.loadMode == LoadMerge && !live.expired(now) {
+			return
+		}
+		c.preserveForForks(live.Key)
+		c.unshare(live)
+		c.share(live, c.compress(entry.Value))
+		live.ExpiresAt = entry.ExpiresAt
+		live.Meta = entry.Meta
+		live.ttl = 0
+		live.epoch = c.epoch
+		c.schedule(live)
+		return
+	}
+
+	if c.list.Len()-c.pinned >= c.capacity {
+		return
+	}
+	c.preserveForForks(entry.Key)
+	c.share(entry, c.compress(entry.Value))
+	c.linkBackLocked(entry)
+
+}
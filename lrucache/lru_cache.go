@@ -4,18 +4,60 @@
 package lrucache
 
 import (
-	"container/list"
-	"encoding/json"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"io"
 	"os"
+	"path/filepath"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/georghagn/nexcache/lrucache/internal/list"
 )
 
+var (
+	// ErrClosed is returned by operations on a cache that has been closed.
+	ErrClosed = errors.New("lrucache: cache is closed")
+
+	// ErrKeyNotFound is returned by Lookup for missing and expired keys.
+	ErrKeyNotFound = errors.New("lrucache: key not found")
+)
+
+var _ io.Closer = (*LRUCache)(nil)
+
 // CacheEntry stores key, value, and expiry time
 type CacheEntry struct {
 	Key       string
 	Value     interface{}
 	ExpiresAt time.Time
+	Meta      map[string]string `json:",omitempty"` // see SetWithMeta
+
+	visited uint32        // SIEVE: accessed since the hand last passed (atomic)
+	prev    *entryVersion // previous version, kept when history is enabled
+	shared  *sharedValue  // deduplicated payload, see WithValueDedup
+	ttl     time.Duration // own TTL, 0 = cache default
+	tags    []string      // see SetWithTags
+	pinned  bool          // see Pin
+	prio    int           // see SetWithPriority
+	idle    time.Duration // max idle time, 0 = none, see WithMaxIdle
+	lastUse int64         // last access in Unix nanoseconds (atomic)
+	slot    int           // position in the expiry heap plus one, 0 = none
+	due     int64         // scheduled expiry in the heap, Unix nanoseconds
+	epoch   *epochState   // epoch of the last write, see NewEpoch
+	born    int64         // last write in Unix nanoseconds, see WithAnalytics
+}
+
+// expired reports whether the entry has expired at now.
+func (e *CacheEntry) expired(now time.Time) bool {
+	return expiredAt(e.ExpiresAt, now) || e.idleExpired(now) || e.invalidated()
+}
+
+// expiredAt reports whether an expiry time has passed at now.
+// A zero expiry time means the entry never expires.
+func expiredAt(expiresAt, now time.Time) bool {
+	return !expiresAt.IsZero() && now.After(expiresAt)
 }
 
 // LRUCache is mainstructure
@@ -23,21 +65,199 @@ type LRUCache struct {
 	capacity int
 	cache    map[string]*list.Element
 	list     *list.List
-	mu       sync.Mutex
+	mu       sync.RWMutex
 	ttl      time.Duration
 	stopCh   chan struct{}
+	stopOnce sync.Once
+	closed   bool
+	forks    map[*Fork]struct{}
+	policy   EvictionPolicy
+	hand     *list.Element // SIEVE eviction hand
+	clock    Clock
+
+	name      string
+	validator func(key string, value interface{}) error
+	history   bool
+	codec     Codec
+	sliding   bool
+
+	negativeTTL time.Duration // see WithNegativeCaching
+	negMu       sync.Mutex
+	negative    map[string]negativeEntry
+
+	writeSlots chan struct{} // bounds concurrent writers, nil = unlimited
+
+	autoSaveCh   chan time.Duration // reconfigures the auto-save ticker
+	autoSavePath string
+	autoSaveErr  error
+	gracefulPath string // see EnableGracefulPersistence
+
+	walCompactCh chan time.Duration // reconfigures the WAL compaction ticker
+	wal          *os.File
+	walPath      string
+	walErr       error
+
+	dedup    map[[sha256.Size]byte]*sharedValue
+	dedupMin int
+
+	goroutines atomic.Int64 // background goroutines owned by the cache
+
+	admission *admissionLog
+
+	onExpire func(key string, value interface{})
+	onEvict  func(entry CacheEntry, reason EvictReason)
+	pending  []func() // callbacks to run once c.mu is released
+
+	promotions chan *list.Element // hits recorded under the read lock
+	hits       atomic.Uint64
+	misses     atomic.Uint64
+
+	evictions  atomic.Uint64
+	loads      atomic.Uint64
+	loadErrors atomic.Uint64
+
+	keyIndex *keyTrie // hierarchical key index, see WithKeyHierarchy
+
+	tags map[string]map[string]struct{} // tag -> keys, see SetWithTags
+
+	namespaces map[string]*Namespace
+
+	pinned int // number of pinned entries, not counted against capacity
+
+	priorities map[int]int // entries per priority, nil until SetWithPriority
+
+	subscribers map[*subscriber]struct{}
+
+	loaderTimeout time.Duration
+	loaderRetries int
+	loaderBackoff time.Duration
+	tracer        LoadTracer
+
+	strict   bool
+	callback atomic.Int64 // strict mode: goroutine running a callback under the lock
+
+	canary    bool
+	canarySeq uint64
+	canaryMu  sync.Mutex // guards canaryErr, never held together with mu
+	canaryErr error
+
+	breakerMu        sync.Mutex
+	breakers         map[string]*breaker // nil = no circuit breaker
+	breakerFailures  int
+	breakerWindow    time.Duration
+	breakerCoolDown  time.Duration
+	breakerPerPrefix bool
+
+	shedding      bool
+	shedInflight  int
+	shedLatency   time.Duration
+	inflightLoads atomic.Int64
+	loadLatency   atomic.Int64 // moving average in nanoseconds
+
+	store    Store
+	behind   *writeBehind // nil = write-through
+	storeErr func(key string, err error)
+	maxBatch int  // ops per StoreBatch call, 0 = unlimited
+	filling  bool // insert by a loader or read-through, not a user write
+
+	replaying bool // RecoverWAL: not published, not written to the store
+
+	compressor    Compressor // nil = no compression
+	compressMin   int
+	compressedIn  atomic.Uint64 // bytes before compression
+	compressedOut atomic.Uint64 // bytes after compression
+
+	encKeyID string
+	encKeys  map[string][]byte // nil = snapshots are not encrypted
+
+	loadMode LoadMode
+
+	concurrentSnapshots bool
+
+	jitter float64 // see WithTTLJitter
+
+	maxIdle time.Duration // see WithMaxIdle
+
+	sweep      *list.Element // position of the incremental cleanup, nil = back
+	sweepMax   int
+	sweepPause time.Duration
+
+	expiries *expiryHeap // nil = cleanup scans the list, see WithExpiryHeap
+
+	epoch  *epochState   // current epoch
+	epochs []*epochState // epochs that were not invalidated, oldest first
+
+	keyLocksMu sync.Mutex // guards keyLocks; independent of mu
+	keyLocks   map[string]*keyLock
+
+	rebase    bool // see WithTTLRebase
+	rebaseMax time.Duration
+
+	limiter      *tokenBucket // nil = loaders are not rate limited
+	throttleMode ThrottleMode
+
+	hot *hotKeys // nil = hot keys are not tracked
+
+	analytics *analytics // nil = no access analytics
+
+	pressure bool          // see EnableMemoryPressure
+	memGauge func() uint64 // nil = heap size from runtime.MemStats
+
+	cleanupCh chan time.Duration // reconfigures the cleanup ticker
+
+	fillPolicy FillPolicy
+
+	copier   func(interface{}) interface{}
+	copyMode CopyMode
+
+	snapLimits SnapshotLimits
+
+	meta map[string]string // metadata of the entry being written, see SetWithMeta
+
+	storeMu   sync.Mutex
+	storeKeys map[string]*storeKey // order of pending store writes, see storeWrite
+	storeSeq  uint64
 }
 
-// New creates a new LRU cache
-func New(capacity int, ttl time.Duration, cleanupInterval time.Duration) *LRUCache {
+// New creates a new LRU cache. Entries expire ttl after they were
+// written; NoExpiration (0) keeps them until they are evicted.
+func New(capacity int, ttl time.Duration, cleanupInterval time.Duration, opts ...Option) *LRUCache {
 	cache := &LRUCache{
 		capacity: capacity,
 		cache:    make(map[string]*list.Element),
 		list:     list.New(),
 		ttl:      ttl,
 		stopCh:   make(chan struct{}),
+		forks:    make(map[*Fork]struct{}),
+		codec:    JSONCodec,
+		clock:    realClock{},
+
+		snapLimits: defaultSnapshotLimits,
+
+		autoSaveCh: make(chan time.Duration),
+
+		walCompactCh: make(chan time.Duration),
+
+		cleanupCh: make(chan time.Duration),
+
+		promotions: make(chan *list.Element, promotionQueueSize),
+	}
+	cache.epoch = &epochState{}
+	cache.epochs = []*epochState{cache.epoch}
+	for _, opt := range opts {
+		opt(cache)
+	}
+	if cache.strict {
+		checkConfig(capacity, ttl, cleanupInterval)
+	}
+	// The ticker is created here, not on the goroutine, so that a fake
+	// clock sees it as soon as New returns.
+	ticker := cache.clock.NewTicker(cleanupInterval)
+	cache.spawn(func() { cache.startCleanup(ticker) })
+	if cache.behind != nil {
+		flush := cache.clock.NewTicker(cache.behind.interval)
+		cache.spawn(func() { cache.runWriteBehind(flush) })
 	}
-	go cache.startCleanup(cleanupInterval)
 	return cache
 }
 
@@ -45,45 +265,130 @@ func New(capacity int, ttl time.Duration, cleanupInterval time.Duration) *LRUCac
 
 // Get retrieves a value or false if nothing is found or the date has expired.
 func (c *LRUCache) Get(key string) (interface{}, bool) {
+	val, found, err := c.lookup(key)
+	if !found && err == nil && c.store != nil {
+		return c.readThrough(key)
+	}
+	return val, found
+}
+
+// Lookup is Get with an error instead of a flag: it returns ErrKeyNotFound
+// for a missing or expired key and ErrClosed on a closed cache, which Get
+// cannot tell apart from a miss.
+func (c *LRUCache) Lookup(key string) (interface{}, error) {
+
+	val, found, err := c.lookup(key)
+	if err != nil {
+		return nil, err
+	}
+	if !found && c.store != nil {
+		val, found = c.readThrough(key)
+	}
+	if !found {
+		return nil, ErrKeyNotFound
+	}
+	return val, nil
 
-	c.mu.Lock()
-	defer c.mu.Unlock()
+}
+
+// lookup retrieves a cached value, counting hits and misses. Plain hits are
+// served under the read lock; expired entries, sliding expiry and lazily
+// loaded values take the exclusive lock. It returns ErrClosed on a closed cache.
+func (c *LRUCache) lookup(key string) (interface{}, bool, error) {
+
+	c.recordLookup(key)
+	if val, found, err, done := c.lookupShared(key); done {
+		return c.copyOut(val), found, err
+	}
+
+	c.lock()
+	defer c.unlock()
+
+	if c.closedLocked() {
+		return nil, false, ErrClosed
+	}
 
 	if element, found := c.cache[key]; found {
 		entry := element.Value.(*CacheEntry)
-		if time.Now().After(entry.ExpiresAt) {
-			c.removeElement(element)
-			return nil, false
+		if entry.expired(c.clock.Now()) {
+			if !c.servesStale() {
+				c.expireElement(element)
+			}
+			c.misses.Add(1)
+			return nil, false, nil
 		}
-		c.list.MoveToFront(element)
-		return entry.Value, true
+		c.access(element)
+		c.hits.Add(1)
+		return c.copyOut(hydrate(entry)), true, nil
 	}
 
-	return nil, false
+	c.misses.Add(1)
+	return nil, false, nil
 
 }
 
 // Set stores a value in the cache
 func (c *LRUCache) Set(key string, value interface{}) {
+	c.acquireWrite()
+	defer c.releaseWrite()
+	c.set(key, value)
+}
 
-	c.mu.Lock()
-	defer c.mu.Unlock()
+func (c *LRUCache) set(key string, value interface{}) {
 
-	if element, found := c.cache[key]; found {
-		entry := element.Value.(*CacheEntry)
-		entry.Value = value
-		entry.ExpiresAt = time.Now().Add(c.ttl)
-		c.list.MoveToFront(element)
+	c.lock()
+	defer c.unlock()
+
+	if c.closedLocked() {
+		c.logAdmission(key, AdmissionRejectedClosed, nil)
+		return
+	}
+
+	c.putLocked(key, value, c.deadline(c.clock.Now(), c.ttl))
+
+}
+
+// SetWithTTL is like Set, but uses ttl instead of the cache's default TTL;
+// NoExpiration stores the value without expiry. Unlike Set followed by
+// Expire, readers never see the value with the default TTL.
+func (c *LRUCache) SetWithTTL(key string, value interface{}, ttl time.Duration) {
+
+	c.acquireWrite()
+	defer c.releaseWrite()
+
+	c.lock()
+	defer c.unlock()
+
+	if c.closedLocked() {
+		c.logAdmission(key, AdmissionRejectedClosed, nil)
 		return
 	}
 
-	if c.list.Len() >= c.capacity {
-		c.ejectOldest()
+	c.putLocked(key, value, c.deadline(c.clock.Now(), ttl)).ttl = ttl
+
+}
+
+// Delete removes an entry and reports whether a non-expired entry existed.
+func (c *LRUCache) Delete(key string) bool {
+
+	c.lock()
+	defer c.unlock()
+
+	if c.closedLocked() {
+		return false
 	}
+	c.forgetFailure(key)
 
-	entry := &CacheEntry{Key: key, Value: value, ExpiresAt: time.Now().Add(c.ttl)}
-	element := c.list.PushFront(entry)
-	c.cache[key] = element
+	element, found := c.cache[key]
+	if !found {
+		return false
+	}
+	entry := element.Value.(*CacheEntry)
+	live := !entry.expired(c.clock.Now())
+	c.removeElement(element)
+	c.logDelete(key)
+	c.storeDelete(key)
+	return live
 
 }
 
@@ -93,27 +398,13 @@ func (c *LRUCache) Set(key string, value interface{}) {
 // Only successful loader results are saved.
 func (c *LRUCache) GetOrLoad(key string, loader func() (interface{}, error)) (interface{}, error) {
 
-	c.mu.Lock()
-	if element, found := c.cache[key]; found {
-		entry := element.Value.(*CacheEntry)
-		if time.Now().After(entry.ExpiresAt) {
-			c.removeElement(element)
-		} else {
-			c.list.MoveToFront(element)
-			val := entry.Value
-			c.mu.Unlock()
-			return val, nil
-		}
-	}
-	c.mu.Unlock()
+	c.checkLoader(loader == nil)
 
-	val, err := loader()
-	if err != nil {
-		return nil, err
+	if val, found, err := c.lookup(key); found || err != nil {
+		return val, err
 	}
 
-	c.Set(key, val)
-	return val, nil
+	return c.load(context.Background(), key, ignoreContext(loader))
 
 }
 
@@ -124,95 +415,263 @@ func (c *LRUCache) GetOrLoadWithFallback(
 	fallback interface{},
 ) (interface{}, error) {
 
-	c.mu.Lock()
-	if element, found := c.cache[key]; found {
-		entry := element.Value.(*CacheEntry)
-		if time.Now().After(entry.ExpiresAt) {
-			c.removeElement(element)
-		} else {
-			c.list.MoveToFront(element)
-			val := entry.Value
-			c.mu.Unlock()
-			return val, nil
-		}
+	c.checkLoader(loader == nil)
+
+	if val, found, err := c.lookup(key); found || err != nil {
+		return val, err
 	}
-	c.mu.Unlock()
 
-	val, err := loader()
+	val, err := c.load(context.Background(), key, ignoreContext(loader))
 	if err != nil {
 		return fallback, err
 	}
-
-	c.Set(key, val)
 	return val, nil
 
 }
 
+// load runs the loader, validates its result and stores it with the
+// default TTL. Only results accepted by the validator are cached.
+func (c *LRUCache) load(ctx context.Context, key string, loader LoaderFunc) (interface{}, error) {
+	return c.loadWithTTL(ctx, key, loader, 0, false)
+}
+
+// loadWithTTL is load; with own set, the result is stored with ttl
+// instead of the default TTL.
+func (c *LRUCache) loadWithTTL(ctx context.Context, key string, loader LoaderFunc, ttl time.Duration, own bool) (interface{}, error) {
+
+	if err := c.negativeErr(key); err != nil {
+		return nil, err
+	}
+	if c.shouldShed(ctx) {
+		return nil, ErrLoadShed
+	}
+	if !c.allowLoad(key) {
+		return nil, ErrCircuitOpen
+	}
+	if err := c.throttle(ctx); err != nil {
+		if err == ErrLoaderThrottled && c.servesStale() {
+			if val, found := c.staleValue(key); found {
+				return val, nil
+			}
+		}
+		return nil, err
+	}
+
+	done := c.trackLoad()
+	val, err := c.callLoader(ctx, key, loader)
+	done()
+	c.recordLoad(ctx, key, err)
+	c.countLoad(err)
+	if err != nil {
+		err = &LoaderError{Key: key, Err: err}
+		c.rememberFailure(key, err, false)
+		return nil, err
+	}
+
+	if c.validator != nil {
+		if err := c.validator(key, val); err != nil {
+			c.logAdmission(key, AdmissionRejectedByValidator, err)
+			c.rememberFailure(key, err, true)
+			return nil, err
+		}
+	}
+
+	c.forgetFailure(key)
+	return c.fill(key, val, ttl, own), nil
+
+}
+
+// fill inserts a loaded value according to the fill policy and returns
+// the value the caller should see. Unlike Set it is published as
+// EventLoad and not written to a backing store. With own set, the value
+// gets ttl instead of the default TTL.
+func (c *LRUCache) fill(key string, value interface{}, ttl time.Duration, own bool) interface{} {
+
+	c.acquireWrite()
+	defer c.releaseWrite()
+
+	c.lock()
+	defer c.unlock()
+
+	if c.closedLocked() {
+		c.logAdmission(key, AdmissionRejectedClosed, nil)
+		return value
+	}
+
+	now := c.clock.Now()
+	if c.fillPolicy == FillIfAbsent {
+		if element, found := c.cache[key]; found {
+			if entry := element.Value.(*CacheEntry); !entry.expired(now) {
+				// Written by someone else while the loader ran.
+				return c.copyOut(hydrate(entry))
+			}
+		}
+	}
+
+	if !own {
+		ttl = c.ttl
+	}
+	c.filling = true
+	entry := c.putLocked(key, value, c.deadline(now, ttl))
+	c.filling = false
+	if own {
+		entry.ttl = ttl
+	}
+	return value
+
+}
+
 // ---------------------- Persistence ----------------------
 
-// SaveToFile stores the cache as JSON
+// Save writes a snapshot of the cache to w using the configured codec
+// (JSON by default). Entries are encoded one at a time, so no copy of the
+// whole cache is built; only WithEncryption needs the snapshot in memory.
+// The cache is locked while w is written, so slow writers should be given
+// a buffer. WithConcurrentSnapshots trades a copy of the entry headers for
+// not holding the lock while writing.
+func (c *LRUCache) Save(w io.Writer) error {
+
+	c.lock()
+	if c.closedLocked() {
+		c.unlock()
+		return ErrClosed
+	}
+	if c.concurrentSnapshots {
+		entries := c.copyEntriesLocked()
+		c.unlock()
+		return c.writeEntries(w, entries)
+	}
+	defer c.unlock()
+
+	return c.writeSnapshot(w)
+
+}
+
+// SaveToFile stores the cache like Save. The file is written atomically:
+// a temporary file is renamed into place.
 func (c *LRUCache) SaveToFile(filename string) error {
 
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	c.lock()
+	defer c.unlock()
 
-	file, err := os.Create(filename)
+	if c.closedLocked() {
+		return ErrClosed
+	}
+
+	return c.snapshotFileLocked(filename)
+
+}
+
+func (c *LRUCache) saveLocked(filename string) error {
+	return writeFileAtomic(filename, c.writeSnapshot)
+}
+
+// Load replaces the cache content with a snapshot read from r, as written
+// by Save or SaveToFile with the same codec; WithLoadMode selects merging
+// instead. Entries beyond the capacity are skipped. Entries are decoded one
+// at a time. Truncated or modified snapshots are refused with
+// ErrCorruptSnapshot; if the damage is only found after the old content
+// was dropped, the cache is left empty. Snapshots from versions without
+// the checksummed format are still accepted.
+func (c *LRUCache) Load(r io.Reader) error {
+
+	c.lock()
+	defer c.unlock()
+
+	if c.closedLocked() {
+		return ErrClosed
+	}
+
+	stream, savedAt, err := c.readSnapshot(r)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
 
-	var entries []CacheEntry
-	for element := c.list.Front(); element != nil; element = element.Next() {
-		entry := element.Value.(*CacheEntry)
-		entries = append(entries, *entry)
+	if c.loadMode == LoadReplace {
+		c.resetLocked()
 	}
 
-	return json.NewEncoder(file).Encode(entries)
+	now := c.clock.Now()
+	for {
+		entry := &CacheEntry{}
+		err := c.nextEntry(stream, entry)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			if c.loadMode == LoadReplace {
+				c.resetLocked()
+			}
+			return err
+		}
+		entry.ExpiresAt = c.rebased(entry.ExpiresAt, savedAt, now)
+		if !entry.expired(now) {
+			c.restoreLocked(entry, now)
+		}
+	}
 
 }
 
-// LoadFromFile loads cache content from JSON file
+// LoadFromFile loads a snapshot file like Load.
 func (c *LRUCache) LoadFromFile(filename string) error {
 
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
 	file, err := os.Open(filename)
 	if err != nil {
 		return err
 	}
 	defer file.Close()
 
-	var entries []CacheEntry
-	if err := json.NewDecoder(file).Decode(&entries); err != nil {
-		return err
-	}
-
-	c.cache = make(map[string]*list.Element)
-	c.list = list.New()
-
-	for _, entry := range entries {
-		if time.Now().Before(entry.ExpiresAt) {
-			element := c.list.PushFront(&entry)
-			c.cache[entry.Key] = element
-		}
-	}
-	return nil
+	return c.Load(file)
 
 }
 
 // ---------------------- Background cleanup ----------------------
 
-func (c *LRUCache) startCleanup(interval time.Duration) {
+func (c *LRUCache) startCleanup(ticker Ticker) {
 
-	ticker := time.NewTicker(interval)
-	defer ticker.Stop()
+	// A closure, since SetCleanupInterval replaces the ticker.
+	defer func() { ticker.Stop() }()
+
+	var autoSave, compact Ticker
+	var autoSaveC, compactC <-chan time.Time
+	defer func() {
+		if autoSave != nil {
+			autoSave.Stop()
+		}
+		if compact != nil {
+			compact.Stop()
+		}
+	}()
 
 	for {
 		select {
-		case <-ticker.C:
+		case <-ticker.C():
 			c.cleanupExpiredEntries()
+		case interval := <-c.cleanupCh:
+			ticker.Stop()
+			ticker = c.clock.NewTicker(interval)
+		case interval := <-c.autoSaveCh:
+			if autoSave != nil {
+				autoSave.Stop()
+				autoSave, autoSaveC = nil, nil
+			}
+			if interval > 0 {
+				autoSave = c.clock.NewTicker(interval)
+				autoSaveC = autoSave.C()
+			}
+		case <-autoSaveC:
+			c.autoSaveNow()
+		case interval := <-c.walCompactCh:
+			if compact != nil {
+				compact.Stop()
+				compact, compactC = nil, nil
+			}
+			if interval > 0 {
+				compact = c.clock.NewTicker(interval)
+				compactC = compact.C()
+			}
+		case <-compactC:
+			c.compactWAL()
 		case <-c.stopCh:
 			return
 		}
@@ -222,13 +681,22 @@ func (c *LRUCache) startCleanup(interval time.Duration) {
 
 func (c *LRUCache) cleanupExpiredEntries() {
 
-	c.mu.Lock()
-	defer c.mu.Unlock()
+	c.lock()
+	defer c.unlock()
+
+	if c.expiries != nil {
+		c.expireDueLocked(c.clock.Now())
+		return
+	}
+	if c.sweepMax > 0 || c.sweepPause > 0 {
+		c.sweepLocked()
+		return
+	}
 	for element := c.list.Back(); element != nil; {
 		entry := element.Value.(*CacheEntry)
 		prev := element.Prev()
-		if time.Now().After(entry.ExpiresAt) {
-			c.removeElement(element)
+		if entry.expired(c.clock.Now()) {
+			c.expireElement(element)
 		}
 		element = prev
 	}
@@ -237,20 +705,258 @@ func (c *LRUCache) cleanupExpiredEntries() {
 
 // StopCleanup ends the cleanup routine.
 func (c *LRUCache) StopCleanup() {
-	close(c.stopCh)
+	c.stopOnce.Do(func() { close(c.stopCh) })
+}
+
+// ---------------------- Lifecycle ----------------------
+
+// Close stops the cleanup routine and shuts the cache down. If auto-save,
+// graceful persistence or the WAL is enabled, a final snapshot is written
+// and its error returned.
+// Afterwards Get reports a miss, Set is ignored and all operations
+// returning an error return ErrClosed. Close implements io.Closer;
+// calling it more than once is safe.
+func (c *LRUCache) Close() error {
+
+	c.StopCleanup()
+	if c.behind != nil {
+		defer c.flushBehind(true)
+	}
+
+	c.lock()
+	defer c.unlock()
+
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+
+	err := c.closeWALLocked()
+	if c.autoSavePath != "" {
+		if saveErr := c.saveLocked(c.autoSavePath); err == nil {
+			err = saveErr
+		}
+	}
+	if c.gracefulPath != "" && c.gracefulPath != c.autoSavePath {
+		if saveErr := c.saveLocked(c.gracefulPath); err == nil {
+			err = saveErr
+		}
+	}
+	return err
+
 }
 
 // ---------------------- Helpers ----------------------
 
+// writeFileAtomic writes to a temporary file in the target directory and
+// renames it to filename, so readers never see a partially written file.
+func writeFileAtomic(filename string, write func(w io.Writer) error) error {
+
+	tmp, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filename)
+
+}
+
 func (c *LRUCache) removeElement(element *list.Element) {
 	entry := element.Value.(*CacheEntry)
+	c.preserveForForks(entry.Key)
+	if c.hand == element {
+		c.hand = element.Prev()
+	}
+	if c.sweep == element {
+		c.sweep = element.Prev()
+	}
+	c.unschedule(entry)
+	c.unshare(entry)
+	if c.keyIndex != nil {
+		c.keyIndex.remove(entry.Key)
+	}
+	c.untag(entry)
+	c.unpinLocked(entry)
+	c.countPriority(entry.prio, -1)
 	delete(c.cache, entry.Key)
 	c.list.Remove(element)
 }
 
-func (c *LRUCache) ejectOldest() {
-	oldest := c.list.Back()
-	if oldest != nil {
-		c.removeElement(oldest)
+// resetLocked drops all entries before the content is replaced wholesale.
+// Must be called with c.mu held.
+func (c *LRUCache) resetLocked() {
+
+	c.detachForks()
+	c.cache = make(map[string]*list.Element)
+	c.list = list.New()
+	c.hand = nil
+	c.sweep = nil
+	if c.expiries != nil {
+		c.expiries = &expiryHeap{}
+	}
+	if c.dedup != nil {
+		c.dedup = make(map[[sha256.Size]byte]*sharedValue)
+	}
+	if c.keyIndex != nil {
+		c.keyIndex = newKeyTrie(c.keyIndex.sep)
 	}
+	c.tags = nil
+	c.pinned = 0
+	if c.priorities != nil {
+		c.priorities = make(map[int]int)
+	}
+
+}
+
+// putLocked inserts or updates an entry and returns it.
+// Must be called with c.mu held.
+func (c *LRUCache) putLocked(key string, value interface{}, expiresAt time.Time) *CacheEntry {
+
+	value = c.copyIn(value)
+	if element, found := c.cache[key]; found {
+		entry := element.Value.(*CacheEntry)
+		c.preserveForForks(key)
+		c.remember(entry)
+		c.unshare(entry)
+		c.share(entry, c.compress(value))
+		entry.ExpiresAt = expiresAt
+		c.applyMeta(entry)
+		entry.ttl = 0
+		entry.epoch = c.epoch
+		c.stampWrite(entry)
+		if c.maxIdle > 0 || entry.idle > 0 {
+			entry.setIdle(c.maxIdle, c.clock.Now())
+		}
+		c.schedule(entry)
+		c.promote(element)
+		c.logSet(entry)
+		c.storeSet(key, value)
+		return entry
+	}
+
+	if c.list.Len()-c.pinned >= c.capacity {
+		c.ejectOldest(EvictCapacity)
+	}
+
+	c.preserveForForks(key)
+	entry := &CacheEntry{Key: key, ExpiresAt: expiresAt}
+	c.applyMeta(entry)
+	c.share(entry, c.compress(value))
+	c.linkLocked(entry)
+	c.logSet(entry)
+	c.storeSet(key, value)
+	return entry
+
+}
+
+// linkLocked adds a new entry at the front of the list.
+// Must be called with c.mu held.
+func (c *LRUCache) linkLocked(entry *CacheEntry) *list.Element {
+	return c.indexLocked(c.list.PushFront(entry))
+}
+
+// linkBackLocked adds a new entry at the back of the list, so entries
+// restored in MRU→LRU order keep that order. Must be called with c.mu held.
+func (c *LRUCache) linkBackLocked(entry *CacheEntry) *list.Element {
+	return c.indexLocked(c.list.PushBack(entry))
+}
+
+// indexLocked registers a freshly linked element. Must be called with c.mu held.
+func (c *LRUCache) indexLocked(element *list.Element) *list.Element {
+
+	entry := element.Value.(*CacheEntry)
+	if c.maxIdle > 0 && entry.idle == 0 {
+		entry.setIdle(c.maxIdle, c.clock.Now())
+	}
+	entry.epoch = c.epoch
+	c.stampWrite(entry)
+	c.schedule(entry)
+	c.cache[entry.Key] = element
+	if c.keyIndex != nil {
+		c.keyIndex.insert(entry.Key)
+	}
+	c.countPriority(entry.prio, 1)
+	return element
+
+}
+
+// promote records an access according to the eviction policy.
+func (c *LRUCache) promote(element *list.Element) {
+	if c.policy == PolicySIEVE {
+		atomic.StoreUint32(&element.Value.(*CacheEntry).visited, 1)
+		return
+	}
+	c.list.MoveToFront(element)
+}
+
+// ejectOldest evicts the next victim of the eviction policy and reports
+// whether there was one. Must be called with c.mu held.
+func (c *LRUCache) ejectOldest(reason EvictReason) bool {
+
+	var victim *list.Element
+	if c.priorities != nil {
+		victim = c.priorityVictim()
+	} else if c.policy == PolicySIEVE {
+		victim = c.sieveVictim()
+	} else {
+		victim = c.lruVictim()
+	}
+	if victim == nil {
+		return false
+	}
+	entry := victim.Value.(*CacheEntry)
+	if reason == EvictMemory {
+		c.logAdmission(entry.Key, AdmissionEvictedMemory, nil)
+	} else {
+		c.logAdmission(entry.Key, AdmissionEvicted, nil)
+	}
+	c.evictions.Add(1)
+	c.removeElement(victim)
+	c.evicted(entry, reason)
+	return true
+
+}
+
+// sieveVictim moves the hand from the tail towards the head, clearing
+// visited bits, and returns the first unvisited entry. It returns nil if
+// all entries are pinned, like lruVictim.
+func (c *LRUCache) sieveVictim() *list.Element {
+
+	if c.list.Len() == c.pinned {
+		return nil
+	}
+
+	hand := c.hand
+	if hand == nil {
+		hand = c.list.Back()
+	}
+	// The first pass clears all visited bits, so the second one finds an
+	// unpinned entry for sure.
+	for steps := 2 * c.list.Len(); steps > 0; steps-- {
+		entry := hand.Value.(*CacheEntry)
+		if !entry.pinned {
+			if atomic.LoadUint32(&entry.visited) == 0 {
+				c.hand = hand.Prev()
+				return hand
+			}
+			atomic.StoreUint32(&entry.visited, 0)
+		}
+		if hand = hand.Prev(); hand == nil {
+			hand = c.list.Back()
+		}
+	}
+	return nil
+
 }
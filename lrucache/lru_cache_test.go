@@ -0,0 +1,140 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/georghagn/nexcache/lrucache"
+	"github.com/georghagn/nexcache/lrucache/clocktest"
+)
+
+func newClock() *clocktest.Fake {
+	return clocktest.New(time.Unix(1700000000, 0))
+}
+
+// waitFor polls cond until it holds, for work done by background
+// goroutines such as the cleanup or a write-behind flush.
+func waitFor(t *testing.T, what string, cond func() bool) {
+
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+}
+
+func TestEvictsLeastRecentlyUsed(t *testing.T) {
+
+	c := lrucache.New(2, time.Hour, time.Hour)
+	defer c.Close()
+
+	var evicted []string
+	c.OnEvict(func(key string, _ interface{}, reason lrucache.EvictReason) {
+		if reason == lrucache.EvictCapacity {
+			evicted = append(evicted, key)
+		}
+	})
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Get("a") // b is now the least recently used
+	c.Set("c", 3)
+
+	if c.Contains("b") || !c.Contains("a") || !c.Contains("c") {
+		t.Errorf("keys after eviction = %v, want a and c", c.Keys())
+	}
+	if !reflect.DeepEqual(evicted, []string{"b"}) {
+		t.Errorf("evicted %v, want [b]", evicted)
+	}
+	if got := c.Stats().Evictions; got != 1 {
+		t.Errorf("Evictions = %d, want 1", got)
+	}
+
+}
+
+// Peek and Contains neither promote an entry nor count as hit or miss.
+func TestPeekDoesNotPromote(t *testing.T) {
+
+	c := lrucache.New(2, time.Hour, time.Hour)
+	defer c.Close()
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+	if val, found := c.Peek("a"); !found || val != 1 {
+		t.Fatalf("Peek(a) = %v, %v", val, found)
+	}
+	c.Set("c", 3)
+
+	if c.Contains("a") {
+		t.Error("Peek promoted the entry")
+	}
+	if s := c.Stats(); s.Hits != 0 || s.Misses != 0 {
+		t.Errorf("Stats = %+v, Peek and Contains must not count", s)
+	}
+
+}
+
+func TestUpdateKeepsSingleEntry(t *testing.T) {
+
+	c := lrucache.New(2, time.Hour, time.Hour)
+	defer c.Close()
+
+	c.Set("a", 1)
+	c.Set("a", 2)
+
+	if c.Len() != 1 {
+		t.Errorf("Len = %d, want 1", c.Len())
+	}
+	if val, _ := c.Get("a"); val != 2 {
+		t.Errorf("Get(a) = %v, want 2", val)
+	}
+
+}
+
+func TestDeleteAndStats(t *testing.T) {
+
+	c := lrucache.New(10, time.Hour, time.Hour)
+	defer c.Close()
+
+	c.Set("a", 1)
+	if !c.Delete("a") || c.Delete("a") {
+		t.Error("Delete must report true once for an existing key")
+	}
+	c.Set("b", 2)
+	c.Get("a")
+	c.Get("b")
+
+	s := c.Stats()
+	if s.Hits != 1 || s.Misses != 1 || s.HitRate() != 0.5 {
+		t.Errorf("Stats = %+v, HitRate %v, want one hit and one miss", s, s.HitRate())
+	}
+
+}
+
+func TestClosedCacheRejectsWrites(t *testing.T) {
+
+	c := lrucache.New(10, time.Hour, time.Hour)
+	c.Set("a", 1)
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.TrySet("b", 2); err != lrucache.ErrClosed {
+		t.Errorf("TrySet after Close = %v, want ErrClosed", err)
+	}
+	if _, err := c.GetOrLoad("c", func() (interface{}, error) { return 3, nil }); err != lrucache.ErrClosed {
+		t.Errorf("GetOrLoad after Close = %v, want ErrClosed", err)
+	}
+	if err := c.Close(); err != nil {
+		t.Errorf("second Close = %v", err)
+	}
+
+}
@@ -0,0 +1,403 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+// Package memcached serves an LRUCache over the memcached ASCII protocol
+// (get, gets, set, add, replace, cas, delete, touch, stats, version,
+// quit), so existing memcached clients can use it unchanged.
+package memcached
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/georghagn/nexcache/lrucache"
+)
+
+// maxItemSize is the largest value accepted, as in memcached (1 MiB).
+const maxItemSize = 1 << 20
+
+// relativeLimit: memcached treats expiration times above 30 days as Unix
+// timestamps.
+const relativeLimit = 30 * 24 * 60 * 60
+
+// maxKeyLen is the longest key accepted, as in memcached.
+const maxKeyLen = 250
+
+// maxLineLen bounds a command line, so a client cannot exhaust memory with
+// a line that never ends. It leaves room for multi-key gets.
+const maxLineLen = 64 << 10
+
+// errLineTooLong is returned by readLine for lines over maxLineLen.
+var errLineTooLong = errors.New("memcached: line too long")
+
+// item is what the server stores in the cache.
+type item struct {
+	flags uint32
+	data  []byte
+	cas   uint64
+}
+
+// Server speaks the memcached protocol on top of a cache. Values written
+// through other APIs of the cache are not visible to memcached clients.
+type Server struct {
+	cache   *lrucache.LRUCache
+	cas     atomic.Uint64
+	started time.Time
+
+	mu     sync.Mutex
+	ln     net.Listener
+	conns  map[net.Conn]struct{}
+	closed bool
+}
+
+// NewServer creates a server for cache.
+func NewServer(cache *lrucache.LRUCache) *Server {
+	return &Server{cache: cache, started: time.Now(), conns: make(map[net.Conn]struct{})}
+}
+
+// ListenAndServe listens on the TCP address addr and serves clients.
+func (s *Server) ListenAndServe(addr string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ln)
+}
+
+// Serve accepts connections on ln until Close is called.
+func (s *Server) Serve(ln net.Listener) error {
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		ln.Close()
+		return net.ErrClosed
+	}
+	s.ln = ln
+	s.mu.Unlock()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			s.mu.Lock()
+			closed := s.closed
+			s.mu.Unlock()
+			if closed {
+				return nil
+			}
+			return err
+		}
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+		go s.ServeConn(conn)
+	}
+
+}
+
+// Close stops the listener and closes all client connections. The cache
+// is left open.
+func (s *Server) Close() error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	for conn := range s.conns {
+		conn.Close()
+	}
+	if s.ln != nil {
+		return s.ln.Close()
+	}
+	return nil
+
+}
+
+// ServeConn handles one client connection until it is closed or sends quit.
+func (s *Server) ServeConn(conn io.ReadWriteCloser) {
+
+	defer func() {
+		conn.Close()
+		if c, ok := conn.(net.Conn); ok {
+			s.mu.Lock()
+			delete(s.conns, c)
+			s.mu.Unlock()
+		}
+	}()
+
+	r := bufio.NewReader(conn)
+	w := bufio.NewWriter(conn)
+	for {
+		line, err := readLine(r)
+		if err == errLineTooLong {
+			// The rest of the line cannot be parsed, so the connection
+			// cannot be resynchronized.
+			w.WriteString("CLIENT_ERROR line too long\r\n")
+			w.Flush()
+			return
+		}
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			w.WriteString("ERROR\r\n")
+		} else if !s.handle(fields, r, w) {
+			w.Flush()
+			return
+		}
+		if r.Buffered() == 0 {
+			if w.Flush() != nil {
+				return
+			}
+		}
+	}
+
+}
+
+// readLine reads a line of at most maxLineLen bytes.
+func readLine(r *bufio.Reader) (string, error) {
+
+	var buf []byte
+	for {
+		chunk, err := r.ReadSlice('\n')
+		if len(buf)+len(chunk) > maxLineLen {
+			return "", errLineTooLong
+		}
+		buf = append(buf, chunk...)
+		if err != bufio.ErrBufferFull {
+			return string(buf), err
+		}
+	}
+
+}
+
+// validKey reports whether key is a valid memcached key: at most 250
+// bytes without control characters.
+func validKey(key string) bool {
+
+	if len(key) == 0 || len(key) > maxKeyLen {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] <= ' ' || key[i] == 0x7f {
+			return false
+		}
+	}
+	return true
+
+}
+
+// handle executes one command. It returns false to close the connection.
+func (s *Server) handle(fields []string, r *bufio.Reader, w *bufio.Writer) bool {
+
+	switch cmd, args := fields[0], fields[1:]; cmd {
+	case "get", "gets":
+		s.get(args, cmd == "gets", w)
+	case "set", "add", "replace", "cas":
+		return s.store(cmd, args, r, w)
+	case "delete":
+		if len(args) < 1 {
+			w.WriteString("ERROR\r\n")
+		} else if !validKey(args[0]) {
+			w.WriteString("CLIENT_ERROR bad command line format\r\n")
+		} else if s.cache.Delete(args[0]) {
+			reply(w, args, "DELETED")
+		} else {
+			reply(w, args, "NOT_FOUND")
+		}
+	case "touch":
+		s.touch(args, w)
+	case "stats":
+		s.stats(w)
+	case "version":
+		w.WriteString("VERSION nexcache\r\n")
+	case "quit":
+		return false
+	default:
+		w.WriteString("ERROR\r\n")
+	}
+	return true
+
+}
+
+func (s *Server) get(keys []string, withCAS bool, w *bufio.Writer) {
+
+	for _, key := range keys {
+		if !validKey(key) {
+			w.WriteString("CLIENT_ERROR bad command line format\r\n")
+			return
+		}
+	}
+	for _, key := range keys {
+		val, found := s.cache.Get(key)
+		it, ok := val.(*item)
+		if !found || !ok {
+			continue
+		}
+		if withCAS {
+			fmt.Fprintf(w, "VALUE %s %d %d %d\r\n", key, it.flags, len(it.data), it.cas)
+		} else {
+			fmt.Fprintf(w, "VALUE %s %d %d\r\n", key, it.flags, len(it.data))
+		}
+		w.Write(it.data)
+		w.WriteString("\r\n")
+	}
+	w.WriteString("END\r\n")
+
+}
+
+// store handles set, add, replace and cas:
+// <cmd> <key> <flags> <exptime> <bytes> [<cas unique>] [noreply]
+func (s *Server) store(cmd string, args []string, r *bufio.Reader, w *bufio.Writer) bool {
+
+	need := 4
+	if cmd == "cas" {
+		need = 5
+	}
+	if len(args) < need {
+		w.WriteString("ERROR\r\n")
+		return true
+	}
+	flags, err1 := strconv.ParseUint(args[1], 10, 32)
+	exptime, err2 := strconv.ParseInt(args[2], 10, 64)
+	size, err3 := strconv.Atoi(args[3])
+	var want uint64
+	var err4 error
+	if cmd == "cas" {
+		want, err4 = strconv.ParseUint(args[4], 10, 64)
+	}
+	if err := errors.Join(err1, err2, err3, err4); err != nil || size < 0 {
+		w.WriteString("CLIENT_ERROR bad command line format\r\n")
+		return true
+	}
+	if size > maxItemSize || !validKey(args[0]) {
+		if size > maxItemSize {
+			w.WriteString("SERVER_ERROR object too large for cache\r\n")
+		} else {
+			w.WriteString("CLIENT_ERROR bad command line format\r\n")
+		}
+		// Swallow the data block so the connection stays in sync.
+		_, err := io.CopyN(io.Discard, r, int64(size)+2)
+		return err == nil
+	}
+
+	data := make([]byte, size+2)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return false
+	}
+	if string(data[size:]) != "\r\n" {
+		w.WriteString("CLIENT_ERROR bad data chunk\r\n")
+		return true
+	}
+
+	key := args[0]
+	it := &item{flags: uint32(flags), data: data[:size], cas: s.cas.Add(1)}
+	ttl, live := lifetime(exptime)
+	if !live {
+		// Stored already expired, like memcached does for past times.
+		ttl = time.Nanosecond
+	}
+	result := "STORED"
+	s.cache.UpdateWithTTL(key, ttl, func(old interface{}, exists bool) (interface{}, bool) {
+		cur, _ := old.(*item)
+		switch {
+		case cmd == "add" && exists:
+			result = "NOT_STORED"
+		case cmd == "replace" && !exists:
+			result = "NOT_STORED"
+		case cmd == "cas" && !exists:
+			result = "NOT_FOUND"
+		case cmd == "cas" && (cur == nil || cur.cas != want):
+			result = "EXISTS"
+		}
+		return it, result == "STORED"
+	})
+	reply(w, args, result)
+	return true
+
+}
+
+func (s *Server) touch(args []string, w *bufio.Writer) {
+
+	if len(args) < 2 {
+		w.WriteString("ERROR\r\n")
+		return
+	}
+	exptime, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		w.WriteString("CLIENT_ERROR bad command line format\r\n")
+		return
+	}
+	if !validKey(args[0]) {
+		w.WriteString("CLIENT_ERROR bad command line format\r\n")
+		return
+	}
+
+	var found bool
+	switch ttl, live := lifetime(exptime); {
+	case !live:
+		found = s.cache.Delete(args[0])
+	case ttl == lrucache.NoExpiration:
+		found = s.cache.Persist(args[0])
+	default:
+		found = s.cache.Expire(args[0], ttl)
+	}
+	if found {
+		reply(w, args, "TOUCHED")
+	} else {
+		reply(w, args, "NOT_FOUND")
+	}
+
+}
+
+// lifetime converts a memcached expiration time: 0 never expires, values
+// up to 30 days are relative seconds, larger values are Unix timestamps.
+// It returns false for negative values and times that have passed.
+func lifetime(exptime int64) (time.Duration, bool) {
+
+	switch {
+	case exptime == 0:
+		return lrucache.NoExpiration, true
+	case exptime < 0:
+		return 0, false
+	case exptime <= relativeLimit:
+		return time.Duration(exptime) * time.Second, true
+	}
+	ttl := time.Until(time.Unix(exptime, 0))
+	return ttl, ttl > 0
+
+}
+
+func (s *Server) stats(w *bufio.Writer) {
+
+	st := s.cache.Stats()
+	stat := func(name string, value interface{}) {
+		fmt.Fprintf(w, "STAT %s %v\r\n", name, value)
+	}
+	stat("uptime", int64(time.Since(s.started).Seconds()))
+	stat("time", time.Now().Unix())
+	stat("version", "nexcache")
+	stat("curr_items", s.cache.Len())
+	stat("get_hits", st.Hits)
+	stat("get_misses", st.Misses)
+	stat("evictions", st.Evictions)
+	w.WriteString("END\r\n")
+
+}
+
+// reply writes msg unless the command ended with "noreply".
+func reply(w *bufio.Writer, args []string, msg string) {
+	if len(args) > 0 && args[len(args)-1] == "noreply" {
+		return
+	}
+	w.WriteString(msg + "\r\n")
+}
@@ -0,0 +1,210 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package memcached
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/georghagn/nexcache/lrucache"
+	"github.com/georghagn/nexcache/lrucache/clocktest"
+)
+
+// client is one connection to a server for the cache on a loopback port.
+type client struct {
+	t    *testing.T
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+func connect(t *testing.T, cache *lrucache.LRUCache) *client {
+
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := NewServer(cache)
+	go server.Serve(ln)
+	t.Cleanup(func() { server.Close() })
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.SetDeadline(time.Now().Add(10 * time.Second))
+	t.Cleanup(func() { conn.Close() })
+	return &client{t: t, conn: conn, r: bufio.NewReader(conn)}
+
+}
+
+func (c *client) send(lines ...string) {
+	c.t.Helper()
+	if _, err := c.conn.Write([]byte(strings.Join(lines, "\r\n") + "\r\n")); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+// expect reads one response line per want and compares them.
+func (c *client) expect(want ...string) {
+
+	c.t.Helper()
+	for _, w := range want {
+		line, err := c.r.ReadString('\n')
+		if err != nil {
+			c.t.Fatalf("reading %q: %v", w, err)
+		}
+		if got := strings.TrimRight(line, "\r\n"); got != w {
+			c.t.Fatalf("got %q, want %q", got, w)
+		}
+	}
+
+}
+
+func newCache(t *testing.T, opts ...lrucache.Option) *lrucache.LRUCache {
+	cache := lrucache.New(100, time.Hour, time.Hour, opts...)
+	t.Cleanup(func() { cache.Close() })
+	return cache
+}
+
+func TestSetGetDelete(t *testing.T) {
+
+	c := connect(t, newCache(t))
+
+	c.send("set k 5 0 5", "hello")
+	c.expect("STORED")
+	c.send("get k missing")
+	c.expect("VALUE k 5 5", "hello", "END")
+	c.send("delete k")
+	c.expect("DELETED")
+	c.send("delete k")
+	c.expect("NOT_FOUND")
+	c.send("get k")
+	c.expect("END")
+
+}
+
+func TestAddReplace(t *testing.T) {
+
+	c := connect(t, newCache(t))
+
+	c.send("replace k 0 0 1", "a")
+	c.expect("NOT_STORED")
+	c.send("add k 0 0 1", "a")
+	c.expect("STORED")
+	c.send("add k 0 0 1", "b")
+	c.expect("NOT_STORED")
+	c.send("replace k 0 0 1", "c")
+	c.expect("STORED")
+	c.send("get k")
+	c.expect("VALUE k 0 1", "c", "END")
+
+}
+
+func TestCAS(t *testing.T) {
+
+	c := connect(t, newCache(t))
+
+	c.send("cas k 0 0 1 1", "a")
+	c.expect("NOT_FOUND")
+	c.send("set k 0 0 1", "a")
+	c.expect("STORED")
+	c.send("gets k")
+	line, _ := c.r.ReadString('\n')
+	fields := strings.Fields(line)
+	c.expect("a", "END")
+	unique := fields[len(fields)-1]
+
+	c.send("cas k 0 0 1 "+unique, "b")
+	c.expect("STORED")
+	c.send("cas k 0 0 1 "+unique, "c")
+	c.expect("EXISTS")
+	c.send("get k")
+	c.expect("VALUE k 0 1", "b", "END")
+
+}
+
+// Expiration times are applied by the cache, on its clock.
+func TestExpirationAndTouch(t *testing.T) {
+
+	clock := clocktest.New(time.Unix(1700000000, 0))
+	c := connect(t, newCache(t, lrucache.WithClock(clock)))
+
+	c.send("set short 0 10 1", "a")
+	c.expect("STORED")
+	c.send("set touched 0 10 1", "b")
+	c.expect("STORED")
+	c.send("touch touched 100")
+	c.expect("TOUCHED")
+	c.send("touch missing 100")
+	c.expect("NOT_FOUND")
+
+	clock.Advance(20 * time.Second)
+	c.send("get short touched")
+	c.expect("VALUE touched 0 1", "b", "END")
+
+	c.send("set gone 0 -1 1", "x")
+	c.expect("STORED")
+	clock.Advance(time.Second)
+	c.send("get gone")
+	c.expect("END")
+
+}
+
+func TestNoreply(t *testing.T) {
+
+	c := connect(t, newCache(t))
+
+	c.send("set k 0 0 1 noreply", "a", "delete missing noreply", "get k")
+	c.expect("VALUE k 0 1", "a", "END")
+
+}
+
+// Bad keys and oversized values are refused without losing the stream.
+func TestRejectsAndStaysInSync(t *testing.T) {
+
+	c := connect(t, newCache(t))
+	long := strings.Repeat("k", maxKeyLen+1)
+
+	c.send("set "+long+" 0 0 1", "a")
+	c.expect("CLIENT_ERROR bad command line format")
+	c.send("set big 0 0 1048577", strings.Repeat("x", 1048577))
+	c.expect("SERVER_ERROR object too large for cache")
+	c.send("set k 0 0 3", "toolong")
+	c.expect("CLIENT_ERROR bad data chunk")
+	c.expect("ERROR") // the rest of the block is read as a command, as in memcached
+	c.send("bogus")
+	c.expect("ERROR")
+	c.send("version")
+	c.expect("VERSION nexcache")
+
+}
+
+func TestLineTooLongClosesConnection(t *testing.T) {
+
+	c := connect(t, newCache(t))
+
+	// The server may close before the whole line is written.
+	go c.conn.Write([]byte("get " + strings.Repeat("k ", maxLineLen) + "\r\n"))
+	c.expect("CLIENT_ERROR line too long")
+	if _, err := c.r.ReadString('\n'); err == nil {
+		t.Error("connection stayed open")
+	}
+
+}
+
+// Values written through other APIs of the cache are not visible.
+func TestForeignValuesAreMisses(t *testing.T) {
+
+	cache := newCache(t)
+	cache.Set("k", "plain string")
+	c := connect(t, cache)
+
+	c.send("get k")
+	c.expect("END")
+
+}
@@ -0,0 +1,78 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+// ---------------------- Metadata ----------------------
+
+// SetWithMeta stores the value like Set and attaches meta to the entry,
+// replacing its previous metadata; a nil or empty meta removes it. A plain
+// Set on the key keeps the metadata. Unlike tags, metadata is written by
+// SaveToFile and the WAL, so it survives a restart. The map is copied.
+func (c *LRUCache) SetWithMeta(key string, value interface{}, meta map[string]string) {
+
+	c.acquireWrite()
+	defer c.releaseWrite()
+
+	c.lock()
+	defer c.unlock()
+
+	if c.closedLocked() {
+		c.logAdmission(key, AdmissionRejectedClosed, nil)
+		return
+	}
+
+	c.meta = make(map[string]string, len(meta))
+	for k, v := range meta {
+		c.meta[k] = v
+	}
+	c.putLocked(key, value, c.deadline(c.clock.Now(), c.ttl))
+	c.meta = nil
+
+}
+
+// GetMeta returns a copy of the metadata of key, without updating the LRU
+// position. The bool is false if the key is missing or expired; a live
+// entry without metadata returns nil and true.
+func (c *LRUCache) GetMeta(key string) (map[string]string, bool) {
+
+	c.lock()
+	defer c.unlock()
+
+	if c.closedLocked() {
+		return nil, false
+	}
+
+	element, found := c.cache[key]
+	if !found {
+		return nil, false
+	}
+	entry := element.Value.(*CacheEntry)
+	if entry.expired(c.clock.Now()) {
+		return nil, false
+	}
+	if entry.Meta == nil {
+		return nil, true
+	}
+	meta := make(map[string]string, len(entry.Meta))
+	for k, v := range entry.Meta {
+		meta[k] = v
+	}
+	return meta, true
+
+}
+
+// applyMeta gives entry the metadata passed to SetWithMeta, if the write
+// comes from there. Must be called with c.mu held.
+func (c *LRUCache) applyMeta(entry *CacheEntry) {
+
+	if c.meta == nil {
+		return
+	}
+	if len(c.meta) == 0 {
+		entry.Meta = nil
+	} else {
+		entry.Meta = c.meta
+	}
+
+}
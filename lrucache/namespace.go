@@ -0,0 +1,176 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// ---------------------- Namespaces ----------------------
+
+// Namespace is a view of the cache whose keys are prefixed with its name
+// and ':'. All namespaces share the parent's capacity and eviction order,
+// but keep their own statistics and default TTL.
+type Namespace struct {
+	cache  *LRUCache
+	prefix string
+	ttl    atomic.Int64 // default TTL in nanoseconds
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+// Namespace returns the view for name, creating it on first use with the
+// cache's default TTL. Repeated calls return the same view.
+func (c *LRUCache) Namespace(name string) *Namespace {
+
+	c.lock()
+	defer c.unlock()
+
+	if ns, found := c.namespaces[name]; found {
+		return ns
+	}
+	if c.namespaces == nil {
+		c.namespaces = make(map[string]*Namespace)
+	}
+	ns := &Namespace{cache: c, prefix: name + ":"}
+	ns.ttl.Store(int64(c.ttl))
+	c.namespaces[name] = ns
+	return ns
+
+}
+
+// Name returns the namespace name.
+func (n *Namespace) Name() string {
+	return strings.TrimSuffix(n.prefix, ":")
+}
+
+// SetDefaultTTL changes the TTL used by Set in this namespace.
+func (n *Namespace) SetDefaultTTL(ttl time.Duration) {
+	n.ttl.Store(int64(ttl))
+}
+
+// Get returns the value stored under key in this namespace.
+func (n *Namespace) Get(key string) (interface{}, bool) {
+	val, found := n.cache.Get(n.prefix + key)
+	n.count(found)
+	return val, found
+}
+
+// Set stores value under key with the namespace's default TTL.
+func (n *Namespace) Set(key string, value interface{}) {
+
+	ttl := time.Duration(n.ttl.Load())
+	c := n.cache
+
+	c.acquireWrite()
+	defer c.releaseWrite()
+
+	c.lock()
+	defer c.unlock()
+
+	if c.closedLocked() {
+		c.logAdmission(n.prefix+key, AdmissionRejectedClosed, nil)
+		return
+	}
+	c.putLocked(n.prefix+key, value, c.deadline(c.clock.Now(), ttl)).ttl = ttl
+
+}
+
+// GetOrLoad is like LRUCache.GetOrLoad within this namespace. Loaded
+// values get the namespace's default TTL.
+func (n *Namespace) GetOrLoad(key string, loader func() (interface{}, error)) (interface{}, error) {
+
+	n.cache.checkLoader(loader == nil)
+
+	val, found, err := n.cache.lookup(n.prefix + key)
+	n.count(found)
+	if found || err != nil {
+		return val, err
+	}
+	ttl := time.Duration(n.ttl.Load())
+	return n.cache.loadWithTTL(context.Background(), n.prefix+key, ignoreContext(loader), ttl, true)
+
+}
+
+// Delete removes key from this namespace.
+func (n *Namespace) Delete(key string) bool {
+	return n.cache.Delete(n.prefix + key)
+}
+
+// Stats returns the hits and misses of lookups through this namespace.
+func (n *Namespace) Stats() Stats {
+	return Stats{
+		Hits:   n.hits.Load(),
+		Misses: n.misses.Load(),
+	}
+}
+
+// FlushNamespace removes all entries of this namespace from the cache and
+// a backing store and returns how many were removed. Other namespaces are
+// not affected.
+func (n *Namespace) FlushNamespace() int {
+
+	c := n.cache
+	c.lock()
+	defer c.unlock()
+
+	if c.closedLocked() {
+		return 0
+	}
+
+	removed := 0
+	for key, element := range c.cache {
+		if strings.HasPrefix(key, n.prefix) {
+			c.removeElement(element)
+			c.logDelete(key)
+			c.storeDelete(key)
+			removed++
+		}
+	}
+	return removed
+
+}
+
+// Advance lets d pass for the entries currently in this namespace, for
+// tests and simulations: their expiry times move d closer, so they expire
+// as if the clock had been advanced, while the rest of the cache is not
+// affected. Entries written afterwards count from the real clock again.
+// Max-idle times are not changed. It returns the number of entries moved.
+func (n *Namespace) Advance(d time.Duration) int {
+
+	c := n.cache
+	c.lock()
+	defer c.unlock()
+
+	if c.closedLocked() {
+		return 0
+	}
+
+	now := c.clock.Now()
+	moved := 0
+	for key, element := range c.cache {
+		entry := element.Value.(*CacheEntry)
+		if !strings.HasPrefix(key, n.prefix) || entry.ExpiresAt.IsZero() || entry.expired(now) {
+			continue
+		}
+		c.preserveForForks(key)
+		entry.ExpiresAt = entry.ExpiresAt.Add(-d)
+		c.schedule(entry)
+		c.logSet(entry)
+		moved++
+	}
+	return moved
+
+}
+
+func (n *Namespace) count(hit bool) {
+	if hit {
+		n.hits.Add(1)
+	} else {
+		n.misses.Add(1)
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/georghagn/nexcache/lrucache"
+)
+
+func TestNamespacePrefixesKeys(t *testing.T) {
+
+	c := lrucache.New(10, time.Hour, time.Hour)
+	defer c.Close()
+	users := c.Namespace("users")
+	if c.Namespace("users") != users {
+		t.Fatal("Namespace returned a new view for the same name")
+	}
+
+	users.Set("1", "alice")
+	if val, _ := c.Get("users:1"); val != "alice" {
+		t.Errorf("parent sees users:1 = %v", val)
+	}
+	if _, found := c.Namespace("orders").Get("1"); found {
+		t.Error("key leaked into another namespace")
+	}
+	users.Get("1")
+	users.Get("2")
+	if s := users.Stats(); s.Hits != 1 || s.Misses != 1 {
+		t.Errorf("namespace stats = %+v, want one hit and one miss", s)
+	}
+
+}
+
+// Set and loads in a namespace use its own default TTL.
+func TestNamespaceTTL(t *testing.T) {
+
+	clock := newClock()
+	c := lrucache.New(10, time.Hour, time.Hour, lrucache.WithClock(clock))
+	defer c.Close()
+	ns := c.Namespace("short")
+	ns.SetDefaultTTL(time.Minute)
+
+	ns.Set("set", 1)
+	ns.GetOrLoad("loaded", func() (interface{}, error) { return 2, nil })
+	c.Set("parent", 3)
+	for _, key := range []string{"short:set", "short:loaded"} {
+		if ttl, _ := c.TTL(key); ttl != time.Minute {
+			t.Errorf("TTL of %s = %v, want the namespace TTL 1m", key, ttl)
+		}
+	}
+
+	clock.Advance(2 * time.Minute)
+	if c.Contains("short:set") || c.Contains("short:loaded") || !c.Contains("parent") {
+		t.Errorf("keys after 2m = %v, want only parent", c.Keys())
+	}
+
+}
+
+func TestFlushNamespace(t *testing.T) {
+
+	store := newMemStore()
+	c := lrucache.New(10, time.Hour, time.Hour, lrucache.WithWriteThrough(store))
+	defer c.Close()
+	a, b := c.Namespace("a"), c.Namespace("b")
+	a.Set("1", 1)
+	a.Set("2", 2)
+	b.Set("1", 1)
+
+	if n := a.FlushNamespace(); n != 2 {
+		t.Errorf("FlushNamespace = %d, want 2", n)
+	}
+	if c.Contains("a:1") || !c.Contains("b:1") {
+		t.Errorf("keys after flush = %v, want [b:1]", c.Keys())
+	}
+	if _, found := store.get("a:1"); found {
+		t.Error("flushed entry is still in the backing store")
+	}
+
+}
+
+func TestNamespaceAdvance(t *testing.T) {
+
+	c := lrucache.New(10, time.Minute, time.Hour)
+	defer c.Close()
+	ns := c.Namespace("ns")
+	ns.Set("k", 1)
+	c.Set("other", 1)
+
+	if n := ns.Advance(2 * time.Minute); n != 1 {
+		t.Errorf("Advance moved %d entries, want 1", n)
+	}
+	if _, found := ns.Get("k"); found {
+		t.Error("entry survived Advance past its TTL")
+	}
+	if !c.Contains("other") {
+		t.Error("Advance affected another namespace")
+	}
+
+}
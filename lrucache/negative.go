@@ -0,0 +1,103 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"errors"
+	"time"
+)
+
+// ---------------------- Negative caching ----------------------
+
+// negativeEntry is a remembered failed load.
+type negativeEntry struct {
+	err       error
+	expiresAt time.Time
+}
+
+// WithNegativeCaching remembers for ttl that a load of a key failed
+// because the validator (see WithResultValidator) rejected the result or
+// the loader returned ErrNotFound. Until ttl has passed, further loads of
+// the key return the same error without calling the loader, so a missing
+// or broken record does not hit the backend on every request. A later
+// successful load, Delete or Clear forgets the failure. At most as many
+// failures as the cache capacity are remembered.
+func WithNegativeCaching(ttl time.Duration) Option {
+	return func(c *LRUCache) {
+		c.negativeTTL = ttl
+	}
+}
+
+// negativeErr returns the remembered failure of key, if any.
+func (c *LRUCache) negativeErr(key string) error {
+
+	if c.negativeTTL <= 0 {
+		return nil
+	}
+
+	c.negMu.Lock()
+	defer c.negMu.Unlock()
+
+	neg, found := c.negative[key]
+	if !found {
+		return nil
+	}
+	if c.clock.Now().After(neg.expiresAt) {
+		delete(c.negative, key)
+		return nil
+	}
+	return neg.err
+
+}
+
+// rememberFailure records a failed load of key. Only validator rejections
+// (rejected set) and ErrNotFound are remembered.
+func (c *LRUCache) rememberFailure(key string, err error, rejected bool) {
+
+	if c.negativeTTL <= 0 || (!rejected && !errors.Is(err, ErrNotFound)) {
+		return
+	}
+
+	c.negMu.Lock()
+	defer c.negMu.Unlock()
+
+	now := c.clock.Now()
+	if c.negative == nil {
+		c.negative = make(map[string]negativeEntry)
+	}
+	if _, found := c.negative[key]; !found && len(c.negative) >= c.capacity {
+		for k, neg := range c.negative {
+			if now.After(neg.expiresAt) {
+				delete(c.negative, k)
+			}
+		}
+		if len(c.negative) >= c.capacity {
+			return
+		}
+	}
+	c.negative[key] = negativeEntry{err: err, expiresAt: now.Add(c.negativeTTL)}
+
+}
+
+// forgetFailure drops the remembered failure of key.
+func (c *LRUCache) forgetFailure(key string) {
+
+	if c.negativeTTL <= 0 {
+		return
+	}
+
+	c.negMu.Lock()
+	delete(c.negative, key)
+	c.negMu.Unlock()
+
+}
+
+// forgetFailures drops all remembered failures.
+func (c *LRUCache) forgetFailures() {
+
+	c.negMu.Lock()
+	c.negative = nil
+	c.negMu.Unlock()
+
+}
@@ -0,0 +1,71 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/georghagn/nexcache/lrucache"
+)
+
+func TestNegativeCachingRemembersNotFound(t *testing.T) {
+
+	clock := newClock()
+	c := lrucache.New(10, time.Hour, time.Hour, lrucache.WithClock(clock), lrucache.WithNegativeCaching(time.Minute))
+	defer c.Close()
+
+	calls := 0
+	missing := func() (interface{}, error) { calls++; return nil, lrucache.ErrNotFound }
+	c.GetOrLoad("k", missing)
+	if _, err := c.GetOrLoad("k", missing); !errors.Is(err, lrucache.ErrNotFound) || calls != 1 {
+		t.Fatalf("second load = %v after %d loader calls, want the remembered ErrNotFound", err, calls)
+	}
+
+	clock.Advance(2 * time.Minute)
+	c.GetOrLoad("k", missing)
+	if calls != 2 {
+		t.Errorf("loader called %d times after the negative TTL, want 2", calls)
+	}
+
+}
+
+func TestNegativeCachingRemembersRejections(t *testing.T) {
+
+	rejected := errors.New("invalid")
+	c := lrucache.New(10, time.Hour, time.Hour, lrucache.WithNegativeCaching(time.Minute),
+		lrucache.WithResultValidator(func(string, interface{}) error { return rejected }))
+	defer c.Close()
+
+	calls := 0
+	load := func() (interface{}, error) { calls++; return "bad", nil }
+	c.GetOrLoad("k", load)
+	if _, err := c.GetOrLoad("k", load); err != rejected || calls != 1 {
+		t.Errorf("second load = %v after %d calls, want the remembered rejection", err, calls)
+	}
+
+	c.Delete("k")
+	c.GetOrLoad("k", load)
+	if calls != 2 {
+		t.Error("Delete did not forget the failure")
+	}
+
+}
+
+// Other loader errors are transient and not remembered.
+func TestNegativeCachingSkipsOtherErrors(t *testing.T) {
+
+	c := lrucache.New(10, time.Hour, time.Hour, lrucache.WithNegativeCaching(time.Minute))
+	defer c.Close()
+
+	calls := 0
+	load := func() (interface{}, error) { calls++; return nil, errors.New("timeout") }
+	c.GetOrLoad("k", load)
+	c.GetOrLoad("k", load)
+	if calls != 2 {
+		t.Errorf("loader called %d times, want 2", calls)
+	}
+
+}
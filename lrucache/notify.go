@@ -0,0 +1,121 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"github.com/georghagn/nexcache/lrucache/internal/list"
+)
+
+// ---------------------- Callbacks ----------------------
+
+// OnExpire registers fn to be called whenever an entry dies of old age,
+// either lazily on access or in the background cleanup. It is not called
+// for capacity evictions or Delete. Callbacks run after the cache lock has
+// been released, so fn may use the cache. A later call replaces fn.
+func (c *LRUCache) OnExpire(fn func(key string, value interface{})) {
+
+	c.lock()
+	defer c.unlock()
+
+	c.onExpire = fn
+
+}
+
+// EvictReason tells an OnEvict callback why an entry was removed.
+type EvictReason string
+
+const (
+	// EvictCapacity: the entry made room for a new one.
+	EvictCapacity EvictReason = "capacity"
+
+	// EvictExpired: the entry's TTL ran out.
+	EvictExpired EvictReason = "expired"
+
+	// EvictCleared: the entry was removed by Clear or Purge.
+	EvictCleared EvictReason = "cleared"
+
+	// EvictMemory: the entry was evicted under memory pressure, see
+	// EnableMemoryPressure.
+	EvictMemory EvictReason = "memory"
+)
+
+// OnEvict registers fn to be called whenever the cache itself removes an
+// entry, or Clear empties it, with the reason. It is not called for Delete
+// or invalidation.
+// Like OnExpire, it runs after the cache lock has been released. A later
+// call replaces fn.
+func (c *LRUCache) OnEvict(fn func(key string, value interface{}, reason EvictReason)) {
+
+	if fn == nil {
+		c.OnEvictEntry(nil)
+		return
+	}
+	c.OnEvictEntry(func(entry CacheEntry, reason EvictReason) {
+		fn(entry.Key, entry.Value, reason)
+	})
+
+}
+
+// OnEvictEntry works like OnEvict, but fn also receives the expiry time
+// and metadata of the entry, e.g. to move it to a second level with its
+// remaining lifetime. It replaces a callback set with OnEvict and vice
+// versa.
+func (c *LRUCache) OnEvictEntry(fn func(entry CacheEntry, reason EvictReason)) {
+
+	c.lock()
+	defer c.unlock()
+
+	c.onEvict = fn
+
+}
+
+// evicted publishes the removal of entry and queues the OnEvict callback.
+// Must be called with c.mu held.
+func (c *LRUCache) evicted(entry *CacheEntry, reason EvictReason) {
+	if reason == EvictCapacity {
+		c.recordEviction(entry)
+	}
+	if reason == EvictExpired {
+		c.publish(EventExpire, entry.Key, entry)
+	} else {
+		c.publish(EventEvict, entry.Key, entry)
+	}
+	if fn := c.onEvict; fn != nil {
+		evicted := CacheEntry{Key: entry.Key, Value: hydrate(entry), ExpiresAt: entry.ExpiresAt, Meta: entry.Meta}
+		c.notify(func() { fn(evicted, reason) })
+	}
+}
+
+// expireElement removes an expired entry and queues the callbacks.
+// Must be called with c.mu held.
+func (c *LRUCache) expireElement(element *list.Element) {
+
+	entry := element.Value.(*CacheEntry)
+	c.removeElement(element)
+	if fn := c.onExpire; fn != nil {
+		key, value := entry.Key, hydrate(entry)
+		c.notify(func() { fn(key, value) })
+	}
+	c.evicted(entry, EvictExpired)
+
+}
+
+// notify queues a callback to run once the lock is released.
+// Must be called with c.mu held.
+func (c *LRUCache) notify(fn func()) {
+	c.pending = append(c.pending, fn)
+}
+
+// unlock releases c.mu and then runs the callbacks queued while it was held.
+func (c *LRUCache) unlock() {
+
+	pending := c.pending
+	c.pending = nil
+	c.mu.Unlock()
+
+	for _, fn := range pending {
+		fn()
+	}
+
+}
@@ -0,0 +1,38 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+// Option configures optional behavior of a cache created with New.
+type Option func(*LRUCache)
+
+// EvictionPolicy selects which entry is removed when the cache is full.
+type EvictionPolicy int
+
+const (
+	// PolicyLRU evicts the least recently used entry (default).
+	PolicyLRU EvictionPolicy = iota
+
+	// PolicySIEVE uses the SIEVE algorithm: hits only set a visited bit
+	// instead of moving the entry, and a hand sweeping from the tail evicts
+	// the first unvisited entry. Cheaper on hits and scan-resistant.
+	PolicySIEVE
+)
+
+// WithEvictionPolicy selects the eviction policy.
+func WithEvictionPolicy(policy EvictionPolicy) Option {
+	return func(c *LRUCache) {
+		c.policy = policy
+	}
+}
+
+// WithResultValidator installs a check that runs on every loader result
+// before it is cached. If the validator returns an error, the value is not
+// stored and GetOrLoad returns that error (GetOrLoadWithFallback returns the
+// fallback). Use it to keep empty or malformed results from flaky backends
+// out of the cache. WithNegativeCaching remembers rejections for a while.
+func WithResultValidator(validate func(key string, value interface{}) error) Option {
+	return func(c *LRUCache) {
+		c.validator = validate
+	}
+}
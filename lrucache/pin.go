@@ -0,0 +1,99 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"github.com/georghagn/nexcache/lrucache/internal/list"
+)
+
+// ---------------------- Pinning ----------------------
+
+// Pin protects the entry for key from capacity eviction and reports
+// whether it is pinned. Pinned entries do not count against the capacity;
+// as a safeguard, at most capacity entries can be pinned at a time.
+// Pinning does not stop TTL expiry (see Persist).
+func (c *LRUCache) Pin(key string) bool {
+
+	c.lock()
+	defer c.unlock()
+
+	if c.closedLocked() {
+		return false
+	}
+
+	element, found := c.cache[key]
+	if !found || element.Value.(*CacheEntry).expired(c.clock.Now()) {
+		return false
+	}
+	return c.pinLocked(element.Value.(*CacheEntry))
+
+}
+
+// Unpin makes the entry for key evictable again and reports whether it
+// was pinned.
+func (c *LRUCache) Unpin(key string) bool {
+
+	c.lock()
+	defer c.unlock()
+
+	if c.closedLocked() {
+		return false
+	}
+
+	element, found := c.cache[key]
+	if !found || !element.Value.(*CacheEntry).pinned {
+		return false
+	}
+	c.unpinLocked(element.Value.(*CacheEntry))
+	return true
+
+}
+
+// SetPinned stores the value like Set and pins it. It reports false, with
+// the value stored but not pinned, if the pin limit is reached.
+func (c *LRUCache) SetPinned(key string, value interface{}) bool {
+
+	c.acquireWrite()
+	defer c.releaseWrite()
+
+	c.lock()
+	defer c.unlock()
+
+	if c.closedLocked() {
+		c.logAdmission(key, AdmissionRejectedClosed, nil)
+		return false
+	}
+	return c.pinLocked(c.putLocked(key, value, c.deadline(c.clock.Now(), c.ttl)))
+
+}
+
+func (c *LRUCache) pinLocked(entry *CacheEntry) bool {
+
+	if entry.pinned {
+		return true
+	}
+	if c.pinned >= c.capacity {
+		return false
+	}
+	entry.pinned = true
+	c.pinned++
+	return true
+
+}
+
+func (c *LRUCache) unpinLocked(entry *CacheEntry) {
+	if entry.pinned {
+		entry.pinned = false
+		c.pinned--
+	}
+}
+
+// lruVictim returns the least recently used entry that is not pinned.
+func (c *LRUCache) lruVictim() *list.Element {
+	element := c.list.Back()
+	for element != nil && element.Value.(*CacheEntry).pinned {
+		element = element.Prev()
+	}
+	return element
+}
@@ -0,0 +1,109 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"testing"
+	"time"
+)
+
+// With only pinned entries the SIEVE hand must give up after a full pass
+// instead of circling forever under the write lock.
+func TestSIEVEAllPinnedHasNoVictim(t *testing.T) {
+
+	c := New(2, time.Hour, time.Hour, WithEvictionPolicy(PolicySIEVE))
+	defer c.Close()
+
+	c.SetPinned("a", 1)
+	c.SetPinned("b", 2)
+	c.Get("a")
+
+	done := make(chan bool)
+	go func() {
+		c.lock()
+		defer c.unlock()
+		done <- c.ejectOldest(EvictCapacity)
+	}()
+	select {
+	case evicted := <-done:
+		if evicted {
+			t.Error("a pinned entry was evicted")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("sieveVictim hangs with only pinned entries")
+	}
+	if c.Len() != 2 {
+		t.Errorf("Len = %d, want 2", c.Len())
+	}
+
+}
+
+func TestSIEVEEvictsUnvisitedFirst(t *testing.T) {
+
+	c := New(3, time.Hour, time.Hour, WithEvictionPolicy(PolicySIEVE))
+	defer c.Close()
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Set("c", 3)
+	c.Get("a")
+	c.Set("d", 4)
+
+	if c.Contains("b") {
+		t.Error("b was not visited and should have been evicted")
+	}
+	for _, key := range []string{"a", "c", "d"} {
+		if !c.Contains(key) {
+			t.Errorf("%q missing", key)
+		}
+	}
+
+}
+
+func TestResizeBelowPinnedUnpins(t *testing.T) {
+
+	for _, policy := range []EvictionPolicy{PolicyLRU, PolicySIEVE} {
+		c := New(2, time.Hour, time.Hour, WithEvictionPolicy(policy))
+		c.SetPinned("a", 1)
+		c.SetPinned("b", 2)
+
+		c.Resize(1)
+		if c.pinned != 1 {
+			t.Fatalf("policy %v: %d entries pinned with capacity 1", policy, c.pinned)
+		}
+		if !isPinned(c, "b") || isPinned(c, "a") {
+			t.Errorf("policy %v: the least recently used pin should have been dropped", policy)
+		}
+
+		c.Resize(0)
+		if c.pinned != 0 {
+			t.Errorf("policy %v: %d entries pinned with capacity 0", policy, c.pinned)
+		}
+		if n := c.Len(); n != 0 {
+			t.Errorf("policy %v: Len = %d after Resize(0), want 0", policy, n)
+		}
+		done := make(chan struct{})
+		go func() {
+			c.Set("c", 3)
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("policy %v: Set hangs after Resize(0)", policy)
+		}
+		c.Close()
+	}
+
+}
+
+func isPinned(c *LRUCache, key string) bool {
+
+	c.lock()
+	defer c.unlock()
+
+	element, found := c.cache[key]
+	return found && element.Value.(*CacheEntry).pinned
+
+}
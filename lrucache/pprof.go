@@ -0,0 +1,49 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"context"
+	"runtime/debug"
+	"runtime/pprof"
+	"strings"
+)
+
+// WithName names the cache. Named caches run their loaders inside
+// pprof.Do with the labels "cache" (the name) and "key_prefix" (the key up
+// to the first ':'), so CPU profiles attribute load work to the cache.
+func WithName(name string) Option {
+	return func(c *LRUCache) {
+		c.name = name
+	}
+}
+
+// runLoader calls the loader, labelled for the profiler if the cache is named.
+// A panic of the loader is returned as *PanicError.
+func (c *LRUCache) runLoader(ctx context.Context, key string, loader LoaderFunc) (val interface{}, err error) {
+
+	defer func() {
+		if r := recover(); r != nil {
+			val, err = nil, &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+
+	if c.name == "" {
+		return loader(ctx)
+	}
+
+	labels := pprof.Labels("cache", c.name, "key_prefix", keyPrefix(key))
+	pprof.Do(ctx, labels, func(ctx context.Context) {
+		val, err = loader(ctx)
+	})
+	return val, err
+
+}
+
+func keyPrefix(key string) string {
+	if i := strings.IndexByte(key, ':'); i >= 0 {
+		return key[:i]
+	}
+	return key
+}
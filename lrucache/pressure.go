@@ -0,0 +1,99 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"errors"
+	"math"
+	"runtime"
+	"time"
+)
+
+// ---------------------- Memory pressure ----------------------
+
+// WithMemoryGauge replaces the heap size that EnableMemoryPressure watches
+// (runtime.MemStats.HeapAlloc by default) with fn, e.g. the resident set
+// size or a container memory metric. fn returns bytes and is called from
+// the supervisor goroutine without the cache lock.
+func WithMemoryGauge(fn func() uint64) Option {
+	return func(c *LRUCache) {
+		c.memGauge = fn
+	}
+}
+
+// EnableMemoryPressure starts a supervisor that reads the memory gauge
+// every interval and, while it exceeds limit bytes, evicts fraction (0 to
+// 1) of the evictable entries in eviction order. Evicted entries are
+// reported with EvictMemory to OnEvict and as EventEvict, and every run is
+// announced by an EventPressure event. The cache cannot tell which share
+// of the heap it holds, so fraction should be large enough to make a
+// difference. The supervisor runs until Close.
+func (c *LRUCache) EnableMemoryPressure(limit uint64, fraction float64, interval time.Duration) error {
+
+	if interval <= 0 || fraction <= 0 || fraction > 1 {
+		return errors.New("lrucache: memory pressure needs a positive interval and a fraction in (0, 1]")
+	}
+
+	c.lock()
+	if c.closedLocked() {
+		c.unlock()
+		return ErrClosed
+	}
+	if c.pressure {
+		c.unlock()
+		return errors.New("lrucache: memory pressure already enabled")
+	}
+	c.pressure = true
+	gauge := c.memGauge
+	c.unlock()
+
+	if gauge == nil {
+		gauge = heapAlloc
+	}
+
+	ticker := c.clock.NewTicker(interval)
+	c.spawn(func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C():
+				if gauge() > limit {
+					c.relievePressure(fraction)
+				}
+			case <-c.stopCh:
+				return
+			}
+		}
+	})
+	return nil
+
+}
+
+// relievePressure evicts fraction of the evictable entries.
+func (c *LRUCache) relievePressure(fraction float64) {
+
+	c.lock()
+	defer c.unlock()
+
+	if c.closed {
+		return
+	}
+
+	target := int(math.Ceil(float64(c.list.Len()-c.pinned) * fraction))
+	evicted := 0
+	for evicted < target && c.ejectOldest(EvictMemory) {
+		evicted++
+	}
+
+	if len(c.subscribers) > 0 {
+		c.broadcast(Event{Type: EventPressure, Value: evicted, Time: c.clock.Now()})
+	}
+
+}
+
+func heapAlloc() uint64 {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.HeapAlloc
+}
@@ -0,0 +1,83 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"github.com/georghagn/nexcache/lrucache/internal/list"
+)
+
+// ---------------------- Priorities ----------------------
+
+// SetWithPriority stores the value like Set with the given priority
+// (default 0). When the cache is full, the least recently used entry of
+// the lowest priority present is evicted first, so expensive values can
+// be kept longer than cheap ones. A plain Set keeps an entry's priority.
+// Once priorities are used, they take precedence over the eviction policy.
+func (c *LRUCache) SetWithPriority(key string, value interface{}, priority int) {
+
+	c.acquireWrite()
+	defer c.releaseWrite()
+
+	c.lock()
+	defer c.unlock()
+
+	if c.closedLocked() {
+		c.logAdmission(key, AdmissionRejectedClosed, nil)
+		return
+	}
+
+	if c.priorities == nil {
+		c.priorities = make(map[int]int)
+		if n := len(c.cache); n > 0 {
+			c.priorities[0] = n
+		}
+	}
+	entry := c.putLocked(key, value, c.deadline(c.clock.Now(), c.ttl))
+	c.countPriority(entry.prio, -1)
+	entry.prio = priority
+	c.countPriority(priority, 1)
+
+}
+
+// countPriority adjusts the number of entries with priority p.
+// Must be called with c.mu held.
+func (c *LRUCache) countPriority(p, delta int) {
+
+	if c.priorities == nil {
+		return
+	}
+	if c.priorities[p] += delta; c.priorities[p] <= 0 {
+		delete(c.priorities, p)
+	}
+
+}
+
+// priorityVictim returns the least recently used unpinned entry of the
+// lowest priority present.
+func (c *LRUCache) priorityVictim() *list.Element {
+
+	lowest, first := 0, true
+	for p := range c.priorities {
+		if first || p < lowest {
+			lowest, first = p, false
+		}
+	}
+
+	var victim *list.Element
+	for element := c.list.Back(); element != nil; element = element.Prev() {
+		entry := element.Value.(*CacheEntry)
+		if entry.pinned {
+			continue
+		}
+		if entry.prio == lowest {
+			return element
+		}
+		if victim == nil || entry.prio < victim.Value.(*CacheEntry).prio {
+			victim = element
+		}
+	}
+	// Only pinned entries have the lowest priority.
+	return victim
+
+}
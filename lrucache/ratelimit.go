@@ -0,0 +1,171 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+)
+
+// ---------------------- Loader rate limit ----------------------
+
+// ErrLoaderThrottled is returned by the GetOrLoad family when the loader
+// rate limit is exhausted and the throttle mode does not wait.
+var ErrLoaderThrottled = errors.New("lrucache: loader rate limit exceeded")
+
+// ThrottleMode selects what a load does when the loader rate limit is
+// exhausted, see WithLoaderRateLimit.
+type ThrottleMode int
+
+const (
+	// ThrottleWait waits until the loader may run (default). The wait ends
+	// early with the context error when the context of the load is done.
+	ThrottleWait ThrottleMode = iota
+
+	// ThrottleReject fails the load with ErrLoaderThrottled.
+	ThrottleReject
+
+	// ThrottleServeStale returns the expired value of the key if it is
+	// still cached, and ErrLoaderThrottled otherwise. In this mode lookups
+	// leave expired entries to the cleanup instead of removing them, so
+	// they remain available as stale values until the next cleanup run.
+	ThrottleServeStale
+)
+
+// WithLoaderRateLimit caps the rate of loader calls of the GetOrLoad
+// family across all keys at rps per second, with bursts of up to burst
+// calls, e.g. to protect a backend during a cold start. What happens to
+// loads over the limit is selected by WithThrottleMode. A GetOrLoadMulti
+// call counts as one loader call.
+func WithLoaderRateLimit(rps float64, burst int) Option {
+	return func(c *LRUCache) {
+		c.limiter = newTokenBucket(rps, burst)
+	}
+}
+
+// WithThrottleMode selects how loads over the loader rate limit are
+// handled (ThrottleWait by default).
+func WithThrottleMode(mode ThrottleMode) Option {
+	return func(c *LRUCache) {
+		c.throttleMode = mode
+	}
+}
+
+// servesStale reports whether lookups have to keep expired entries.
+func (c *LRUCache) servesStale() bool {
+	return c.limiter != nil && c.throttleMode == ThrottleServeStale
+}
+
+// throttle takes a token for a loader call or reports why it cannot.
+func (c *LRUCache) throttle(ctx context.Context) error {
+
+	if c.limiter == nil {
+		return nil
+	}
+	if c.throttleMode != ThrottleWait {
+		if !c.limiter.tryTake(c.clock.Now()) {
+			return ErrLoaderThrottled
+		}
+		return nil
+	}
+
+	wait := c.limiter.take(c.clock.Now())
+	if wait <= 0 {
+		return nil
+	}
+	if err := c.wait(ctx, wait); err != nil {
+		c.limiter.giveBack()
+		return err
+	}
+	return nil
+
+}
+
+// staleValue returns the cached value of key even if it has expired.
+func (c *LRUCache) staleValue(key string) (interface{}, bool) {
+
+	c.lock()
+	defer c.unlock()
+
+	if c.closed {
+		return nil, false
+	}
+	element, found := c.cache[key]
+	if !found {
+		return nil, false
+	}
+	return hydrate(element.Value.(*CacheEntry)), true
+
+}
+
+// tokenBucket is a token bucket refilled continuously at rate tokens per
+// second up to burst.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64 // negative while callers wait for reserved tokens
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst)}
+}
+
+// refill adds the tokens accrued since the last call.
+// Must be called with b.mu held.
+func (b *tokenBucket) refill(now time.Time) {
+
+	if !b.last.IsZero() && now.After(b.last) {
+		b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	}
+	b.last = now
+
+}
+
+// take reserves a token and returns how long to wait until it is due.
+func (b *tokenBucket) take(now time.Time) time.Duration {
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill(now)
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	if b.rate <= 0 {
+		return math.MaxInt64
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+
+}
+
+// tryTake takes a token if one is available.
+func (b *tokenBucket) tryTake(now time.Time) bool {
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill(now)
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+
+}
+
+// giveBack returns a token reserved by take that was not used.
+func (b *tokenBucket) giveBack() {
+	b.mu.Lock()
+	b.tokens = min(b.burst, b.tokens+1)
+	b.mu.Unlock()
+}
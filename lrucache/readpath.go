@@ -0,0 +1,109 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"sync/atomic"
+
+	"github.com/georghagn/nexcache/lrucache/internal/list"
+)
+
+// ---------------------- Shared read path ----------------------
+
+// promotionQueueSize is the number of hits that can be recorded under the
+// read lock before they have to be applied to the LRU list.
+const promotionQueueSize = 256
+
+// lookupShared serves a plain hit or miss under the read lock, so
+// read-heavy workloads scale across cores. Instead of moving the entry, the
+// hit is queued and applied the next time the exclusive lock is taken.
+// done is false if the caller has to fall back to the exclusive path
+// (expired entry, sliding expiry, lazily loaded value).
+func (c *LRUCache) lookupShared(key string) (val interface{}, found bool, err error, done bool) {
+
+	if c.sliding {
+		return nil, false, nil, false
+	}
+
+	if c.strict {
+		c.checkReentrant()
+	}
+	c.mu.RLock()
+
+	if c.closed {
+		// Let the exclusive path report it.
+		c.mu.RUnlock()
+		return nil, false, nil, false
+	}
+
+	element, ok := c.cache[key]
+	if !ok {
+		c.mu.RUnlock()
+		c.misses.Add(1)
+		return nil, false, nil, true
+	}
+	entry := element.Value.(*CacheEntry)
+	now := c.clock.Now()
+	if _, lazy := entry.Value.(*lazyValue); lazy || entry.expired(now) {
+		c.mu.RUnlock()
+		return nil, false, nil, false
+	}
+	c.recordHit(entry, now)
+	entry.touch(now)
+	val = plain(entry)
+	queued := c.queuePromotion(element)
+	c.mu.RUnlock()
+
+	if !queued {
+		// Queue is full: apply the pending promotions now. The element
+		// may have been removed and reused in between.
+		c.lock()
+		if c.cache[key] == element {
+			c.promote(element)
+		}
+		c.unlock()
+	}
+	c.hits.Add(1)
+	return val, true, nil, true
+
+}
+
+// queuePromotion records a hit observed under the read lock.
+// It returns false if the queue is full.
+func (c *LRUCache) queuePromotion(element *list.Element) bool {
+
+	if c.policy == PolicySIEVE {
+		// SIEVE only sets the visited bit, which is safe under the read lock.
+		atomic.StoreUint32(&element.Value.(*CacheEntry).visited, 1)
+		return true
+	}
+
+	select {
+	case c.promotions <- element:
+		return true
+	default:
+		return false
+	}
+
+}
+
+// lock acquires the exclusive lock and applies queued promotions, so the
+// LRU order is up to date for whatever the caller is about to do.
+func (c *LRUCache) lock() {
+
+	if c.strict {
+		c.checkReentrant()
+	}
+	c.mu.Lock()
+	for {
+		select {
+		case element := <-c.promotions:
+			// Removals take the lock first, so queued elements are live.
+			c.promote(element)
+		default:
+			return
+		}
+	}
+
+}
@@ -0,0 +1,56 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ---------------------- Runtime configuration ----------------------
+
+// SetDefaultTTL changes the TTL of entries written from now on;
+// NoExpiration means they do not expire. Cached entries keep their expiry, and namespaces
+// keep the TTL they were created with (see Namespace.SetDefaultTTL).
+// Strict mode panics on a negative TTL.
+func (c *LRUCache) SetDefaultTTL(ttl time.Duration) {
+
+	if ttl < 0 && c.strict {
+		panic(fmt.Sprintf("lrucache: TTL must not be negative, got %v", ttl))
+	}
+
+	c.lock()
+	defer c.unlock()
+
+	c.ttl = ttl
+
+}
+
+// DefaultTTL returns the TTL of newly written entries.
+func (c *LRUCache) DefaultTTL() time.Duration {
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.ttl
+
+}
+
+// SetCleanupInterval restarts the background cleanup with a new interval.
+// The next run is one interval from now.
+func (c *LRUCache) SetCleanupInterval(interval time.Duration) error {
+
+	if interval <= 0 {
+		return errors.New("lrucache: cleanup interval must be positive")
+	}
+
+	select {
+	case c.cleanupCh <- interval:
+		return nil
+	case <-c.stopCh:
+		return errCleanupStopped
+	}
+
+}
@@ -0,0 +1,348 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+// Package replication broadcasts Set and Delete operations of a cache to
+// its peers over a message transport such as NATS. Peers either drop the
+// key (InvalidateOnly) or store the replicated value (ReplicateValues);
+// concurrent writes are resolved last-write-wins by timestamp.
+package replication
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/georghagn/nexcache/lrucache"
+)
+
+// ErrEventsLost is reported when local events were dropped because Run
+// fell behind; see WithBuffer.
+var ErrEventsLost = errors.New("replication: local events lost")
+
+// opFlush asks the peers to drop their whole cache.
+const opFlush = "flush"
+
+// Transport carries messages between peers. A NATS connection can
+// implement it with Publish and a channel subscription on subject.
+type Transport interface {
+	Publish(ctx context.Context, subject string, payload []byte) error
+	// Subscribe delivers the payloads published on subject until ctx is done.
+	Subscribe(ctx context.Context, subject string) (<-chan []byte, error)
+}
+
+// Mode selects what peers do with a remote write.
+type Mode int
+
+const (
+	// InvalidateOnly drops the key on peers; they reload it on demand.
+	InvalidateOnly Mode = iota
+
+	// ReplicateValues sends the value along, so peers can serve it right
+	// away. Values travel as JSON and must be encodable with encoding/json.
+	ReplicateValues
+)
+
+// Stats reports replication activity.
+type Stats struct {
+	Sent    uint64        // local operations published
+	Applied uint64        // remote operations applied
+	Stale   uint64        // remote operations ignored as older than the local state
+	Lag     time.Duration // age of the most recently applied remote operation
+	MaxLag  time.Duration // largest lag observed
+}
+
+// message is the payload sent over the transport.
+type message struct {
+	Origin    string          `json:"origin"`
+	Op        string          `json:"op"`
+	Key       string          `json:"key"`
+	Value     json.RawMessage `json:"value,omitempty"`
+	ExpiresAt time.Time       `json:"expiresAt"`
+	Time      int64           `json:"ts"` // Unix nanoseconds of the write
+}
+
+// Replicator connects one cache to its peers.
+type Replicator struct {
+	cache   *lrucache.LRUCache
+	tr      Transport
+	subject string
+	mode    Mode
+	origin  string
+	buffer  int
+	horizon time.Duration
+	onError func(err error)
+
+	mu       sync.Mutex
+	versions map[string]int64 // last write time per key, for last-write-wins
+	stats    Stats
+
+	// Only used by the goroutine running Run.
+	local   <-chan lrucache.Event
+	pending []lrucache.Event // local events taken while applying a remote one
+}
+
+// Option configures a Replicator.
+type Option func(*Replicator)
+
+// WithMode selects InvalidateOnly (default) or ReplicateValues.
+func WithMode(mode Mode) Option {
+	return func(r *Replicator) {
+		r.mode = mode
+	}
+}
+
+// WithOrigin sets the id that marks this peer's own messages. By default
+// a random id is used.
+func WithOrigin(id string) Option {
+	return func(r *Replicator) {
+		r.origin = id
+	}
+}
+
+// WithBuffer sets how many local events may be queued before they are
+// dropped (default 1024); see LRUCache.Subscribe. When events are lost,
+// the peers cannot be brought up to date key by key, so the replicator
+// reports ErrEventsLost and asks all peers to clear their caches; they
+// reload the keys on demand.
+func WithBuffer(size int) Option {
+	return func(r *Replicator) {
+		r.buffer = size
+	}
+}
+
+// WithHorizon sets how long write times are remembered for
+// last-write-wins (default 5 minutes). Remote operations delayed by more
+// than the horizon may overwrite newer local writes. Zero or less keeps
+// them forever.
+func WithHorizon(d time.Duration) Option {
+	return func(r *Replicator) {
+		r.horizon = d
+	}
+}
+
+// WithErrorHandler receives transport, encoding and decoding errors and
+// ErrEventsLost.
+func WithErrorHandler(fn func(err error)) Option {
+	return func(r *Replicator) {
+		r.onError = fn
+	}
+}
+
+// New creates a replicator for cache that communicates on subject.
+func New(cache *lrucache.LRUCache, tr Transport, subject string, opts ...Option) *Replicator {
+
+	r := &Replicator{
+		cache:    cache,
+		tr:       tr,
+		subject:  subject,
+		buffer:   1024,
+		horizon:  5 * time.Minute,
+		versions: make(map[string]int64),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.origin == "" {
+		var id [8]byte
+		rand.Read(id[:])
+		r.origin = hex.EncodeToString(id[:])
+	}
+	return r
+
+}
+
+// Stats returns the current replication counters.
+func (r *Replicator) Stats() Stats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.stats
+}
+
+// Run replicates until ctx is done. Only Set and Delete are broadcast;
+// values filled by loaders and replicated values are not. After lost
+// local events the peers are flushed, see WithBuffer.
+func (r *Replicator) Run(ctx context.Context) error {
+
+	remote, err := r.tr.Subscribe(ctx, r.subject)
+	if err != nil {
+		return err
+	}
+	local, cancel := r.cache.Subscribe(r.buffer)
+	defer cancel()
+	r.local = local
+
+	var pruneC <-chan time.Time
+	if r.horizon > 0 {
+		prune := time.NewTicker(r.horizon)
+		defer prune.Stop()
+		pruneC = prune.C
+	}
+
+	for {
+		select {
+		case event := <-local:
+			r.send(ctx, event)
+		case payload, ok := <-remote:
+			if !ok {
+				return ctx.Err()
+			}
+			r.receive(payload)
+			for _, event := range r.pending {
+				r.send(ctx, event)
+			}
+			r.pending = r.pending[:0]
+		case <-pruneC:
+			r.prune()
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+}
+
+func (r *Replicator) send(ctx context.Context, event lrucache.Event) {
+
+	if event.Type == lrucache.EventLost {
+		r.report(fmt.Errorf("%w: %v events, flushing peers", ErrEventsLost, event.Value))
+		r.publish(ctx, message{Origin: r.origin, Op: opFlush, Time: event.Time.UnixNano()})
+		return
+	}
+	if event.Type != lrucache.EventSet && event.Type != lrucache.EventDelete {
+		return
+	}
+
+	msg := message{Origin: r.origin, Op: string(event.Type), Key: event.Key, Time: event.Time.UnixNano()}
+	if r.mode == ReplicateValues && event.Type == lrucache.EventSet {
+		value, err := json.Marshal(event.Value)
+		if err != nil {
+			r.report(err)
+			return
+		}
+		msg.Value = value
+		msg.ExpiresAt = event.ExpiresAt
+	}
+
+	r.mu.Lock()
+	r.record(event)
+	r.stats.Sent++
+	r.mu.Unlock()
+
+	r.publish(ctx, msg)
+
+}
+
+func (r *Replicator) publish(ctx context.Context, msg message) {
+
+	payload, err := json.Marshal(msg)
+	if err == nil {
+		err = r.tr.Publish(ctx, r.subject, payload)
+	}
+	r.report(err)
+
+}
+
+func (r *Replicator) receive(payload []byte) {
+
+	var msg message
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		r.report(err)
+		return
+	}
+	if msg.Origin == r.origin {
+		return
+	}
+	if msg.Op == opFlush {
+		r.cache.Clear()
+		return
+	}
+
+	// The version check runs with the cache locked, so no local write can
+	// slip in between the check and applying the remote operation.
+	newer := func() bool {
+		r.drain()
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		if msg.Time <= r.versions[msg.Key] {
+			r.stats.Stale++
+			return false
+		}
+		r.versions[msg.Key] = msg.Time
+		r.stats.Applied++
+		r.stats.Lag = time.Since(time.Unix(0, msg.Time))
+		r.stats.MaxLag = max(r.stats.MaxLag, r.stats.Lag)
+		return true
+	}
+
+	if msg.Op == string(lrucache.EventSet) && msg.Value != nil {
+		var value interface{}
+		if err := json.Unmarshal(msg.Value, &value); err != nil {
+			r.report(err)
+			return
+		}
+		r.cache.ReplicateIf(msg.Key, value, msg.ExpiresAt, newer)
+		return
+	}
+	r.cache.InvalidateIf(msg.Key, newer)
+
+}
+
+// drain takes the local events queued so far and records their write
+// times; they are sent after the remote operation. Called with the cache
+// locked, when every completed local write has been queued.
+func (r *Replicator) drain() {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for {
+		select {
+		case event, ok := <-r.local:
+			if !ok {
+				return
+			}
+			r.record(event)
+			r.pending = append(r.pending, event)
+		default:
+			return
+		}
+	}
+
+}
+
+// record remembers the write time of a local Set or Delete. Must be
+// called with r.mu held.
+func (r *Replicator) record(event lrucache.Event) {
+	if event.Type != lrucache.EventSet && event.Type != lrucache.EventDelete {
+		return
+	}
+	if ts := event.Time.UnixNano(); ts > r.versions[event.Key] {
+		r.versions[event.Key] = ts
+	}
+}
+
+// prune forgets write times older than the horizon.
+func (r *Replicator) prune() {
+
+	cutoff := time.Now().Add(-r.horizon).UnixNano()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key, ts := range r.versions {
+		if ts < cutoff {
+			delete(r.versions, key)
+		}
+	}
+
+}
+
+func (r *Replicator) report(err error) {
+	if err != nil && r.onError != nil {
+		r.onError(err)
+	}
+}
@@ -0,0 +1,221 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package replication
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/georghagn/nexcache/lrucache"
+)
+
+// bus is an in-memory Transport shared by several peers.
+type bus struct {
+	mu   sync.Mutex
+	subs []chan []byte
+}
+
+// peer is one peer's view of the bus. Publish blocks while hold is open.
+type peer struct {
+	bus        *bus
+	hold       chan struct{}
+	publishing chan struct{}
+}
+
+func (b *bus) peer() *peer {
+	return &peer{bus: b, publishing: make(chan struct{}, 1)}
+}
+
+func (p *peer) Publish(ctx context.Context, subject string, payload []byte) error {
+
+	select {
+	case p.publishing <- struct{}{}:
+	default:
+	}
+	if p.hold != nil {
+		<-p.hold
+	}
+
+	p.bus.mu.Lock()
+	defer p.bus.mu.Unlock()
+
+	for _, ch := range p.bus.subs {
+		ch <- payload
+	}
+	return nil
+
+}
+
+func (p *peer) Subscribe(ctx context.Context, subject string) (<-chan []byte, error) {
+
+	ch := make(chan []byte, 1024)
+	p.bus.mu.Lock()
+	p.bus.subs = append(p.bus.subs, ch)
+	p.bus.mu.Unlock()
+	return ch, nil
+
+}
+
+func (b *bus) subscribers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subs)
+}
+
+// run starts r and returns once it is subscribed to the bus.
+func run(t *testing.T, ctx context.Context, b *bus, r *Replicator) {
+
+	t.Helper()
+	before := b.subscribers()
+	go r.Run(ctx)
+	waitFor(t, "subscription", func() bool { return b.subscribers() > before })
+
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+}
+
+// When local events overflow the buffer, the peers are flushed and the
+// loss is reported instead of the peers silently diverging.
+func TestLostEventsFlushPeers(t *testing.T) {
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	b := &bus{}
+	a := lrucache.New(100, time.Hour, time.Hour)
+	defer a.Close()
+	other := lrucache.New(100, time.Hour, time.Hour)
+	defer other.Close()
+	other.Set("stale", 1)
+
+	var mu sync.Mutex
+	var errs []error
+	pa := b.peer()
+	pa.hold = make(chan struct{})
+	ra := New(a, pa, "s", WithBuffer(1), WithErrorHandler(func(err error) {
+		mu.Lock()
+		errs = append(errs, err)
+		mu.Unlock()
+	}))
+	run(t, ctx, b, New(other, b.peer(), "s"))
+	run(t, ctx, b, ra)
+
+	a.Set("k0", 0)
+	<-pa.publishing // Run is stuck sending k0
+	for _, key := range []string{"k1", "k2", "k3", "k4"} {
+		a.Set(key, 1)
+	}
+	close(pa.hold)
+	waitFor(t, "buffer drained", func() bool { return ra.Stats().Sent >= 2 })
+	a.Set("k5", 1) // carries the EventLost
+
+	waitFor(t, "peer flush", func() bool { return !other.Contains("stale") })
+	mu.Lock()
+	defer mu.Unlock()
+	lost := false
+	for _, err := range errs {
+		lost = lost || errors.Is(err, ErrEventsLost)
+	}
+	if !lost {
+		t.Errorf("errors %v do not report ErrEventsLost", errs)
+	}
+
+}
+
+func TestInvalidateOnly(t *testing.T) {
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	b := &bus{}
+	a := lrucache.New(10, time.Hour, time.Hour)
+	defer a.Close()
+	other := lrucache.New(10, time.Hour, time.Hour)
+	defer other.Close()
+	a.Set("gone", "old")
+	other.Set("k", "old")
+	other.Set("gone", "old")
+
+	ra := New(a, b.peer(), "s")
+	run(t, ctx, b, ra)
+	run(t, ctx, b, New(other, b.peer(), "s"))
+
+	a.Set("k", "new")
+	a.Delete("gone")
+	waitFor(t, "invalidation", func() bool { return !other.Contains("k") && !other.Contains("gone") })
+	if a.Contains("gone") || !a.Contains("k") {
+		t.Error("own messages changed the local cache")
+	}
+	if s := ra.Stats(); s.Sent != 2 {
+		t.Errorf("Sent = %d, want 2", s.Sent)
+	}
+
+}
+
+// Replicated values are stored with their expiry and not sent on again.
+func TestReplicateValues(t *testing.T) {
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	b := &bus{}
+	a := lrucache.New(10, time.Hour, time.Hour)
+	defer a.Close()
+	other := lrucache.New(10, time.Hour, time.Hour)
+	defer other.Close()
+
+	rother := New(other, b.peer(), "s", WithMode(ReplicateValues))
+	run(t, ctx, b, New(a, b.peer(), "s", WithMode(ReplicateValues)))
+	run(t, ctx, b, rother)
+
+	a.SetWithTTL("k", "v", time.Minute)
+	waitFor(t, "replication", func() bool { return other.Contains("k") })
+	_, want, _ := a.GetWithExpiry("k")
+	if val, expiry, _ := other.GetWithExpiry("k"); val != "v" || !expiry.Equal(want) {
+		t.Errorf("replica = %v (expires %v), want v (expires %v)", val, expiry, want)
+	}
+	if s := rother.Stats(); s.Applied != 1 || s.Sent != 0 {
+		t.Errorf("replica stats = %+v, want one applied and nothing sent", s)
+	}
+
+}
+
+// Remote operations older than the last known write of a key are ignored.
+func TestLastWriteWins(t *testing.T) {
+
+	c := lrucache.New(10, time.Hour, time.Hour)
+	defer c.Close()
+	r := New(c, nil, "s", WithMode(ReplicateValues), WithOrigin("self"))
+
+	send := func(origin string, ts int64, value string) {
+		payload, _ := json.Marshal(message{Origin: origin, Op: "set", Key: "k", Value: json.RawMessage(`"` + value + `"`), Time: ts})
+		r.receive(payload)
+	}
+	send("peer", 2, "newer")
+	send("peer", 1, "older")
+	send("self", 3, "own")
+
+	if val, _ := c.Get("k"); val != "newer" {
+		t.Errorf("k = %v, want newer", val)
+	}
+	if s := r.Stats(); s.Applied != 1 || s.Stale != 1 {
+		t.Errorf("stats = %+v, want one applied and one stale", s)
+	}
+
+}
@@ -0,0 +1,51 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"fmt"
+)
+
+// ---------------------- Resize ----------------------
+
+// Resize changes the capacity at runtime. When shrinking, entries are
+// evicted right away in eviction order, with the usual OnEvict callbacks
+// and events, until the cache fits; pinned entries stay. Since at most
+// capacity entries can be pinned, the least recently used pinned entries
+// beyond the new capacity are unpinned first. Growing only affects future
+// inserts. Strict mode panics on a capacity below 1.
+func (c *LRUCache) Resize(newCapacity int) {
+
+	if newCapacity <= 0 && c.strict {
+		panic(fmt.Sprintf("lrucache: capacity must be positive, got %d", newCapacity))
+	}
+
+	c.lock()
+	defer c.unlock()
+
+	if c.closedLocked() {
+		return
+	}
+
+	c.capacity = newCapacity
+	for element := c.list.Back(); element != nil && c.pinned > max(c.capacity, 0); element = element.Prev() {
+		c.unpinLocked(element.Value.(*CacheEntry))
+	}
+	for c.list.Len()-c.pinned > c.capacity {
+		if !c.ejectOldest(EvictCapacity) {
+			break // only pinned entries left
+		}
+	}
+
+}
+
+// Capacity returns the current capacity.
+func (c *LRUCache) Capacity() int {
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.capacity
+
+}
@@ -0,0 +1,82 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ---------------------- Load shedding ----------------------
+
+// ErrLoadShed is returned for low-priority loads that were rejected
+// because the loaders are under pressure.
+var ErrLoadShed = errors.New("lrucache: low-priority load shed")
+
+type priorityKey struct{}
+
+// LowPriority marks loads made with the returned context as low priority.
+// With WithLoadShedding they are rejected first when loaders are under
+// pressure, e.g. for batch jobs sharing a cache with user-facing traffic.
+func LowPriority(ctx context.Context) context.Context {
+	return context.WithValue(ctx, priorityKey{}, true)
+}
+
+func isLowPriority(ctx context.Context) bool {
+	low, _ := ctx.Value(priorityKey{}).(bool)
+	return low
+}
+
+// WithLoadShedding rejects low-priority loads (see LowPriority) with
+// ErrLoadShed while maxInflight loaders are already running or the
+// average loader latency exceeds maxLatency. A zero value disables the
+// respective threshold. Other loads are never shed.
+func WithLoadShedding(maxInflight int, maxLatency time.Duration) Option {
+	return func(c *LRUCache) {
+		c.shedding = true
+		c.shedInflight = maxInflight
+		c.shedLatency = maxLatency
+	}
+}
+
+// shouldShed reports whether a load made with ctx must be rejected.
+func (c *LRUCache) shouldShed(ctx context.Context) bool {
+
+	if !c.shedding || !isLowPriority(ctx) {
+		return false
+	}
+	if c.shedInflight > 0 && c.inflightLoads.Load() >= int64(c.shedInflight) {
+		return true
+	}
+	return c.shedLatency > 0 && time.Duration(c.loadLatency.Load()) > c.shedLatency
+
+}
+
+// trackLoad counts a running loader and returns a function that records
+// its completion. The latency is kept as a moving average.
+func (c *LRUCache) trackLoad() func() {
+
+	if !c.shedding {
+		return func() {}
+	}
+
+	c.inflightLoads.Add(1)
+	start := c.clock.Now()
+	return func() {
+		c.inflightLoads.Add(-1)
+		sample := int64(c.clock.Now().Sub(start))
+		for {
+			old := c.loadLatency.Load()
+			avg := sample
+			if old != 0 {
+				avg = old + (sample-old)/8
+			}
+			if c.loadLatency.CompareAndSwap(old, avg) {
+				return
+			}
+		}
+	}
+
+}
@@ -0,0 +1,68 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/georghagn/nexcache/lrucache"
+)
+
+func TestShedLowPriorityWhileLoadersRun(t *testing.T) {
+
+	c := lrucache.New(10, time.Hour, time.Hour, lrucache.WithLoadShedding(1, 0))
+	defer c.Close()
+
+	running := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		c.GetOrLoad("slow", func() (interface{}, error) {
+			close(running)
+			<-release
+			return "v", nil
+		})
+		close(done)
+	}()
+	<-running
+
+	ctx := context.Background()
+	load := func(context.Context) (interface{}, error) { return "v", nil }
+	if _, err := c.GetOrLoadContext(lrucache.LowPriority(ctx), "low", load); err != lrucache.ErrLoadShed {
+		t.Errorf("low-priority load = %v, want ErrLoadShed", err)
+	}
+	if _, err := c.GetOrLoadContext(ctx, "normal", load); err != nil {
+		t.Errorf("normal load = %v, must never be shed", err)
+	}
+	close(release)
+	<-done
+	if _, err := c.GetOrLoadContext(lrucache.LowPriority(ctx), "low", load); err != nil {
+		t.Errorf("low-priority load after the pressure = %v", err)
+	}
+
+}
+
+// Loader latency is measured on the cache clock.
+func TestShedLowPriorityOnLatency(t *testing.T) {
+
+	clock := newClock()
+	c := lrucache.New(10, time.Hour, time.Hour, lrucache.WithClock(clock), lrucache.WithLoadShedding(0, time.Second))
+	defer c.Close()
+
+	c.GetOrLoad("slow", func() (interface{}, error) {
+		clock.Advance(5 * time.Second)
+		return "v", nil
+	})
+
+	low := lrucache.LowPriority(context.Background())
+	_, err := c.GetOrLoadMultiContext(low, []string{"a", "b"}, func(context.Context, []string) (map[string]interface{}, error) {
+		return nil, nil
+	})
+	if err != lrucache.ErrLoadShed {
+		t.Errorf("low-priority batch after slow loads = %v, want ErrLoadShed", err)
+	}
+
+}
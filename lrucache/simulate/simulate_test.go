@@ -0,0 +1,108 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package simulate
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// exactLRU replays accesses through an LRU cache of the given capacity
+// and returns its hit ratio.
+func exactLRU(accesses []string, capacity int) float64 {
+
+	var order []string // most recent last
+	hits := 0
+	for _, key := range accesses {
+		pos := -1
+		for i, k := range order {
+			if k == key {
+				pos = i
+				break
+			}
+		}
+		if pos >= 0 {
+			hits++
+			order = append(order[:pos], order[pos+1:]...)
+		} else if len(order) == capacity {
+			order = order[1:]
+		}
+		order = append(order, key)
+	}
+	return float64(hits) / float64(len(accesses))
+
+}
+
+// A cyclic scan over n keys misses in every LRU cache smaller than n and
+// hits after the first round in one of n.
+func TestCyclicScan(t *testing.T) {
+
+	sim := New(1)
+	for round := 0; round < 10; round++ {
+		for i := 0; i < 100; i++ {
+			sim.Access(fmt.Sprint("k", i))
+		}
+	}
+
+	ratios := sim.HitRatios(99, 100, 1000)
+	if ratios[0] != 0 || ratios[1] != 0.9 || ratios[2] != 0.9 {
+		t.Errorf("hit ratios = %v, want [0 0.9 0.9]", ratios)
+	}
+	if s := sim.Stats(); s.Accesses != 1000 || s.Sampled != 1000 || s.DistinctKeys != 100 {
+		t.Errorf("stats = %+v", s)
+	}
+
+}
+
+// With every key tracked, the projection is the exact curve, also across
+// the renumbering of logical times.
+func TestExactWithFullRate(t *testing.T) {
+
+	rng := rand.New(rand.NewSource(1))
+	zipf := rand.NewZipf(rng, 1.2, 1, 300)
+	accesses := make([]string, 5000)
+	for i := range accesses {
+		accesses[i] = fmt.Sprint("k", zipf.Uint64())
+	}
+
+	sim := New(1)
+	for _, key := range accesses {
+		sim.Access(key)
+	}
+	for _, capacity := range []int{1, 10, 50, 200} {
+		want := exactLRU(accesses, capacity)
+		if got := sim.HitRatio(capacity); math.Abs(got-want) > 1e-9 {
+			t.Errorf("capacity %d: hit ratio %v, want %v", capacity, got, want)
+		}
+	}
+
+}
+
+func TestSampledEstimate(t *testing.T) {
+
+	rng := rand.New(rand.NewSource(2))
+	sim := New(0.1)
+	for i := 0; i < 200000; i++ {
+		sim.Access(fmt.Sprint("k", rng.Intn(20000)))
+	}
+
+	// Uniform accesses over 20000 keys hit with the share of keys held.
+	if got := sim.HitRatio(10000); math.Abs(got-0.5) > 0.05 {
+		t.Errorf("hit ratio at half the keys = %v, want about 0.5", got)
+	}
+	if s := sim.Stats(); s.Sampled == 0 || s.Sampled == s.Accesses || math.Abs(float64(s.DistinctKeys)-20000) > 2000 {
+		t.Errorf("stats = %+v, want about 20000 distinct keys from a sample", s)
+	}
+
+}
+
+func TestEmpty(t *testing.T) {
+
+	if got := New(0.5).HitRatios(10, 100); got[0] != 0 || got[1] != 0 {
+		t.Errorf("hit ratios without accesses = %v", got)
+	}
+
+}
@@ -0,0 +1,82 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"time"
+
+	"github.com/georghagn/nexcache/lrucache/internal/list"
+)
+
+// ---------------------- Sliding expiration ----------------------
+
+// WithSlidingTTL enables idle-based expiry: every hit (Get, GetOrLoad,
+// GetMulti) pushes the expiry of the entry forward by its TTL.
+func WithSlidingTTL(enabled bool) Option {
+	return func(c *LRUCache) {
+		c.sliding = enabled
+	}
+}
+
+// Touch extends the expiry of key by its TTL without reading the value and
+// reports whether a non-expired entry was found. It works regardless of
+// WithSlidingTTL and does not change the LRU position.
+func (c *LRUCache) Touch(key string) bool {
+
+	c.lock()
+	defer c.unlock()
+
+	if c.closedLocked() {
+		return false
+	}
+
+	element, found := c.cache[key]
+	if !found {
+		return false
+	}
+	entry := element.Value.(*CacheEntry)
+	now := c.clock.Now()
+	if entry.expired(now) {
+		return false
+	}
+	c.renew(entry, now)
+	entry.touch(now)
+	return true
+
+}
+
+// access handles a cache hit: the entry is promoted and, in sliding mode,
+// its expiry renewed. Must be called with c.mu held.
+func (c *LRUCache) access(element *list.Element) {
+
+	c.promote(element)
+	entry := element.Value.(*CacheEntry)
+	if c.analytics != nil {
+		c.recordHit(entry, c.clock.Now())
+	}
+	if c.sliding {
+		c.renew(entry, c.clock.Now())
+	}
+	if entry.idle > 0 {
+		entry.touch(c.clock.Now())
+	}
+
+}
+
+// renew sets the expiry of entry to now plus its TTL.
+// Entries without expiry (see Persist) are left alone.
+func (c *LRUCache) renew(entry *CacheEntry, now time.Time) {
+
+	if entry.ExpiresAt.IsZero() {
+		return
+	}
+	ttl := entry.ttl
+	if ttl == 0 {
+		ttl = c.ttl
+	}
+	c.preserveForForks(entry.Key)
+	entry.ExpiresAt = c.deadline(now, ttl)
+	c.schedule(entry)
+
+}
@@ -0,0 +1,138 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ---------------------- Snapshot validation ----------------------
+
+// ErrSnapshotLimit is returned when a snapshot exceeds one of its
+// SnapshotLimits.
+var ErrSnapshotLimit = errors.New("lrucache: snapshot exceeds a limit")
+
+// SnapshotLimits bound what Load, LoadFromFile, LoadFromFileLazy and
+// ReadSnapshot accept, so a damaged or hostile snapshot is refused with an
+// error instead of exhausting memory. Zero fields mean no limit.
+type SnapshotLimits struct {
+	// MaxEntries is the number of entries in a snapshot. Load still
+	// skips entries beyond the capacity; this limit stops reading.
+	MaxEntries int
+
+	// MaxKeyLen is the length of a key in bytes.
+	MaxKeyLen int
+
+	// MaxRecordSize is the encoded size of one entry in bytes, which
+	// bounds the size of a value. It is checked before the record is
+	// read. Headerless snapshots of old versions have no records and are
+	// not checked.
+	MaxRecordSize int
+
+	// MaxTTL is how far an expiry time may lie after the save time of the
+	// snapshot (or the load time, if the snapshot does not record it).
+	MaxTTL time.Duration
+
+	// AllowDuplicates accepts keys that occur more than once; the last
+	// occurrence wins. Snapshots written by Save never contain them.
+	AllowDuplicates bool
+}
+
+// defaultSnapshotLimits apply unless WithSnapshotLimits is used.
+var defaultSnapshotLimits = SnapshotLimits{
+	MaxKeyLen:     64 << 10,
+	MaxRecordSize: 64 << 20,
+}
+
+// WithSnapshotLimits replaces the default limits for loading snapshots
+// (keys up to 64 KiB, entries up to 64 MiB encoded, no duplicate keys).
+// Independent of the limits, expiry times before 1970 are refused.
+func WithSnapshotLimits(limits SnapshotLimits) Option {
+	return func(c *LRUCache) {
+		c.snapLimits = limits
+	}
+}
+
+// SnapshotError describes an entry that failed validation. Err matches
+// ErrSnapshotLimit or ErrCorruptSnapshot.
+type SnapshotError struct {
+	Entry int // position in the snapshot, starting at 0
+	Key   string
+	Err   error
+}
+
+func (e *SnapshotError) Error() string {
+	return fmt.Sprintf("%v (entry %d, key %.64q)", e.Err, e.Entry, e.Key)
+}
+
+func (e *SnapshotError) Unwrap() error {
+	return e.Err
+}
+
+// checkedStream validates the entries of a snapshot as they are read.
+type checkedStream struct {
+	entryStream
+	limits SnapshotLimits
+	ref    time.Time // reference time of MaxTTL
+	count  int
+	seen   map[string]struct{}
+}
+
+func (c *LRUCache) checked(stream entryStream, savedAt time.Time) entryStream {
+
+	s := &checkedStream{entryStream: stream, limits: c.snapLimits, ref: savedAt}
+	if s.ref.IsZero() {
+		s.ref = c.clock.Now()
+	}
+	if !s.limits.AllowDuplicates {
+		s.seen = make(map[string]struct{})
+	}
+	return s
+
+}
+
+func (s *checkedStream) next(v interface{}) error {
+
+	if err := s.entryStream.next(v); err != nil {
+		return err
+	}
+
+	var key string
+	var expiresAt time.Time
+	switch entry := v.(type) {
+	case *CacheEntry:
+		key, expiresAt = entry.Key, entry.ExpiresAt
+	case *lazyEntry:
+		key, expiresAt = entry.Key, entry.ExpiresAt
+	}
+
+	entry := s.count
+	s.count++
+	fail := func(base error, format string, args ...interface{}) error {
+		return &SnapshotError{Entry: entry, Key: key, Err: fmt.Errorf("%w: "+format, append([]interface{}{base}, args...)...)}
+	}
+
+	if max := s.limits.MaxEntries; max > 0 && s.count > max {
+		return fail(ErrSnapshotLimit, "more than %d entries", max)
+	}
+	if max := s.limits.MaxKeyLen; max > 0 && len(key) > max {
+		return fail(ErrSnapshotLimit, "key of %d bytes, at most %d", len(key), max)
+	}
+	if !expiresAt.IsZero() && expiresAt.Before(time.Unix(0, 0)) {
+		return fail(ErrCorruptSnapshot, "expiry %v before 1970", expiresAt)
+	}
+	if max := s.limits.MaxTTL; max > 0 && expiresAt.Sub(s.ref) > max {
+		return fail(ErrSnapshotLimit, "expiry %v more than %v after %v", expiresAt, max, s.ref)
+	}
+	if s.seen != nil {
+		if _, dup := s.seen[key]; dup {
+			return fail(ErrCorruptSnapshot, "duplicate key")
+		}
+		s.seen[key] = struct{}{}
+	}
+	return nil
+
+}
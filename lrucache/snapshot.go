@@ -0,0 +1,357 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"time"
+)
+
+// ---------------------- Snapshot format ----------------------
+
+// A snapshot starts with a fixed header:
+//
+//	magic "NXCSNAP" | version (1 byte) | entry count (uint64) | saved at (int64)
+//
+// followed by one record per entry, most recently used first:
+//
+//	payload length (uint32) | CRC-32C of the payload (uint32) | payload
+//
+// Each payload is one entry encoded with the configured codec, so
+// snapshots are written and read entry by entry. The record order is the
+// eviction order, which Load restores. The save time is in Unix
+// nanoseconds and used by WithTTLRebase. All integers
+// are big endian. With WithEncryption the whole snapshot is encrypted.
+const (
+	snapMagic     = "NXCSNAP"
+	snapVersion   = 2
+	snapHeaderLen = len(snapMagic) + 1 + 8 + 8
+)
+
+// ErrCorruptSnapshot is returned when a snapshot is truncated, a checksum
+// does not match or the number of entries differs from the header.
+var ErrCorruptSnapshot = errors.New("lrucache: corrupt snapshot")
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// writeSnapshot streams all entries to w in MRU→LRU order, encrypted if
+// configured. Must be called with c.mu held.
+func (c *LRUCache) writeSnapshot(w io.Writer) error {
+
+	return c.encodeSnapshot(w, c.list.Len(), c.clock.Now(), func(fn func(entry CacheEntry) error) error {
+		for element := c.list.Front(); element != nil; element = element.Next() {
+			if err := fn(*element.Value.(*CacheEntry)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+}
+
+// writeEntries writes entries copied by copyEntriesLocked. It does not
+// need c.mu.
+func (c *LRUCache) writeEntries(w io.Writer, entries []CacheEntry) error {
+
+	return c.encodeSnapshot(w, len(entries), c.clock.Now(), func(fn func(entry CacheEntry) error) error {
+		for _, entry := range entries {
+			if err := fn(entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+}
+
+// encodeSnapshot writes count entries produced by walk as a snapshot
+// saved at savedAt.
+func (c *LRUCache) encodeSnapshot(w io.Writer, count int, savedAt time.Time, walk func(fn func(entry CacheEntry) error) error) error {
+
+	return c.sealTo(w, func(w io.Writer) error {
+		bw := bufio.NewWriter(w)
+
+		header := make([]byte, 0, snapHeaderLen)
+		header = append(header, snapMagic...)
+		header = append(header, snapVersion)
+		header = binary.BigEndian.AppendUint64(header, uint64(count))
+		header = binary.BigEndian.AppendUint64(header, uint64(savedAt.UnixNano()))
+		if _, err := bw.Write(header); err != nil {
+			return err
+		}
+
+		var record bytes.Buffer
+		var prefix [8]byte
+		err := walk(func(entry CacheEntry) error {
+			entry.Value = plain(&entry)
+			if c.codec == JSONCodec {
+				value, err := tagType(entry.Value)
+				if err != nil {
+					return err
+				}
+				entry.Value = value
+			}
+
+			record.Reset()
+			if err := c.codec.NewEncoder(&record).Encode(entry); err != nil {
+				return err
+			}
+			binary.BigEndian.PutUint32(prefix[:4], uint32(record.Len()))
+			binary.BigEndian.PutUint32(prefix[4:], crc32.Checksum(record.Bytes(), castagnoli))
+			if _, err := bw.Write(prefix[:]); err != nil {
+				return err
+			}
+			_, err := bw.Write(record.Bytes())
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		return bw.Flush()
+	})
+
+}
+
+// ---------------------- Concurrent snapshots ----------------------
+
+// WithConcurrentSnapshots lets Save, SaveToFile, auto-save and graceful
+// persistence hold the lock only while the entries are copied; encoding,
+// encryption and writing run without it. Values are shared, not copied,
+// so the copy is cheap, but it needs memory for one entry header per
+// entry during the save. The snapshot is consistent as of the copy. WAL
+// compaction and the final snapshot of Close still write under the lock.
+func WithConcurrentSnapshots() Option {
+	return func(c *LRUCache) {
+		c.concurrentSnapshots = true
+	}
+}
+
+// copyEntriesLocked copies all entries in MRU→LRU order.
+// Must be called with c.mu held.
+func (c *LRUCache) copyEntriesLocked() []CacheEntry {
+
+	entries := make([]CacheEntry, 0, c.list.Len())
+	for element := c.list.Front(); element != nil; element = element.Next() {
+		entries = append(entries, *element.Value.(*CacheEntry))
+	}
+	return entries
+
+}
+
+// snapshotFileLocked writes a snapshot to filename. Must be called with
+// c.mu held; with WithConcurrentSnapshots, c.mu is released while the
+// file is written and held again on return.
+func (c *LRUCache) snapshotFileLocked(filename string) error {
+
+	if !c.concurrentSnapshots {
+		return c.saveLocked(filename)
+	}
+
+	entries := c.copyEntriesLocked()
+	c.unlock()
+	defer c.lock()
+
+	return writeFileAtomic(filename, func(w io.Writer) error {
+		return c.writeEntries(w, entries)
+	})
+
+}
+
+// entryStream yields the entries of a snapshot one at a time. next decodes
+// the following entry into v and returns io.EOF after the last one.
+type entryStream interface {
+	next(v interface{}) error
+}
+
+// readSnapshot decrypts r and checks the snapshot header. It returns the
+// save time, which is zero for snapshots that do not record it. Snapshots
+// without a header (written by versions before the format existed) are
+// read as a plain encoded slice, so they can still be loaded and then
+// saved anew.
+func (c *LRUCache) readSnapshot(r io.Reader) (entryStream, time.Time, error) {
+
+	r, err := c.openSnapshot(r)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	br := bufio.NewReader(r)
+	if magic, _ := br.Peek(len(snapMagic)); string(magic) != snapMagic {
+		stream, err := c.legacyStream(br)
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		return c.checked(stream, time.Time{}), time.Time{}, nil
+	}
+
+	// Version 1 headers end after the entry count.
+	const v1Len = len(snapMagic) + 1 + 8
+	var header [snapHeaderLen]byte
+	if _, err := io.ReadFull(br, header[:v1Len]); err != nil {
+		return nil, time.Time{}, fmt.Errorf("%w: truncated header", ErrCorruptSnapshot)
+	}
+	version := header[len(snapMagic)]
+	if version < 1 || version > snapVersion {
+		return nil, time.Time{}, fmt.Errorf("lrucache: unsupported snapshot version %d", version)
+	}
+	count := binary.BigEndian.Uint64(header[len(snapMagic)+1:])
+	if max := c.snapLimits.MaxEntries; max > 0 && count > uint64(max) {
+		return nil, time.Time{}, fmt.Errorf("%w: %d entries, at most %d", ErrSnapshotLimit, count, max)
+	}
+
+	var savedAt time.Time
+	if version >= 2 {
+		if _, err := io.ReadFull(br, header[v1Len:]); err != nil {
+			return nil, time.Time{}, fmt.Errorf("%w: truncated header", ErrCorruptSnapshot)
+		}
+		savedAt = time.Unix(0, int64(binary.BigEndian.Uint64(header[v1Len:])))
+	}
+	stream := &recordStream{codec: c.codec, r: br, remaining: count, maxSize: c.snapLimits.MaxRecordSize}
+	return c.checked(stream, savedAt), savedAt, nil
+
+}
+
+// nextEntry reads the following entry of stream into entry. With the JSON
+// codec, values of registered types are restored, see RegisterType.
+func (c *LRUCache) nextEntry(stream entryStream, entry *CacheEntry) error {
+
+	if c.codec != JSONCodec {
+		return stream.next(entry)
+	}
+
+	var raw lazyEntry
+	if err := stream.next(&raw); err != nil {
+		return err
+	}
+	value, err := decodeValue(raw.Value)
+	if err != nil {
+		return err
+	}
+	*entry = CacheEntry{Key: raw.Key, Value: value, ExpiresAt: raw.ExpiresAt, Meta: raw.Meta}
+	return nil
+
+}
+
+// recordStream reads the records of a snapshot and verifies each checksum.
+type recordStream struct {
+	codec     Codec
+	r         *bufio.Reader
+	remaining uint64
+	maxSize   int
+	buf       bytes.Buffer
+}
+
+func (s *recordStream) next(v interface{}) error {
+
+	if s.remaining == 0 {
+		if _, err := s.r.Peek(1); err == nil {
+			return fmt.Errorf("%w: more entries than announced", ErrCorruptSnapshot)
+		} else if err != io.EOF {
+			return err
+		}
+		return io.EOF
+	}
+
+	var prefix [8]byte
+	if _, err := io.ReadFull(s.r, prefix[:]); err != nil {
+		return s.truncated(err)
+	}
+	size := binary.BigEndian.Uint32(prefix[:4])
+	sum := binary.BigEndian.Uint32(prefix[4:])
+	if s.maxSize > 0 && int64(size) > int64(s.maxSize) {
+		return fmt.Errorf("%w: entry of %d bytes, at most %d", ErrSnapshotLimit, size, s.maxSize)
+	}
+
+	// CopyN instead of a buffer of the announced size: a corrupt length
+	// must not trigger a huge allocation.
+	s.buf.Reset()
+	if _, err := io.CopyN(&s.buf, s.r, int64(size)); err != nil {
+		return s.truncated(err)
+	}
+	if crc32.Checksum(s.buf.Bytes(), castagnoli) != sum {
+		return fmt.Errorf("%w: checksum mismatch", ErrCorruptSnapshot)
+	}
+	s.remaining--
+	return s.codec.NewDecoder(&s.buf).Decode(v)
+
+}
+
+func (s *recordStream) truncated(err error) error {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return fmt.Errorf("%w: %d entries missing", ErrCorruptSnapshot, s.remaining)
+	}
+	return err
+}
+
+// ---------------------- Headerless snapshots ----------------------
+
+// legacyStream reads a headerless snapshot. JSON arrays are decoded element
+// by element; other codecs need the whole slice.
+func (c *LRUCache) legacyStream(r io.Reader) (entryStream, error) {
+
+	if c.codec != JSONCodec {
+		var entries []CacheEntry
+		if err := c.codec.NewDecoder(r).Decode(&entries); err != nil {
+			return nil, err
+		}
+		return &sliceStream{entries: entries}, nil
+	}
+
+	dec := json.NewDecoder(r)
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if token == nil {
+		// An empty cache was written as null.
+		return &sliceStream{}, nil
+	}
+	if token != json.Delim('[') {
+		return nil, fmt.Errorf("%w: not a snapshot", ErrCorruptSnapshot)
+	}
+	return &jsonArrayStream{dec: dec}, nil
+
+}
+
+type jsonArrayStream struct {
+	dec *json.Decoder
+}
+
+func (s *jsonArrayStream) next(v interface{}) error {
+
+	if !s.dec.More() {
+		if _, err := s.dec.Token(); err != nil {
+			return err
+		}
+		return io.EOF
+	}
+	return s.dec.Decode(v)
+
+}
+
+type sliceStream struct {
+	entries []CacheEntry
+}
+
+func (s *sliceStream) next(v interface{}) error {
+
+	if len(s.entries) == 0 {
+		return io.EOF
+	}
+	entry, ok := v.(*CacheEntry)
+	if !ok {
+		return errors.New("lrucache: snapshot requires the JSON codec")
+	}
+	*entry = s.entries[0]
+	s.entries = s.entries[1:]
+	return nil
+
+}
@@ -0,0 +1,133 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache_test
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/georghagn/nexcache/lrucache"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+
+	for name, codec := range map[string]lrucache.Codec{"json": lrucache.JSONCodec, "gob": lrucache.GobCodec} {
+		t.Run(name, func(t *testing.T) {
+
+			clock := newClock()
+			src := lrucache.New(10, time.Minute, time.Hour, lrucache.WithClock(clock), lrucache.WithCodec(codec))
+			defer src.Close()
+			src.Set("a", "1")
+			src.SetWithTTL("b", "2", time.Hour)
+			src.SetWithMeta("c", "3", map[string]string{"owner": "x"})
+			src.SetWithTTL("d", "4", lrucache.NoExpiration)
+
+			var buf bytes.Buffer
+			if err := src.Save(&buf); err != nil {
+				t.Fatal(err)
+			}
+			dst := lrucache.New(10, time.Minute, time.Hour, lrucache.WithClock(clock), lrucache.WithCodec(codec))
+			defer dst.Close()
+			if err := dst.Load(&buf); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, key := range []string{"a", "b", "c", "d"} {
+				want, wantExp, _ := src.GetWithExpiry(key)
+				got, gotExp, found := dst.GetWithExpiry(key)
+				if !found || got != want || !gotExp.Equal(wantExp) {
+					t.Errorf("%s = %v (expires %v), want %v (expires %v)", key, got, gotExp, want, wantExp)
+				}
+			}
+			if meta, _ := dst.GetMeta("c"); !reflect.DeepEqual(meta, map[string]string{"owner": "x"}) {
+				t.Errorf("metadata = %v", meta)
+			}
+
+		})
+	}
+
+}
+
+func TestLoadSkipsExpiredEntries(t *testing.T) {
+
+	clock := newClock()
+	src := lrucache.New(10, time.Minute, time.Hour, lrucache.WithClock(clock))
+	defer src.Close()
+	src.SetWithTTL("short", 1, time.Second)
+	src.Set("long", 2)
+
+	path := filepath.Join(t.TempDir(), "snap")
+	if err := src.SaveToFile(path); err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(10 * time.Second)
+
+	dst := lrucache.New(10, time.Minute, time.Hour, lrucache.WithClock(clock))
+	defer dst.Close()
+	dst.Set("live", 3)
+	if err := dst.LoadFromFile(path); err != nil {
+		t.Fatal(err)
+	}
+	if dst.Contains("short") || !dst.Contains("long") {
+		t.Errorf("keys after Load = %v, want only long", dst.Keys())
+	}
+	if dst.Contains("live") {
+		t.Error("LoadReplace kept the live content")
+	}
+
+}
+
+func TestLoadMerge(t *testing.T) {
+
+	src := lrucache.New(10, time.Minute, time.Hour)
+	defer src.Close()
+	src.Set("shared", "snapshot")
+	src.Set("new", "snapshot")
+	var buf bytes.Buffer
+	if err := src.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	snapshot := buf.Bytes()
+
+	for mode, want := range map[lrucache.LoadMode]string{lrucache.LoadMerge: "live", lrucache.LoadMergeOverwrite: "snapshot"} {
+		dst := lrucache.New(10, time.Minute, time.Hour, lrucache.WithLoadMode(mode))
+		dst.Set("shared", "live")
+		if err := dst.Load(bytes.NewReader(snapshot)); err != nil {
+			t.Fatal(err)
+		}
+		if val, _ := dst.Get("shared"); val != want {
+			t.Errorf("mode %d: shared = %v, want %v", mode, val, want)
+		}
+		if !dst.Contains("new") {
+			t.Errorf("mode %d: entry of the snapshot was not added", mode)
+		}
+		dst.Close()
+	}
+
+}
+
+func TestLoadRejectsCorruptSnapshot(t *testing.T) {
+
+	src := lrucache.New(10, time.Minute, time.Hour)
+	defer src.Close()
+	src.Set("key", "a value long enough to be damaged")
+	var buf bytes.Buffer
+	if err := src.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	i := bytes.Index(data, []byte("damaged"))
+	data[i] = 'D'
+
+	dst := lrucache.New(10, time.Minute, time.Hour)
+	defer dst.Close()
+	if err := dst.Load(bytes.NewReader(data)); !errors.Is(err, lrucache.ErrCorruptSnapshot) {
+		t.Errorf("Load of a modified snapshot = %v, want ErrCorruptSnapshot", err)
+	}
+
+}
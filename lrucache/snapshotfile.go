@@ -0,0 +1,68 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"io"
+	"time"
+)
+
+// ---------------------- Offline snapshot access ----------------------
+
+// ReadSnapshot reads a snapshot written by Save or SaveToFile without a
+// cache, for tools that inspect or rewrite snapshot files. fn is called
+// for every entry in the stored order, most recently used first,
+// including expired entries; an error from fn stops reading and is
+// returned. It returns the save time, which is zero for snapshots that do
+// not record it. Only the codec, encryption and snapshot limit options
+// are used. Malformed input is reported as an error, never as a panic;
+// FuzzReadSnapshot checks this for both codecs.
+func ReadSnapshot(r io.Reader, fn func(entry CacheEntry) error, opts ...Option) (time.Time, error) {
+
+	c := offlineCache(opts)
+	stream, savedAt, err := c.readSnapshot(r)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	for {
+		var entry CacheEntry
+		err := c.nextEntry(stream, &entry)
+		if err == io.EOF {
+			return savedAt, nil
+		}
+		if err != nil {
+			return savedAt, err
+		}
+		if err := fn(entry); err != nil {
+			return savedAt, err
+		}
+	}
+
+}
+
+// WriteSnapshot writes entries as a snapshot that records savedAt as its
+// save time, in the format of Save. The order of entries is kept; Load
+// treats the first entry as the most recently used. Only the codec and
+// encryption options are used.
+func WriteSnapshot(w io.Writer, entries []CacheEntry, savedAt time.Time, opts ...Option) error {
+	return offlineCache(opts).encodeSnapshot(w, len(entries), savedAt, func(fn func(entry CacheEntry) error) error {
+		for _, entry := range entries {
+			if err := fn(entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// offlineCache returns a cache that only carries the options for reading
+// and writing snapshots.
+func offlineCache(opts []Option) *LRUCache {
+	c := &LRUCache{codec: JSONCodec, clock: realClock{}, snapLimits: defaultSnapshotLimits}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
@@ -0,0 +1,36 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+// FuzzReadSnapshot feeds arbitrary bytes to ReadSnapshot with both codecs.
+// Malformed input must be reported as an error, never as a panic.
+func FuzzReadSnapshot(f *testing.F) {
+
+	entries := []CacheEntry{
+		{Key: "a", Value: "x"},
+		{Key: "b", Value: 1.5, ExpiresAt: time.Unix(1700000000, 0), Meta: map[string]string{"src": "db"}},
+	}
+	for _, codec := range []Codec{JSONCodec, GobCodec} {
+		var buf bytes.Buffer
+		if err := WriteSnapshot(&buf, entries, time.Unix(1700000000, 0), WithCodec(codec)); err != nil {
+			f.Fatal(err)
+		}
+		f.Add(buf.Bytes())
+	}
+	f.Add([]byte(`{"entries":[{"Key":1}]}`))
+	f.Add([]byte{})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		for _, codec := range []Codec{JSONCodec, GobCodec} {
+			ReadSnapshot(bytes.NewReader(data), func(CacheEntry) error { return nil }, WithCodec(codec))
+		}
+	})
+
+}
@@ -0,0 +1,171 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+// Package sqlcache caches the results of database/sql queries in an
+// LRUCache and invalidates them by table.
+package sqlcache
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
+	"github.com/georghagn/nexcache/lrucache"
+)
+
+// Queryer is implemented by *sql.DB, *sql.Tx and *sql.Conn.
+type Queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// Execer is implemented by *sql.DB, *sql.Tx and *sql.Conn.
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// Result is a fully scanned row set. Callers must not modify it, since
+// the same Result is returned for every cache hit.
+type Result struct {
+	Columns []string
+	Rows    [][]interface{}
+}
+
+// Cache stores query results in an LRUCache.
+type Cache struct {
+	cache *lrucache.LRUCache
+
+	mu  sync.Mutex
+	gen map[string]uint64 // invalidations per table
+}
+
+// New creates a query cache on top of cache.
+func New(cache *lrucache.LRUCache) *Cache {
+	return &Cache{cache: cache, gen: make(map[string]uint64)}
+}
+
+// QueryCached returns the cached result for cacheKey or runs the query,
+// scans all rows and caches them for ttl (the cache default if 0).
+func (c *Cache) QueryCached(ctx context.Context, db Queryer, cacheKey string, ttl time.Duration, query string, args ...interface{}) (*Result, error) {
+	return c.QueryCachedTables(ctx, db, cacheKey, ttl, nil, query, args...)
+}
+
+// QueryCachedTables is like QueryCached and tags the result with the
+// tables it reads, so InvalidateTable and Exec can drop it. A result is
+// not cached if one of its tables was invalidated while the query ran,
+// since it may predate the write.
+func (c *Cache) QueryCachedTables(ctx context.Context, db Queryer, cacheKey string, ttl time.Duration, tables []string, query string, args ...interface{}) (*Result, error) {
+
+	if val, found := c.cache.Get(cacheKey); found {
+		if result, ok := val.(*Result); ok {
+			return result, nil
+		}
+	}
+
+	gens := c.generations(tables)
+	result, err := scan(ctx, db, query, args)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]string, len(tables))
+	for i, table := range tables {
+		tags[i] = tableTag(table)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, table := range tables {
+		if c.gen[table] != gens[i] {
+			return result, nil
+		}
+	}
+	if ttl > 0 {
+		c.cache.SetWithTagsTTL(cacheKey, result, ttl, tags...)
+	} else {
+		c.cache.SetWithTags(cacheKey, result, tags...)
+	}
+	return result, nil
+
+}
+
+// InvalidateTable drops all cached results that read table and returns
+// how many were dropped.
+func (c *Cache) InvalidateTable(table string) int {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.gen[table]++
+	return c.cache.InvalidateTag(tableTag(table))
+
+}
+
+// generations returns the current invalidation counts of tables.
+func (c *Cache) generations(tables []string) []uint64 {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	gens := make([]uint64, len(tables))
+	for i, table := range tables {
+		gens[i] = c.gen[table]
+	}
+	return gens
+
+}
+
+// Exec runs a writing statement and, if it succeeds, invalidates the
+// cached results of the tables it modifies.
+func (c *Cache) Exec(ctx context.Context, db Execer, tables []string, query string, args ...interface{}) (sql.Result, error) {
+
+	res, err := db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	for _, table := range tables {
+		c.InvalidateTable(table)
+	}
+	return res, nil
+
+}
+
+func tableTag(table string) string {
+	return "sqlcache:table:" + table
+}
+
+func scan(ctx context.Context, db Queryer, query string, args []interface{}) (*Result, error) {
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{Columns: columns}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		ptrs := make([]interface{}, len(columns))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, err
+		}
+		for i, v := range values {
+			// Drivers may reuse byte slices after the next call to Next.
+			if b, ok := v.([]byte); ok {
+				values[i] = append([]byte(nil), b...)
+			}
+		}
+		result.Rows = append(result.Rows, values)
+	}
+	return result, rows.Err()
+
+}
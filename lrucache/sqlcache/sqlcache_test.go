@@ -0,0 +1,205 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package sqlcache
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/georghagn/nexcache/lrucache"
+	"github.com/georghagn/nexcache/lrucache/clocktest"
+)
+
+// fakeDriver answers every query with one row holding the number of
+// queries run so far. onQuery, if set, runs while a query executes.
+type fakeDriver struct {
+	mu      sync.Mutex
+	queries int64
+	onQuery func()
+}
+
+var db = &fakeDriver{}
+
+func init() {
+	sql.Register("sqlcache-fake", db)
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) { return fakeConn{d}, nil }
+
+type fakeConn struct{ d *fakeDriver }
+
+func (c fakeConn) Prepare(string) (driver.Stmt, error) { return fakeStmt{c.d}, nil }
+func (fakeConn) Close() error                          { return nil }
+func (fakeConn) Begin() (driver.Tx, error)             { return nil, driver.ErrSkip }
+
+type fakeStmt struct{ d *fakeDriver }
+
+func (fakeStmt) Close() error  { return nil }
+func (fakeStmt) NumInput() int { return -1 }
+
+func (fakeStmt) Exec([]driver.Value) (driver.Result, error) {
+	return driver.RowsAffected(1), nil
+}
+
+func (s fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+
+	s.d.mu.Lock()
+	s.d.queries++
+	n, hook := s.d.queries, s.d.onQuery
+	s.d.mu.Unlock()
+
+	if hook != nil {
+		hook()
+	}
+	return &fakeRows{n: n}, nil
+
+}
+
+type fakeRows struct {
+	n    int64
+	done bool
+}
+
+func (*fakeRows) Columns() []string { return []string{"n"} }
+func (*fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = r.n
+	return nil
+}
+
+func setHook(fn func()) {
+	db.mu.Lock()
+	db.onQuery = fn
+	db.mu.Unlock()
+}
+
+func open(t *testing.T) *sql.DB {
+	t.Helper()
+	conn, err := sql.Open("sqlcache-fake", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestQueryCachedServesFromCache(t *testing.T) {
+
+	conn := open(t)
+	c := lrucache.New(10, time.Hour, time.Hour)
+	defer c.Close()
+	q := New(c)
+	ctx := context.Background()
+
+	first, err := q.QueryCached(ctx, conn, "k", 0, "SELECT n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := q.QueryCached(ctx, conn, "k", 0, "SELECT n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != second {
+		t.Error("second query was not served from the cache")
+	}
+	if len(first.Columns) != 1 || len(first.Rows) != 1 {
+		t.Errorf("result = %+v, want one column and one row", first)
+	}
+
+}
+
+func TestExecInvalidatesTables(t *testing.T) {
+
+	conn := open(t)
+	c := lrucache.New(10, time.Hour, time.Hour)
+	defer c.Close()
+	q := New(c)
+	ctx := context.Background()
+
+	q.QueryCachedTables(ctx, conn, "users", 0, []string{"users"}, "SELECT n")
+	q.QueryCachedTables(ctx, conn, "orders", 0, []string{"orders"}, "SELECT n")
+	if _, err := q.Exec(ctx, conn, []string{"users"}, "UPDATE users"); err != nil {
+		t.Fatal(err)
+	}
+	if c.Contains("users") {
+		t.Error("result of the written table is still cached")
+	}
+	if !c.Contains("orders") {
+		t.Error("result of another table was dropped")
+	}
+
+}
+
+// A result read before a concurrent write to its table must not be
+// cached once the write has invalidated the table.
+func TestInvalidationDuringQueryWins(t *testing.T) {
+
+	conn := open(t)
+	c := lrucache.New(10, time.Hour, time.Hour)
+	defer c.Close()
+	q := New(c)
+	ctx := context.Background()
+
+	setHook(func() { q.InvalidateTable("users") })
+	_, err := q.QueryCachedTables(ctx, conn, "users", 0, []string{"users"}, "SELECT n")
+	setHook(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Contains("users") {
+		t.Error("result predating the invalidation was cached")
+	}
+
+	q.QueryCachedTables(ctx, conn, "users", 0, []string{"users"}, "SELECT n")
+	if !c.Contains("users") {
+		t.Error("result after the invalidation was not cached")
+	}
+
+}
+
+// The result is written once with its TTL, so no reader sees it with the
+// cache default.
+func TestQueryCachedSetsTTLAtOnce(t *testing.T) {
+
+	conn := open(t)
+	clock := clocktest.New(time.Unix(0, 0))
+	c := lrucache.New(10, time.Hour, time.Hour, lrucache.WithClock(clock))
+	defer c.Close()
+	q := New(c)
+
+	events, cancel := c.Subscribe(16)
+	defer cancel()
+
+	if _, err := q.QueryCachedTables(context.Background(), conn, "k", time.Minute, []string{"t"}, "SELECT n"); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+
+	var sets []lrucache.Event
+	for event := range events {
+		if event.Type == lrucache.EventSet {
+			sets = append(sets, event)
+		}
+	}
+	if len(sets) != 1 {
+		t.Fatalf("%d writes, want 1", len(sets))
+	}
+	if want := clock.Now().Add(time.Minute); !sets[0].ExpiresAt.Equal(want) {
+		t.Errorf("written with expiry %v, want %v", sets[0].ExpiresAt, want)
+	}
+	if q.InvalidateTable("t") != 1 {
+		t.Error("result is not tagged with its table")
+	}
+
+}
@@ -0,0 +1,61 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+// ---------------------- Statistics ----------------------
+
+// Stats holds cache counters. Hits and misses are counted by Get,
+// GetOrLoad and their variants; Peek and Contains are not counted.
+// Loads and LoadErrors count loader calls of the GetOrLoad family.
+// CompressedIn and CompressedOut sum the sizes of all values compressed
+// with WithCompression before and after compression.
+type Stats struct {
+	Hits          uint64
+	Misses        uint64
+	Evictions     uint64
+	Loads         uint64
+	LoadErrors    uint64
+	CompressedIn  uint64
+	CompressedOut uint64
+}
+
+// HitRate returns the share of lookups that were hits, between 0 and 1.
+func (s Stats) HitRate() float64 {
+	total := s.Hits + s.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(total)
+}
+
+// CompressionRatio returns CompressedOut / CompressedIn, or 0 if nothing
+// was compressed. Smaller is better.
+func (s Stats) CompressionRatio() float64 {
+	if s.CompressedIn == 0 {
+		return 0
+	}
+	return float64(s.CompressedOut) / float64(s.CompressedIn)
+}
+
+// Stats returns the current counters. Counters are updated without taking
+// the cache lock.
+func (c *LRUCache) Stats() Stats {
+	return Stats{
+		Hits:          c.hits.Load(),
+		Misses:        c.misses.Load(),
+		Evictions:     c.evictions.Load(),
+		Loads:         c.loads.Load(),
+		LoadErrors:    c.loadErrors.Load(),
+		CompressedIn:  c.compressedIn.Load(),
+		CompressedOut: c.compressedOut.Load(),
+	}
+}
+
+// countLoad records the outcome of a loader call.
+func (c *LRUCache) countLoad(err error) {
+	c.loads.Add(1)
+	if err != nil {
+		c.loadErrors.Add(1)
+	}
+}
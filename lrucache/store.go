@@ -0,0 +1,374 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ---------------------- Backing store ----------------------
+
+// ErrNotFound is returned by Store.Load for keys the store does not hold.
+var ErrNotFound = errors.New("lrucache: key not found in store")
+
+// Store is a backing store the cache sits in front of, e.g. a database.
+// Load must return ErrNotFound (possibly wrapped) for unknown keys.
+type Store interface {
+	Load(key string) (interface{}, error)
+	Store(key string, value interface{}) error
+	Delete(key string) error
+}
+
+// WithWriteThrough writes every Set (and every other write) and every
+// Delete synchronously to store before the call returns. Values filled by
+// loaders or read-through are not written back. If the store
+// write fails, the key is dropped from the cache so that readers fall back
+// to the store, and the error is passed to the handler set with
+// WithStoreErrorHandler. Get reads through to the store on a miss.
+func WithWriteThrough(store Store) Option {
+	return func(c *LRUCache) {
+		c.store = store
+	}
+}
+
+// WithWriteBehind queues writes and deletes and flushes them to store in
+// the background every flushInterval, or earlier once queueSize operations
+// are pending. Writers block while the queue is full. Pending operations
+// are flushed on Close. Get reads through to the store on a miss.
+func WithWriteBehind(store Store, queueSize int, flushInterval time.Duration) Option {
+	if queueSize < 1 {
+		queueSize = 1
+	}
+	return func(c *LRUCache) {
+		c.store = store
+		c.behind = &writeBehind{
+			max:      queueSize,
+			interval: flushInterval,
+			wake:     make(chan struct{}, 1),
+		}
+		c.behind.notFull = sync.NewCond(&c.behind.mu)
+	}
+}
+
+// WithMaxBatch limits a write-behind flush to n operations per
+// StoreBatch call; larger flushes are split into several batches in the
+// order of the queue. Without it a flush is written as one batch. Stores
+// that write to several destinations can group the ops of a batch by key
+// in StoreBatch.
+func WithMaxBatch(n int) Option {
+	return func(c *LRUCache) {
+		c.maxBatch = n
+	}
+}
+
+// WithStoreErrorHandler receives errors of the backing store, including
+// those of background write-behind flushes. Without a handler they are
+// dropped.
+func WithStoreErrorHandler(fn func(key string, err error)) Option {
+	return func(c *LRUCache) {
+		c.storeErr = fn
+	}
+}
+
+// Flush writes all queued write-behind operations to the store now.
+func (c *LRUCache) Flush() {
+	if c.behind != nil {
+		c.flushBehind(false)
+	}
+}
+
+// StoreOp is one write of a write-behind batch. Deleted marks a deletion.
+type StoreOp struct {
+	Key     string
+	Value   interface{}
+	Deleted bool
+}
+
+// BatchStore is a Store that applies several writes at once, e.g. in a
+// single database transaction. Write-behind flushes use StoreBatch when
+// the store implements it; an error is reported for every key of the
+// batch.
+type BatchStore interface {
+	Store
+	StoreBatch(ops []StoreOp) error
+}
+
+// storeOp is a store write, numbered in the order the cache changed.
+type storeOp struct {
+	StoreOp
+	seq uint64
+}
+
+// storeKey orders the store writes of one key. A write that has been
+// overtaken by a newer write of the same key is skipped, so the store
+// ends up with the value of the cache even when the goroutines of two
+// writes reach the store in reverse order.
+type storeKey struct {
+	mu      sync.Mutex // serializes store calls for the key
+	latest  uint64     // newest write, guarded by LRUCache.storeMu
+	pending int        // writes not yet done, guarded by LRUCache.storeMu
+}
+
+type writeBehind struct {
+	mu       sync.Mutex
+	notFull  *sync.Cond
+	ops      []storeOp
+	max      int
+	interval time.Duration
+	stopped  bool
+	wake     chan struct{} // signals a full queue to the flusher
+
+	writeMu sync.Mutex // keeps batches in order
+}
+
+// storeSet forwards a write to the store once c.mu is released.
+// Must be called with c.mu held.
+func (c *LRUCache) storeSet(key string, value interface{}) {
+	if c.store != nil && !c.filling && !c.replaying {
+		op := c.track(StoreOp{Key: key, Value: value})
+		c.notify(func() { c.storeWrite(op) })
+	}
+}
+
+// storeDelete forwards a delete to the store once c.mu is released.
+// Must be called with c.mu held.
+func (c *LRUCache) storeDelete(key string) {
+	if c.store != nil {
+		op := c.track(StoreOp{Key: key, Deleted: true})
+		c.notify(func() { c.storeWrite(op) })
+	}
+}
+
+// track numbers op as the newest write of its key. Must be called with
+// c.mu held, so the numbers follow the order of the cache changes.
+func (c *LRUCache) track(op StoreOp) storeOp {
+
+	c.storeMu.Lock()
+	defer c.storeMu.Unlock()
+
+	if c.storeKeys == nil {
+		c.storeKeys = make(map[string]*storeKey)
+	}
+	k, found := c.storeKeys[op.Key]
+	if !found {
+		k = &storeKey{}
+		c.storeKeys[op.Key] = k
+	}
+	c.storeSeq++
+	k.latest = c.storeSeq
+	k.pending++
+	return storeOp{StoreOp: op, seq: c.storeSeq}
+
+}
+
+// current reports whether op is still the newest write of its key.
+func (c *LRUCache) current(op storeOp) bool {
+	c.storeMu.Lock()
+	defer c.storeMu.Unlock()
+	return c.storeKeys[op.Key].latest == op.seq
+}
+
+// done marks op as finished and forgets its key once nothing is pending.
+func (c *LRUCache) done(op storeOp) {
+
+	c.storeMu.Lock()
+	defer c.storeMu.Unlock()
+
+	k := c.storeKeys[op.Key]
+	k.pending--
+	if k.pending == 0 {
+		delete(c.storeKeys, op.Key)
+	}
+
+}
+
+func (c *LRUCache) storeWrite(op storeOp) {
+
+	if c.behind != nil && c.enqueue(op) {
+		return
+	}
+	defer c.done(op)
+
+	c.storeMu.Lock()
+	k := c.storeKeys[op.Key]
+	c.storeMu.Unlock()
+
+	k.mu.Lock()
+	var err error
+	if c.current(op) {
+		err = c.apply(op.StoreOp)
+	}
+	k.mu.Unlock()
+
+	if err != nil && !op.Deleted {
+		// Do not keep a value in the cache that the store rejected, unless
+		// it has been overwritten since. New writes are numbered under
+		// c.mu, so the check holds until the entry is removed.
+		c.lock()
+		if element, found := c.cache[op.Key]; found && c.current(op) {
+			c.removeElement(element)
+			c.logDelete(op.Key)
+		}
+		c.unlock()
+	}
+
+}
+
+// enqueue adds op to the write-behind queue, waiting while it is full.
+// It reports false once the flusher has stopped.
+func (c *LRUCache) enqueue(op storeOp) bool {
+
+	b := c.behind
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for len(b.ops) >= b.max && !b.stopped {
+		select {
+		case b.wake <- struct{}{}:
+		default:
+		}
+		b.notFull.Wait()
+	}
+	if b.stopped {
+		return false
+	}
+	b.ops = append(b.ops, op)
+	return true
+
+}
+
+// apply performs op on the store and reports errors to the handler.
+func (c *LRUCache) apply(op StoreOp) error {
+
+	var err error
+	if op.Deleted {
+		err = c.store.Delete(op.Key)
+	} else {
+		err = c.store.Store(op.Key, op.Value)
+	}
+	if err != nil && c.storeErr != nil {
+		c.storeErr(op.Key, err)
+	}
+	return err
+
+}
+
+// flushBehind writes the queued operations as one batch. Of several writes
+// of a key only the newest is applied. With final set, the queue is closed
+// and later writes go to the store directly.
+func (c *LRUCache) flushBehind(final bool) {
+
+	b := c.behind
+	b.writeMu.Lock()
+	defer b.writeMu.Unlock()
+
+	b.mu.Lock()
+	ops := b.ops
+	b.ops = nil
+	if final {
+		b.stopped = true
+	}
+	b.notFull.Broadcast()
+	b.mu.Unlock()
+
+	batch := make([]StoreOp, 0, len(ops))
+	for _, op := range ops {
+		if c.current(op) {
+			batch = append(batch, op.StoreOp)
+		}
+	}
+	if len(batch) > 0 {
+		c.applyBatch(batch)
+	}
+	for _, op := range ops {
+		c.done(op)
+	}
+
+}
+
+// applyBatch writes batch with StoreBatch if the store supports it, in
+// chunks of at most c.maxBatch operations, and one operation at a time
+// otherwise.
+func (c *LRUCache) applyBatch(batch []StoreOp) {
+
+	bs, ok := c.store.(BatchStore)
+	if !ok {
+		for _, op := range batch {
+			c.apply(op)
+		}
+		return
+	}
+	for len(batch) > 0 {
+		chunk := batch
+		if c.maxBatch > 0 && len(chunk) > c.maxBatch {
+			chunk = chunk[:c.maxBatch]
+		}
+		batch = batch[len(chunk):]
+		if err := bs.StoreBatch(chunk); err != nil && c.storeErr != nil {
+			for _, op := range chunk {
+				c.storeErr(op.Key, err)
+			}
+		}
+	}
+
+}
+
+// runWriteBehind is the background flusher.
+func (c *LRUCache) runWriteBehind(ticker Ticker) {
+
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C():
+			c.flushBehind(false)
+		case <-c.behind.wake:
+			c.flushBehind(false)
+		case <-c.stopCh:
+			c.flushBehind(true)
+			return
+		}
+	}
+
+}
+
+// readThrough loads a missing key from the store and caches it without
+// writing it back. An expired entry is replaced; a live entry written
+// while the store was read wins.
+func (c *LRUCache) readThrough(key string) (interface{}, bool) {
+
+	val, err := c.store.Load(key)
+	if err != nil {
+		if !errors.Is(err, ErrNotFound) && c.storeErr != nil {
+			c.storeErr(key, err)
+		}
+		return nil, false
+	}
+	if c.validator != nil {
+		if err := c.validator(key, val); err != nil {
+			c.logAdmission(key, AdmissionRejectedByValidator, err)
+			return nil, false
+		}
+	}
+
+	c.lock()
+	defer c.unlock()
+
+	if c.closed {
+		return val, true
+	}
+	now := c.clock.Now()
+	if element, found := c.cache[key]; found {
+		if entry := element.Value.(*CacheEntry); !entry.expired(now) {
+			return c.copyOut(hydrate(entry)), true
+		}
+	}
+	c.filling = true
+	c.putLocked(key, val, c.deadline(now, c.ttl))
+	c.filling = false
+	return val, true
+
+}
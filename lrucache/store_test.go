@@ -0,0 +1,316 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache_test
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/georghagn/nexcache/lrucache"
+)
+
+// memStore is an in-memory Store. Store calls run the hook first, if set.
+type memStore struct {
+	mu     sync.Mutex
+	data   map[string]interface{}
+	writes int
+	hook   func(key string, value interface{}) error
+}
+
+func newMemStore() *memStore {
+	return &memStore{data: make(map[string]interface{})}
+}
+
+func (s *memStore) Load(key string) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if v, ok := s.data[key]; ok {
+		return v, nil
+	}
+	return nil, lrucache.ErrNotFound
+}
+
+func (s *memStore) Store(key string, value interface{}) error {
+
+	if s.hook != nil {
+		if err := s.hook(key, value); err != nil {
+			return err
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data[key] = value
+	s.writes++
+	return nil
+
+}
+
+func (s *memStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.data, key)
+	s.writes++
+	return nil
+}
+
+func (s *memStore) get(key string) (interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.data[key]
+	return v, ok
+}
+
+func (s *memStore) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.writes
+}
+
+// batchStore records the batches of write-behind flushes.
+type batchStore struct {
+	*memStore
+	batches [][]lrucache.StoreOp
+}
+
+func (s *batchStore) StoreBatch(ops []lrucache.StoreOp) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.batches = append(s.batches, append([]lrucache.StoreOp(nil), ops...))
+	for _, op := range ops {
+		if op.Deleted {
+			delete(s.data, op.Key)
+		} else {
+			s.data[op.Key] = op.Value
+		}
+	}
+	return nil
+
+}
+
+func (s *batchStore) recorded() [][]lrucache.StoreOp {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.batches
+}
+
+func TestWriteThrough(t *testing.T) {
+
+	store := newMemStore()
+	c := lrucache.New(10, time.Hour, time.Hour, lrucache.WithWriteThrough(store))
+	defer c.Close()
+
+	c.Set("k", "v")
+	if val, _ := store.get("k"); val != "v" {
+		t.Errorf("store has %v after Set, want v", val)
+	}
+	c.Delete("k")
+	if _, found := store.get("k"); found {
+		t.Error("Delete did not reach the store")
+	}
+
+}
+
+func TestWriteThroughFailureDropsKey(t *testing.T) {
+
+	store := newMemStore()
+	failure := errors.New("disk full")
+	store.hook = func(string, interface{}) error { return failure }
+	var reported error
+	c := lrucache.New(10, time.Hour, time.Hour, lrucache.WithWriteThrough(store),
+		lrucache.WithStoreErrorHandler(func(_ string, err error) { reported = err }))
+	defer c.Close()
+
+	c.Set("k", "v")
+	if c.Contains("k") {
+		t.Error("value rejected by the store stayed in the cache")
+	}
+	if reported != failure {
+		t.Errorf("handler got %v, want %v", reported, failure)
+	}
+
+}
+
+// Read-through fills the cache from the store without writing back.
+func TestReadThrough(t *testing.T) {
+
+	store := newMemStore()
+	store.data["k"] = "stored"
+	c := lrucache.New(10, time.Hour, time.Hour, lrucache.WithWriteThrough(store))
+	defer c.Close()
+
+	if val, found := c.Get("k"); !found || val != "stored" {
+		t.Fatalf("Get = %v, %v, want the stored value", val, found)
+	}
+	if !c.Contains("k") {
+		t.Error("value read through was not cached")
+	}
+	if n := store.count(); n != 0 {
+		t.Errorf("read-through wrote %d times to the store", n)
+	}
+
+}
+
+// An expired entry is replaced by the current value of the store.
+func TestReadThroughRefillsExpiredEntry(t *testing.T) {
+
+	clock := newClock()
+	store := newMemStore()
+	c := lrucache.New(10, time.Minute, time.Hour, lrucache.WithClock(clock), lrucache.WithWriteThrough(store))
+	defer c.Close()
+
+	c.Set("k", "old")
+	store.mu.Lock()
+	store.data["k"] = "new"
+	store.mu.Unlock()
+	clock.Advance(2 * time.Minute)
+
+	if val, _ := c.Get("k"); val != "new" {
+		t.Fatalf("Get = %v, want the value of the store", val)
+	}
+	if val, _ := c.Peek("k"); val != "new" {
+		t.Errorf("cache holds %v, want the refilled value", val)
+	}
+
+}
+
+// Two writes of a key that reach the store in reverse order leave the
+// store with the newer value.
+func TestStoreWritesKeepKeyOrder(t *testing.T) {
+
+	store := newMemStore()
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	store.hook = func(_ string, value interface{}) error {
+		if value == "first" {
+			close(entered)
+			<-release
+		}
+		return nil
+	}
+	c := lrucache.New(10, time.Hour, time.Hour, lrucache.WithWriteThrough(store))
+	defer c.Close()
+
+	done := make(chan struct{})
+	go func() {
+		c.Set("k", "first")
+		close(done)
+	}()
+	<-entered
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		close(release)
+	}()
+	c.Set("k", "second")
+	<-done
+
+	if val, _ := store.get("k"); val != "second" {
+		t.Errorf("store has %v, want the newer value", val)
+	}
+	if val, _ := c.Get("k"); val != "second" {
+		t.Errorf("cache has %v, want second", val)
+	}
+
+}
+
+// A flush writes only the newest operation of each key, in queue order.
+func TestWriteBehindCoalesces(t *testing.T) {
+
+	store := &batchStore{memStore: newMemStore()}
+	c := lrucache.New(10, time.Hour, time.Hour, lrucache.WithWriteBehind(store, 100, time.Hour))
+	defer c.Close()
+
+	c.Set("a", 1)
+	c.Set("b", 1)
+	c.Set("a", 2)
+	c.Delete("b")
+	c.Set("c", 1)
+	if n := len(store.recorded()); n != 0 {
+		t.Fatalf("%d batches before Flush, want 0", n)
+	}
+	c.Flush()
+
+	want := [][]lrucache.StoreOp{{
+		{Key: "a", Value: 2},
+		{Key: "b", Deleted: true},
+		{Key: "c", Value: 1},
+	}}
+	if got := store.recorded(); !reflect.DeepEqual(got, want) {
+		t.Errorf("batches = %+v, want %+v", got, want)
+	}
+
+}
+
+func TestWriteBehindMaxBatch(t *testing.T) {
+
+	store := &batchStore{memStore: newMemStore()}
+	c := lrucache.New(10, time.Hour, time.Hour, lrucache.WithWriteBehind(store, 100, time.Hour), lrucache.WithMaxBatch(2))
+	defer c.Close()
+
+	for _, key := range []string{"a", "b", "c", "d", "e"} {
+		c.Set(key, key)
+	}
+	c.Flush()
+
+	var sizes []int
+	var keys []string
+	for _, batch := range store.recorded() {
+		sizes = append(sizes, len(batch))
+		for _, op := range batch {
+			keys = append(keys, op.Key)
+		}
+	}
+	if !reflect.DeepEqual(sizes, []int{2, 2, 1}) || !reflect.DeepEqual(keys, []string{"a", "b", "c", "d", "e"}) {
+		t.Errorf("batches of sizes %v with keys %v, want [2 2 1] in write order", sizes, keys)
+	}
+
+}
+
+func TestWriteBehindFlushesOnTicker(t *testing.T) {
+
+	clock := newClock()
+	store := newMemStore()
+	c := lrucache.New(10, time.Hour, time.Hour, lrucache.WithClock(clock), lrucache.WithWriteBehind(store, 100, time.Second))
+	defer c.Close()
+
+	c.Set("k", "v")
+	if _, found := store.get("k"); found {
+		t.Fatal("write-behind wrote synchronously")
+	}
+	clock.Advance(time.Second)
+	waitFor(t, "flush", func() bool { _, found := store.get("k"); return found })
+
+}
+
+func TestWriteBehindFlushesFullQueue(t *testing.T) {
+
+	store := newMemStore()
+	c := lrucache.New(10, time.Hour, time.Hour, lrucache.WithWriteBehind(store, 2, time.Hour))
+	defer c.Close()
+
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Set("c", 3) // waits until the full queue is flushed
+	waitFor(t, "flush", func() bool { return store.count() >= 2 })
+
+}
+
+func TestCloseFlushesWriteBehind(t *testing.T) {
+
+	store := newMemStore()
+	c := lrucache.New(10, time.Hour, time.Hour, lrucache.WithWriteBehind(store, 100, time.Hour))
+
+	c.Set("k", "v")
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if val, _ := store.get("k"); val != "v" {
+		t.Errorf("store has %v after Close, want v", val)
+	}
+
+}
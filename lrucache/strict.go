@@ -0,0 +1,94 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"strconv"
+	"time"
+)
+
+// ---------------------- Strict mode ----------------------
+
+// WithStrictMode turns common misuse into panics with a clear message
+// instead of silent failures: invalid capacity, TTL or cleanup interval,
+// nil loaders, use of the cache after Close, and calls into the cache from
+// a callback that runs under the cache lock (which would deadlock).
+// Intended for development and tests; the reentrancy check costs a stack
+// inspection per lock acquisition.
+func WithStrictMode() Option {
+	return func(c *LRUCache) {
+		c.strict = true
+	}
+}
+
+// closedLocked reports whether the cache is closed. In strict mode, using
+// a closed cache panics. Must be called with c.mu held.
+func (c *LRUCache) closedLocked() bool {
+	if c.closed && c.strict {
+		panic("lrucache: use of cache after Close")
+	}
+	return c.closed
+}
+
+// checkLoader panics in strict mode if no loader was passed.
+func (c *LRUCache) checkLoader(isNil bool) {
+	if isNil && c.strict {
+		panic("lrucache: nil loader passed to GetOrLoad")
+	}
+}
+
+// checkReentrant panics if the calling goroutine is running a callback
+// under the cache lock and is about to lock the cache again.
+func (c *LRUCache) checkReentrant() {
+	if id := c.callback.Load(); id != 0 && id == goroutineID() {
+		panic("lrucache: cache method called from a callback that runs under the cache lock")
+	}
+}
+
+// runLocked calls an Update callback, recording the goroutine in strict mode.
+// Must be called with c.mu held.
+func (c *LRUCache) runLocked(fn func(old interface{}, exists bool) (interface{}, bool), old interface{}, exists bool) (interface{}, bool) {
+
+	if !c.strict {
+		return fn(old, exists)
+	}
+
+	c.callback.Store(goroutineID())
+	defer c.callback.Store(0)
+	return fn(old, exists)
+
+}
+
+// checkConfig panics on invalid constructor arguments.
+func checkConfig(capacity int, ttl, cleanupInterval time.Duration) {
+
+	if capacity <= 0 {
+		panic(fmt.Sprintf("lrucache: capacity must be positive, got %d", capacity))
+	}
+	if ttl < 0 {
+		panic(fmt.Sprintf("lrucache: TTL must not be negative, got %v", ttl))
+	}
+	if cleanupInterval <= 0 {
+		panic(fmt.Sprintf("lrucache: cleanup interval must be positive, got %v", cleanupInterval))
+	}
+
+}
+
+// goroutineID extracts the id of the current goroutine from its stack
+// header ("goroutine 42 [running]:"). Only used in strict mode.
+func goroutineID() int64 {
+
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i >= 0 {
+		b = b[:i]
+	}
+	id, _ := strconv.ParseInt(string(b), 10, 64)
+	return id
+
+}
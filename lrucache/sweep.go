@@ -0,0 +1,51 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import "time"
+
+// ---------------------- Incremental cleanup ----------------------
+
+// WithCleanupBudget makes the background cleanup incremental. Instead of
+// scanning the whole cache under the lock, each pass examines at most
+// maxEntries entries and runs for at most maxPause, then releases the lock;
+// the next pass resumes where the previous one stopped. A value of 0
+// disables the respective limit. A full sweep then takes several cleanup
+// intervals, so choose the interval accordingly. Expired entries are still
+// never returned by Get; the budget only bounds how long they occupy memory.
+func WithCleanupBudget(maxEntries int, maxPause time.Duration) Option {
+	return func(c *LRUCache) {
+		c.sweepMax = maxEntries
+		c.sweepPause = maxPause
+	}
+}
+
+// sweepLocked runs one budgeted cleanup pass from the sweep position
+// towards the front of the list. Must be called with c.mu held.
+func (c *LRUCache) sweepLocked() {
+
+	start := time.Now()
+	now := c.clock.Now()
+
+	element := c.sweep
+	if element == nil {
+		element = c.list.Back()
+	}
+	for n := 0; element != nil; n++ {
+		if c.sweepMax > 0 && n >= c.sweepMax {
+			break
+		}
+		// Reading the wall clock for every entry would dominate the pass.
+		if c.sweepPause > 0 && n%64 == 63 && time.Since(start) >= c.sweepPause {
+			break
+		}
+		prev := element.Prev()
+		if element.Value.(*CacheEntry).expired(now) {
+			c.expireElement(element)
+		}
+		element = prev
+	}
+	c.sweep = element
+
+}
@@ -0,0 +1,104 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"time"
+)
+
+// ---------------------- Tags ----------------------
+
+// SetWithTags stores the value like Set and attaches tags to the entry,
+// replacing its previous tags. A plain Set on a tagged key keeps its tags.
+// Tags live in memory only; they are not written by SaveToFile or the WAL.
+func (c *LRUCache) SetWithTags(key string, value interface{}, tags ...string) {
+	c.setTagged(key, value, 0, false, tags)
+}
+
+// SetWithTagsTTL is like SetWithTags, but uses ttl instead of the cache's
+// default TTL, like SetWithTTL. Value, TTL and tags are written at once.
+func (c *LRUCache) SetWithTagsTTL(key string, value interface{}, ttl time.Duration, tags ...string) {
+	c.setTagged(key, value, ttl, true, tags)
+}
+
+func (c *LRUCache) setTagged(key string, value interface{}, ttl time.Duration, own bool, tags []string) {
+
+	c.acquireWrite()
+	defer c.releaseWrite()
+
+	c.lock()
+	defer c.unlock()
+
+	if c.closedLocked() {
+		c.logAdmission(key, AdmissionRejectedClosed, nil)
+		return
+	}
+
+	if !own {
+		ttl = c.ttl
+	}
+	entry := c.putLocked(key, value, c.deadline(c.clock.Now(), ttl))
+	if own {
+		entry.ttl = ttl
+	}
+	c.untag(entry)
+	for _, tag := range tags {
+		if c.tags == nil {
+			c.tags = make(map[string]map[string]struct{})
+		}
+		keys, found := c.tags[tag]
+		if !found {
+			keys = make(map[string]struct{})
+			c.tags[tag] = keys
+		}
+		if _, dup := keys[key]; !dup {
+			keys[key] = struct{}{}
+			entry.tags = append(entry.tags, tag)
+		}
+	}
+
+}
+
+// InvalidateTag removes all entries carrying tag and returns how many
+// live entries were removed; entries that had already expired are removed
+// as well but not counted, like in DeleteFunc.
+func (c *LRUCache) InvalidateTag(tag string) int {
+
+	c.lock()
+	defer c.unlock()
+
+	if c.closedLocked() {
+		return 0
+	}
+
+	now := c.clock.Now()
+	keys := c.tags[tag]
+	removed := 0
+	for key := range keys {
+		if element, found := c.cache[key]; found {
+			if !element.Value.(*CacheEntry).expired(now) {
+				removed++
+			}
+			c.removeElement(element)
+			c.logDelete(key)
+		}
+	}
+	delete(c.tags, tag)
+	return removed
+
+}
+
+// untag removes entry from the tag index. Must be called with c.mu held.
+func (c *LRUCache) untag(entry *CacheEntry) {
+
+	for _, tag := range entry.tags {
+		keys := c.tags[tag]
+		delete(keys, entry.Key)
+		if len(keys) == 0 {
+			delete(c.tags, tag)
+		}
+	}
+	entry.tags = nil
+
+}
@@ -0,0 +1,55 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/georghagn/nexcache/lrucache"
+	"github.com/georghagn/nexcache/lrucache/clocktest"
+)
+
+func TestInvalidateTagCountsLiveEntries(t *testing.T) {
+
+	clock := clocktest.New(time.Unix(0, 0))
+	c := lrucache.New(10, time.Hour, time.Hour, lrucache.WithClock(clock))
+	defer c.Close()
+
+	c.SetWithTagsTTL("old", 1, time.Second, "t")
+	c.SetWithTags("live", 2, "t")
+	c.SetWithTags("other", 3, "u")
+	clock.Advance(2 * time.Second)
+
+	if n := c.InvalidateTag("t"); n != 1 {
+		t.Errorf("InvalidateTag = %d, want 1 live entry", n)
+	}
+	if c.Contains("live") {
+		t.Error("tagged entry survived")
+	}
+	if !c.Contains("other") {
+		t.Error("entry with another tag was removed")
+	}
+	if n := c.InvalidateTag("t"); n != 0 {
+		t.Errorf("second InvalidateTag = %d, want 0", n)
+	}
+
+}
+
+func TestSetWithTagsReplacesTags(t *testing.T) {
+
+	c := lrucache.New(10, time.Hour, time.Hour)
+	defer c.Close()
+
+	c.SetWithTags("k", 1, "a")
+	c.SetWithTags("k", 2, "b")
+	if n := c.InvalidateTag("a"); n != 0 {
+		t.Errorf("old tag still removes the entry (%d)", n)
+	}
+	c.Set("k", 3) // a plain Set keeps the tags
+	if n := c.InvalidateTag("b"); n != 1 {
+		t.Errorf("InvalidateTag(b) = %d, want 1", n)
+	}
+
+}
@@ -0,0 +1,171 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+// Package tiered combines an in-memory lrucache.LRUCache (L1) with a
+// slower, larger second level (L2) such as a disk or network store.
+//
+// Values are kept in L2 as a Record with their expiry time, so entries
+// expire in L2 just as in L1; both levels use the clock of L1. The L2
+// store should be dedicated to the tiered cache.
+package tiered
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/georghagn/nexcache/lrucache"
+)
+
+// Record is the form in which values are stored in L2.
+type Record struct {
+	Value     interface{}
+	ExpiresAt time.Time // zero = never expires
+}
+
+func init() {
+	gob.Register(Record{})
+}
+
+// Cache is a two-level cache. Get checks L1, then L2, and promotes L2 hits
+// into L1. Set and Delete apply to both levels.
+type Cache struct {
+	l1      *lrucache.LRUCache
+	l2      lrucache.Store
+	onError func(key string, err error)
+}
+
+// Option configures a Cache.
+type Option func(*Cache)
+
+// WithDemotion writes entries evicted from L1, for capacity or under
+// memory pressure, into L2 with their remaining lifetime, so L2 also holds
+// values that were only ever put into L1 directly. Expired entries are not
+// demoted, and neither are entries removed by Clear, which counts as a
+// deletion. It registers an OnEvictEntry callback on L1, replacing any
+// previous OnEvict or OnEvictEntry callback.
+func WithDemotion() Option {
+	return func(t *Cache) {
+		t.l1.OnEvictEntry(func(entry lrucache.CacheEntry, reason lrucache.EvictReason) {
+			if reason != lrucache.EvictExpired && reason != lrucache.EvictCleared {
+				t.report(entry.Key, t.l2.Store(entry.Key, Record{Value: entry.Value, ExpiresAt: entry.ExpiresAt}))
+			}
+		})
+	}
+}
+
+// WithErrorHandler receives L2 errors that cannot be returned to a caller:
+// failed demotions and failed L2 lookups in Get.
+func WithErrorHandler(fn func(key string, err error)) Option {
+	return func(t *Cache) {
+		t.onError = fn
+	}
+}
+
+// New stacks l1 on top of l2.
+func New(l1 *lrucache.LRUCache, l2 lrucache.Store, opts ...Option) *Cache {
+	t := &Cache{l1: l1, l2: l2}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// Get returns the value from L1, or from L2, in which case it is copied
+// into L1 with its remaining lifetime. Expired L2 entries are deleted.
+func (t *Cache) Get(key string) (interface{}, bool) {
+
+	if val, found := t.l1.Get(key); found {
+		return val, true
+	}
+
+	raw, err := t.l2.Load(key)
+	if err != nil {
+		if !errors.Is(err, lrucache.ErrNotFound) {
+			t.report(key, err)
+		}
+		return nil, false
+	}
+	rec, ok := asRecord(raw)
+	if !ok {
+		t.report(key, errors.New("tiered: L2 value is not a tiered.Record"))
+		return nil, false
+	}
+	if !rec.ExpiresAt.IsZero() && t.l1.Clock().Now().After(rec.ExpiresAt) {
+		t.report(key, t.l2.Delete(key))
+		return nil, false
+	}
+	// Like a loaded value, the promoted copy is not written back to L2.
+	t.l1.Replicate(key, rec.Value, rec.ExpiresAt)
+	return rec.Value, true
+
+}
+
+// Set writes the value to L2 and then to L1, both with the default TTL of
+// L1. If L2 fails, L1 is left unchanged.
+func (t *Cache) Set(key string, value interface{}) error {
+
+	rec := Record{Value: value}
+	ttl := t.l1.DefaultTTL()
+	if ttl != lrucache.NoExpiration {
+		rec.ExpiresAt = t.l1.Clock().Now().Add(ttl)
+	}
+	if err := t.l2.Store(key, rec); err != nil {
+		return err
+	}
+	t.l1.Set(key, value)
+	return nil
+
+}
+
+// Delete removes key from both levels.
+func (t *Cache) Delete(key string) error {
+	t.l1.Delete(key)
+	return t.l2.Delete(key)
+}
+
+// L1 returns the in-memory level.
+func (t *Cache) L1() *lrucache.LRUCache {
+	return t.l1
+}
+
+// Close closes L1. The L2 store is owned by the caller.
+func (t *Cache) Close() error {
+	return t.l1.Close()
+}
+
+// asRecord converts a value loaded from L2 into a Record. Stores that
+// encode as JSON return it as a map.
+func asRecord(raw interface{}) (Record, bool) {
+
+	switch rec := raw.(type) {
+	case Record:
+		return rec, true
+	case *Record:
+		if rec != nil {
+			return *rec, true
+		}
+	case map[string]interface{}:
+		if _, ok := rec["Value"]; !ok {
+			return Record{}, false
+		}
+		data, err := json.Marshal(rec)
+		if err != nil {
+			return Record{}, false
+		}
+		var out Record
+		if err := json.Unmarshal(data, &out); err != nil {
+			return Record{}, false
+		}
+		return out, true
+	}
+	return Record{}, false
+
+}
+
+func (t *Cache) report(key string, err error) {
+	if err != nil && t.onError != nil {
+		t.onError(key, err)
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package tiered
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/georghagn/nexcache/lrucache"
+	"github.com/georghagn/nexcache/lrucache/clocktest"
+)
+
+// memStore is an in-memory L2.
+type memStore struct {
+	mu   sync.Mutex
+	data map[string]interface{}
+}
+
+func newMemStore() *memStore {
+	return &memStore{data: make(map[string]interface{})}
+}
+
+func (s *memStore) Load(key string) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if v, ok := s.data[key]; ok {
+		return v, nil
+	}
+	return nil, lrucache.ErrNotFound
+}
+
+func (s *memStore) Store(key string, value interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data[key] = value
+	return nil
+}
+
+func (s *memStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.data, key)
+	return nil
+}
+
+func (s *memStore) has(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.data[key]
+	return ok
+}
+
+func newClock() *clocktest.Fake {
+	return clocktest.New(time.Unix(1700000000, 0))
+}
+
+func TestGetPromotesFromL2(t *testing.T) {
+
+	clock := newClock()
+	l2 := newMemStore()
+	tc := New(lrucache.New(10, time.Minute, time.Hour, lrucache.WithClock(clock)), l2)
+	defer tc.Close()
+
+	if err := tc.Set("k", "v"); err != nil {
+		t.Fatal(err)
+	}
+	tc.L1().Delete("k")
+
+	if val, found := tc.Get("k"); !found || val != "v" {
+		t.Fatalf("Get = %v, %v, want v from L2", val, found)
+	}
+	if !tc.L1().Contains("k") {
+		t.Error("L2 hit was not promoted into L1")
+	}
+	if ttl, _ := tc.L1().TTL("k"); ttl != time.Minute {
+		t.Errorf("promoted entry has TTL %v, want its remaining 1m", ttl)
+	}
+
+}
+
+// L2 records expire by the clock of L1, so a fake clock drives them too.
+func TestL2ExpiresByL1Clock(t *testing.T) {
+
+	clock := newClock()
+	l2 := newMemStore()
+	tc := New(lrucache.New(10, time.Minute, time.Hour, lrucache.WithClock(clock)), l2)
+	defer tc.Close()
+
+	tc.Set("k", "v")
+	tc.L1().Delete("k")
+	clock.Advance(2 * time.Minute)
+
+	if _, found := tc.Get("k"); found {
+		t.Error("expired L2 record was served")
+	}
+	if l2.has("k") {
+		t.Error("expired L2 record was not deleted")
+	}
+
+}
+
+func TestDeleteRemovesBothLevels(t *testing.T) {
+
+	l2 := newMemStore()
+	tc := New(lrucache.New(10, time.Minute, time.Hour), l2)
+	defer tc.Close()
+
+	tc.Set("k", "v")
+	if err := tc.Delete("k"); err != nil {
+		t.Fatal(err)
+	}
+	if tc.L1().Contains("k") || l2.has("k") {
+		t.Error("Delete left the key in one of the levels")
+	}
+
+}
+
+func TestDemotionOnCapacityEviction(t *testing.T) {
+
+	l2 := newMemStore()
+	l1 := lrucache.New(1, time.Minute, time.Hour)
+	tc := New(l1, l2, WithDemotion())
+	defer tc.Close()
+
+	l1.Set("a", 1)
+	l1.Set("b", 2)
+
+	if !l2.has("a") {
+		t.Fatal("entry evicted for capacity was not demoted")
+	}
+	if val, found := tc.Get("a"); !found || val != 1 {
+		t.Errorf("Get of demoted entry = %v, %v", val, found)
+	}
+
+}
+
+func TestDemotionUnderMemoryPressure(t *testing.T) {
+
+	clock := newClock()
+	l2 := newMemStore()
+	l1 := lrucache.New(10, time.Minute, time.Hour, lrucache.WithClock(clock),
+		lrucache.WithMemoryGauge(func() uint64 { return 1 << 40 }))
+	tc := New(l1, l2, WithDemotion())
+	defer tc.Close()
+
+	l1.Set("a", 1)
+	if err := l1.EnableMemoryPressure(1, 1, time.Second); err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(time.Second)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !l2.has("a") {
+		if time.Now().After(deadline) {
+			t.Fatal("entry evicted under memory pressure was not demoted")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+}
+
+func TestNoDemotionOnExpiryOrClear(t *testing.T) {
+
+	clock := newClock()
+	l2 := newMemStore()
+	l1 := lrucache.New(10, time.Minute, time.Hour, lrucache.WithClock(clock))
+	tc := New(l1, l2, WithDemotion())
+	defer tc.Close()
+
+	l1.Set("expired", 1)
+	l1.Set("cleared", 2)
+	clock.Advance(2 * time.Minute)
+	l1.Get("expired")
+	l1.Set("cleared", 2)
+	l1.Clear()
+
+	if l2.has("expired") || l2.has("cleared") {
+		t.Error("expired or cleared entries were demoted")
+	}
+
+}
+
+// Stores that encode records as JSON hand them back as maps.
+func TestAsRecordFromJSONMap(t *testing.T) {
+
+	rec, ok := asRecord(map[string]interface{}{"Value": "v", "ExpiresAt": "2026-01-01T00:00:00Z"})
+	if !ok || rec.Value != "v" || rec.ExpiresAt.Year() != 2026 {
+		t.Errorf("asRecord = %+v, %v", rec, ok)
+	}
+	if _, ok := asRecord("plain"); ok {
+		t.Error("a plain value was taken for a Record")
+	}
+
+}
@@ -0,0 +1,109 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// ---------------------- Type registry ----------------------
+
+// registry maps registered names to concrete value types and back.
+var registry = struct {
+	sync.RWMutex
+	byName map[string]reflect.Type
+	byType map[reflect.Type]string
+}{
+	byName: make(map[string]reflect.Type),
+	byType: make(map[reflect.Type]string),
+}
+
+// typedValue is the JSON form of a value of a registered type.
+type typedValue struct {
+	Type  string          `json:"$type"`
+	Value json.RawMessage `json:"$value"`
+}
+
+// RegisterType records the concrete type of value under name. Values of
+// that type are then written with their type name to JSON snapshots and
+// the WAL, and restored as the same type instead of
+// map[string]interface{}. Pointer types are restored as pointers. Custom
+// json.Marshaler and json.Unmarshaler implementations of the type are
+// used. The type is also registered with encoding/gob, as GobCodec needs.
+//
+// Like gob.Register, RegisterType is meant for init functions; it panics
+// if name or the type is already registered. As gob does not distinguish
+// T and *T, only one of them can be registered.
+func RegisterType(name string, value interface{}) {
+
+	typ := reflect.TypeOf(value)
+	if typ == nil {
+		panic("lrucache: RegisterType with nil value")
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	if _, dup := registry.byName[name]; dup {
+		panic(fmt.Sprintf("lrucache: type name %q registered twice", name))
+	}
+	if _, dup := registry.byType[typ]; dup {
+		panic(fmt.Sprintf("lrucache: type %v registered twice", typ))
+	}
+	registry.byName[name] = typ
+	registry.byType[typ] = name
+	gob.RegisterName(name, value)
+
+}
+
+// tagType wraps values of registered types for JSON encoding.
+func tagType(value interface{}) (interface{}, error) {
+
+	registry.RLock()
+	name, found := registry.byType[reflect.TypeOf(value)]
+	registry.RUnlock()
+	if !found {
+		return value, nil
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return typedValue{Type: name, Value: raw}, nil
+
+}
+
+// decodeValue decodes a JSON value, restoring registered types.
+func decodeValue(raw json.RawMessage) (interface{}, error) {
+
+	if bytes.HasPrefix(bytes.TrimSpace(raw), []byte(`{"$type"`)) {
+		var tv typedValue
+		if err := json.Unmarshal(raw, &tv); err == nil {
+			registry.RLock()
+			typ, found := registry.byName[tv.Type]
+			registry.RUnlock()
+			if found {
+				ptr := reflect.New(typ)
+				if err := json.Unmarshal(tv.Value, ptr.Interface()); err != nil {
+					return nil, err
+				}
+				return ptr.Elem().Interface(), nil
+			}
+		}
+	}
+
+	var value interface{}
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	err := json.Unmarshal(raw, &value)
+	return value, err
+
+}
@@ -0,0 +1,250 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"time"
+)
+
+// ---------------------- Write-ahead log ----------------------
+
+// walSuffix is appended to the snapshot path to name the log file.
+const walSuffix = ".wal"
+
+const (
+	walOpSet    = "set"
+	walOpDelete = "del"
+)
+
+// walRecord is one line of the write-ahead log.
+type walRecord struct {
+	Op        string            `json:"op"`
+	Key       string            `json:"key"`
+	Value     interface{}       `json:"value,omitempty"`
+	ExpiresAt time.Time         `json:"expiresAt"`
+	Meta      map[string]string `json:"meta,omitempty"`
+}
+
+// EnableWAL appends every Set and Delete to the log file path+".wal".
+// Every compactInterval (and on DisableWAL and Close) the cache is written
+// as a snapshot to path and the log is truncated. After a crash, RecoverWAL
+// restores the snapshot and replays the log, so only writes not yet handed
+// to the operating system are lost.
+//
+// Log records are always JSON encoded, independent of the configured codec.
+func (c *LRUCache) EnableWAL(path string, compactInterval time.Duration) error {
+
+	if path == "" || compactInterval <= 0 {
+		return errors.New("lrucache: WAL needs a path and a positive compaction interval")
+	}
+
+	c.lock()
+	if c.closedLocked() {
+		c.unlock()
+		return ErrClosed
+	}
+	if c.wal != nil {
+		c.unlock()
+		return errors.New("lrucache: WAL is already enabled")
+	}
+	file, err := os.OpenFile(path+walSuffix, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		c.unlock()
+		return err
+	}
+	c.wal = file
+	c.walPath = path
+	c.walErr = nil
+	c.unlock()
+
+	select {
+	case c.walCompactCh <- compactInterval:
+		return nil
+	case <-c.stopCh:
+		return errCleanupStopped
+	}
+
+}
+
+// DisableWAL compacts the log one last time and closes it.
+func (c *LRUCache) DisableWAL() error {
+
+	select {
+	case c.walCompactCh <- 0:
+	case <-c.stopCh:
+	}
+
+	c.lock()
+	defer c.unlock()
+
+	return c.closeWALLocked()
+
+}
+
+// WALError returns the first error that occurred while writing the log
+// or compacting it, or nil.
+func (c *LRUCache) WALError() error {
+
+	c.lock()
+	defer c.unlock()
+
+	return c.walErr
+
+}
+
+// RecoverWAL restores the snapshot at path (if present) and replays the log
+// path+".wal" on top of it. Entries that have expired in the meantime are
+// skipped. Like Load, the replay restores the cache without publishing
+// events or writing to a backing store. It must be called before EnableWAL.
+func (c *LRUCache) RecoverWAL(path string) error {
+
+	if err := c.LoadFromFile(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	file, err := os.Open(path + walSuffix)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	c.lock()
+	defer c.unlock()
+
+	if c.closedLocked() {
+		return ErrClosed
+	}
+	if c.wal != nil {
+		return errors.New("lrucache: RecoverWAL must be called before EnableWAL")
+	}
+
+	c.replaying = true
+	defer func() { c.replaying = false }()
+
+	now := c.clock.Now()
+	dec := json.NewDecoder(file)
+	for {
+		var rec struct {
+			walRecord
+			Value json.RawMessage `json:"value,omitempty"`
+		}
+		err := dec.Decode(&rec)
+		if err == io.EOF || errors.Is(err, io.ErrUnexpectedEOF) {
+			// A torn last record from a crash ends the replay.
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch rec.Op {
+		case walOpSet:
+			if !expiredAt(rec.ExpiresAt, now) {
+				value, err := decodeValue(rec.Value)
+				if err != nil {
+					return err
+				}
+				c.putLocked(rec.Key, value, rec.ExpiresAt).Meta = rec.Meta
+			} else if element, found := c.cache[rec.Key]; found {
+				c.removeElement(element)
+			}
+		case walOpDelete:
+			if element, found := c.cache[rec.Key]; found {
+				c.removeElement(element)
+			}
+		}
+	}
+
+}
+
+// compactWAL writes a snapshot and truncates the log.
+func (c *LRUCache) compactWAL() {
+
+	c.lock()
+	defer c.unlock()
+
+	if c.closed || c.wal == nil {
+		return
+	}
+	c.compactWALLocked()
+
+}
+
+// ---------------------- Helpers ----------------------
+
+func (c *LRUCache) compactWALLocked() {
+
+	if err := c.saveLocked(c.walPath); err != nil {
+		c.setWALError(err)
+		return
+	}
+	if err := c.wal.Truncate(0); err != nil {
+		c.setWALError(err)
+	}
+
+}
+
+func (c *LRUCache) closeWALLocked() error {
+
+	if c.wal == nil {
+		return nil
+	}
+	c.compactWALLocked()
+	if err := c.wal.Close(); err != nil {
+		c.setWALError(err)
+	}
+	c.wal = nil
+	return c.walErr
+
+}
+
+// logSet records a write for the WAL and subscribers.
+func (c *LRUCache) logSet(entry *CacheEntry) {
+	if c.filling {
+		c.publish(EventLoad, entry.Key, entry)
+	} else {
+		c.publish(EventSet, entry.Key, entry)
+	}
+	if c.wal != nil {
+		c.appendWAL(walRecord{Op: walOpSet, Key: entry.Key, Value: plain(entry), ExpiresAt: entry.ExpiresAt, Meta: entry.Meta})
+	}
+}
+
+// logDelete records a deletion for the WAL and subscribers.
+func (c *LRUCache) logDelete(key string) {
+	c.publish(EventDelete, key, nil)
+	c.walDelete(key)
+}
+
+func (c *LRUCache) walDelete(key string) {
+	if c.wal != nil {
+		c.appendWAL(walRecord{Op: walOpDelete, Key: key})
+	}
+}
+
+func (c *LRUCache) appendWAL(rec walRecord) {
+
+	value, err := tagType(rec.Value)
+	if err != nil {
+		c.setWALError(err)
+		return
+	}
+	rec.Value = value
+	if err := json.NewEncoder(c.wal).Encode(rec); err != nil {
+		c.setWALError(err)
+	}
+
+}
+
+func (c *LRUCache) setWALError(err error) {
+	if c.walErr == nil {
+		c.walErr = err
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/georghagn/nexcache/lrucache"
+)
+
+// crashedWAL enables the WAL on c, runs write and copies the log as it
+// stands before Close compacts it, as a crash would leave it.
+func crashedWAL(t *testing.T, c *lrucache.LRUCache, write func()) string {
+
+	t.Helper()
+	dir := t.TempDir()
+	live := filepath.Join(dir, "live")
+	if err := c.EnableWAL(live, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	write()
+
+	log, err := os.ReadFile(live + ".wal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	crashed := filepath.Join(dir, "crashed")
+	if err := os.WriteFile(crashed+".wal", log, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return crashed
+
+}
+
+// sampleWAL returns a crashed log that sets a and b and deletes a again.
+func sampleWAL(t *testing.T) string {
+
+	t.Helper()
+	c := lrucache.New(10, time.Hour, time.Hour)
+	defer c.Close()
+	return crashedWAL(t, c, func() {
+		c.Set("a", "1")
+		c.Set("b", "2")
+		c.Delete("a")
+	})
+
+}
+
+func TestRecoverWALReplaysLog(t *testing.T) {
+
+	path := sampleWAL(t)
+	c := lrucache.New(10, time.Hour, time.Hour)
+	defer c.Close()
+
+	if err := c.RecoverWAL(path); err != nil {
+		t.Fatal(err)
+	}
+	if val, found := c.Get("b"); !found || val != "2" {
+		t.Errorf("Get(b) = %v, %v, want 2", val, found)
+	}
+	if c.Contains("a") {
+		t.Error("deleted key was recovered")
+	}
+
+}
+
+// Recovered records are not new writes: they must not reach the backing
+// store or the subscribers again.
+func TestRecoverWALBypassesStoreAndEvents(t *testing.T) {
+
+	path := sampleWAL(t)
+	store := newMemStore()
+	c := lrucache.New(10, time.Hour, time.Hour, lrucache.WithWriteThrough(store))
+	defer c.Close()
+	events, cancel := c.Subscribe(16)
+	defer cancel()
+
+	if err := c.RecoverWAL(path); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+
+	if n := store.count(); n != 0 {
+		t.Errorf("replay wrote %d times to the store, want 0", n)
+	}
+	for event := range events {
+		t.Errorf("replay published %v for %q", event.Type, event.Key)
+	}
+	if !c.Contains("b") {
+		t.Error("record was not recovered")
+	}
+
+}
+
+// Close compacts the log into a snapshot that RecoverWAL restores.
+func TestWALRoundTripAcrossClose(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "cache")
+	c := lrucache.New(10, time.Hour, time.Hour)
+	if err := c.EnableWAL(path, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	c.Set("a", "1")
+	c.SetWithMeta("b", "2", map[string]string{"m": "x"})
+	c.Delete("a")
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r := lrucache.New(10, time.Hour, time.Hour)
+	defer r.Close()
+	if err := r.RecoverWAL(path); err != nil {
+		t.Fatal(err)
+	}
+	if r.Contains("a") || !r.Contains("b") {
+		t.Errorf("recovered keys = %v, want [b]", r.Keys())
+	}
+	if meta, _ := r.GetMeta("b"); meta["m"] != "x" {
+		t.Errorf("recovered metadata = %v", meta)
+	}
+
+}
+
+func TestWALCompactsOnTicker(t *testing.T) {
+
+	clock := newClock()
+	path := filepath.Join(t.TempDir(), "cache")
+	c := lrucache.New(10, time.Hour, time.Hour, lrucache.WithClock(clock))
+	defer c.Close()
+	if err := c.EnableWAL(path, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	c.Set("a", "1")
+
+	clock.Advance(time.Minute)
+	waitFor(t, "compaction", func() bool {
+		info, err := os.Stat(path + ".wal")
+		_, snapErr := os.Stat(path)
+		return err == nil && info.Size() == 0 && snapErr == nil
+	})
+	if err := c.WALError(); err != nil {
+		t.Fatal(err)
+	}
+
+}
+
+// Records that expired while the process was down are not restored.
+func TestRecoverWALSkipsExpired(t *testing.T) {
+
+	clock := newClock()
+	c := lrucache.New(10, time.Hour, time.Hour, lrucache.WithClock(clock))
+	defer c.Close()
+	path := crashedWAL(t, c, func() {
+		c.SetWithTTL("short", "1", time.Second)
+		c.Set("long", "2")
+	})
+
+	clock.Advance(time.Minute)
+	r := lrucache.New(10, time.Hour, time.Hour, lrucache.WithClock(clock))
+	defer r.Close()
+	if err := r.RecoverWAL(path); err != nil {
+		t.Fatal(err)
+	}
+	if r.Contains("short") || !r.Contains("long") {
+		t.Errorf("recovered keys = %v, want [long]", r.Keys())
+	}
+
+}
@@ -0,0 +1,95 @@
+// Copyright 2026 Georg Hagn
+// SPDX-License-Identifier: Apache-2.0
+
+package lrucache
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ---------------------- Warm-up ----------------------
+
+// WarmError reports the keys that could not be loaded by Warm.
+type WarmError struct {
+	Errors map[string]error
+}
+
+func (e *WarmError) Error() string {
+
+	keys := make([]string, 0, len(e.Errors))
+	for key := range e.Errors {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return fmt.Sprintf("lrucache: warm-up failed for %d keys (first %q: %v)", len(keys), keys[0], e.Errors[keys[0]])
+
+}
+
+// Warm loads keys into the cache using up to concurrency parallel loader
+// calls, e.g. at startup so a service does not start cold. Keys already
+// cached are skipped. Failed keys are reported in a *WarmError.
+func (c *LRUCache) Warm(keys []string, loader func(key string) (interface{}, error), concurrency int) error {
+	return c.WarmContext(context.Background(), keys, func(_ context.Context, key string) (interface{}, error) {
+		return loader(key)
+	}, concurrency)
+}
+
+// WarmContext is like Warm, but passes ctx to the loader and stops
+// starting new loads once ctx is done; the remaining keys are reported
+// with ctx.Err().
+func (c *LRUCache) WarmContext(ctx context.Context, keys []string, loader func(ctx context.Context, key string) (interface{}, error), concurrency int) error {
+
+	c.checkLoader(loader == nil)
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var (
+		mu     sync.Mutex
+		errs   = make(map[string]error)
+		wg     sync.WaitGroup
+		queue  = make(chan string)
+		record = func(key string, err error) {
+			mu.Lock()
+			errs[key] = err
+			mu.Unlock()
+		}
+	)
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for key := range queue {
+				_, err := c.GetOrLoadContext(ctx, key, func(ctx context.Context) (interface{}, error) {
+					return loader(ctx, key)
+				})
+				if err != nil {
+					record(key, err)
+				}
+			}
+		}()
+	}
+
+	for i, key := range keys {
+		if ctx.Err() != nil {
+			for _, rest := range keys[i:] {
+				record(rest, ctx.Err())
+			}
+			break
+		}
+		queue <- key
+	}
+	close(queue)
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return &WarmError{Errors: errs}
+	}
+	return nil
+
+}